@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go-devtools/internal/favorites"
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/requirements"
+)
+
+const favoritesSection = "Favorites"
+
+// favoritesMenu caches the Favorites section until favorites.json changes.
+type favoritesMenu struct {
+	root  *menu.Menu
+	tools []modules.Tool
+	stamp string
+	items []menu.Item
+}
+
+func (f *favoritesMenu) Items() []menu.Item {
+	store, err := favorites.Default()
+	if err != nil {
+		return nil
+	}
+	stamp := fileStamp(store.Path())
+	if f.items == nil || stamp != f.stamp {
+		f.items = favoriteItems(f.root, f.tools, store)
+		f.stamp = stamp
+	}
+	return f.items
+}
+
+func favoriteItems(root *menu.Menu, tools []modules.Tool, store *favorites.Store) []menu.Item {
+	paths, err := store.List()
+	if err != nil {
+		return nil
+	}
+
+	items := make([]menu.Item, 0, len(paths))
+	for _, path := range paths {
+		trail, ok := root.Resolve(path)
+		if !ok {
+			continue
+		}
+		target := trail[len(trail)-1]
+		checks := make([]requirements.Check, 0)
+		for _, item := range trail {
+			checks = append(checks, item.Requirements...)
+		}
+
+		// The runner checks Requirements before opening or running the item,
+		// exactly as it does along the original path.
+		item := menu.Item{
+			ID:           trail[0].ID,
+			Section:      favoritesSection,
+			Key:          favorites.Key(path),
+			Label:        favorites.Label(path),
+			Description:  target.Description,
+			Requirements: checks,
+		}
+		if target.NextMenu != nil {
+			item.NextMenu = target.NextMenu
+		} else if target.Run != nil || target.Live != nil {
+			parents := trail[:len(trail)-1]
+			run := func(action func() (string, error)) (string, error) {
+				started := time.Now()
+				out, err := action()
+				recordMenuEvent(tools, menu.Event{
+					Trail:    parents,
+					Item:     target,
+					Started:  started,
+					Duration: time.Since(started),
+					Output:   out,
+					Err:      err,
+				})
+				return out, err
+			}
+			if target.Live != nil {
+				item.Live = func(out io.Writer) (string, error) {
+					return run(func() (string, error) { return target.Live(out) })
+				}
+			} else {
+				item.Run = func() (string, error) { return run(target.Run) }
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// Toggle pins or unpins the selected item; it is the Runner's OnFavorite hook.
+func (f *favoritesMenu) Toggle(trail []menu.Item, item menu.Item) (string, error) {
+	return toggleFavorite(f.root, trail, item)
+}
+
+func toggleFavorite(root *menu.Menu, trail []menu.Item, item menu.Item) (string, error) {
+	store, err := favorites.Default()
+	if err != nil {
+		return "", err
+	}
+
+	var path []string
+	switch {
+	case strings.HasPrefix(item.ID, "history:"):
+		return "Recent entries cannot be pinned; pin the original menu item instead.", nil
+	case len(trail) == 0 && item.Section == favoritesSection:
+		if path, err = pinnedPath(store, item.Key); err != nil {
+			return "", err
+		}
+	default:
+		// Inside a submenu opened from Favorites the trail starts at the
+		// pinned item, so rebuild the path from the module root.
+		if len(trail) > 0 && trail[0].Section == favoritesSection {
+			if path, err = pinnedPath(store, trail[0].Key); err != nil {
+				return "", err
+			}
+			trail = trail[1:]
+		}
+		for _, parent := range trail {
+			path = append(path, parent.Label)
+		}
+		path = append(path, item.Label)
+		if _, ok := root.Resolve(path); !ok {
+			return fmt.Sprintf("%q is generated when its menu opens and cannot be pinned; pin the menu instead.", item.Label), nil
+		}
+	}
+
+	added, err := store.Toggle(path)
+	if err != nil {
+		return "", err
+	}
+	if added {
+		return fmt.Sprintf("Pinned %q to Favorites.", favorites.Label(path)), nil
+	}
+	return fmt.Sprintf("Removed %q from Favorites.", favorites.Label(path)), nil
+}
+
+func pinnedPath(store *favorites.Store, key string) ([]string, error) {
+	paths, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range paths {
+		if favorites.Key(existing) == key {
+			return append([]string{}, existing...), nil
+		}
+	}
+	return nil, fmt.Errorf("pinned item not found in %s", store.Path())
+}
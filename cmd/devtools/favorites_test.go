@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"go-devtools/internal/favorites"
+	"go-devtools/internal/menu"
+	"go-devtools/internal/requirements"
+)
+
+func favoritesRoot(ran *int) *menu.Menu {
+	failing := requirements.Check{Name: "token", Validate: func() error { return errors.New("TOKEN is not set") }}
+	cloud := menu.New("Cloud", []menu.Item{
+		{Label: "AWS / SSO", Run: func() (string, error) { *ran++; return "sso", nil }},
+		{Label: "AWS", NextMenu: menu.New("AWS", []menu.Item{
+			{Label: "SSO", Run: func() (string, error) { *ran++; return "aws sso", nil }},
+		})},
+	})
+	return menu.New("Root", []menu.Item{
+		{ID: "cloud", Label: "Cloud", NextMenu: cloud},
+		{ID: "locked", Label: "Locked", Requirements: []requirements.Check{failing}, NextMenu: menu.New("Locked", []menu.Item{
+			{Label: "Run", Run: func() (string, error) { *ran++; return "ran", nil }},
+		})},
+	})
+}
+
+func TestFavoritesPinUnpinAndResolve(t *testing.T) {
+	t.Setenv("DEVTOOLS_HOME", t.TempDir())
+	t.Setenv("DEVTOOLS_NO_HISTORY", "1")
+	ran := 0
+	root := favoritesRoot(&ran)
+	store, err := favorites.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cloud := root.Items[0]
+	nestedTrail, _ := root.Resolve([]string{"Cloud", "AWS"})
+	for _, pin := range []struct {
+		trail []menu.Item
+		item  menu.Item
+	}{
+		{[]menu.Item{cloud}, cloud.NextMenu.Items[0]},
+		{nestedTrail, nestedTrail[1].NextMenu.Items[0]},
+		{[]menu.Item{root.Items[1]}, root.Items[1].NextMenu.Items[0]},
+	} {
+		if _, err := toggleFavorite(root, pin.trail, pin.item); err != nil {
+			t.Fatalf("toggleFavorite error = %v", err)
+		}
+	}
+
+	items := favoriteItems(root, nil, store)
+	if len(items) != 3 {
+		t.Fatalf("got %d favorites, want 3", len(items))
+	}
+	if items[0].Label != items[1].Label || items[0].Key == items[1].Key {
+		t.Errorf("favorites %q and %q must share a label but not a key", items[0].Key, items[1].Key)
+	}
+	if out, err := items[1].Run(); err != nil || out != "aws sso" {
+		t.Errorf("pinned nested item = %q, %v", out, err)
+	}
+	if len(items[2].Requirements) != 1 {
+		t.Errorf("pinned item carries %d requirements, want the parent's check", len(items[2].Requirements))
+	}
+
+	// Unpinning from the Favorites section removes exactly that path.
+	if msg, err := toggleFavorite(root, nil, items[0]); err != nil || msg != `Removed "Cloud / AWS / SSO" from Favorites.` {
+		t.Fatalf("unpin = %q, %v", msg, err)
+	}
+	paths, _ := store.List()
+	if len(paths) != 2 || favorites.Key(paths[0]) != items[1].Key {
+		t.Errorf("remaining favorites = %q", paths)
+	}
+}
+
+func TestLeadingItemsCannotBePinned(t *testing.T) {
+	t.Setenv("DEVTOOLS_HOME", t.TempDir())
+	ran := 0
+	root := favoritesRoot(&ran)
+	cloud := root.Items[0]
+	generated := menu.Item{Label: "prod-eu", Run: func() (string, error) { return "", nil }}
+	cloud.NextMenu.Leading = func() []menu.Item { return []menu.Item{generated} }
+
+	msg, err := toggleFavorite(root, []menu.Item{cloud}, generated)
+	if err != nil || msg != `"prod-eu" is generated when its menu opens and cannot be pinned; pin the menu instead.` {
+		t.Errorf("toggleFavorite = %q, %v", msg, err)
+	}
+	store, _ := favorites.Default()
+	if paths, _ := store.List(); len(paths) != 0 {
+		t.Errorf("generated item was pinned: %q", paths)
+	}
+}
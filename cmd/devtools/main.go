@@ -1,19 +1,37 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 
 	"go-devtools/internal/cli"
+	"go-devtools/internal/history"
 	"go-devtools/internal/menu"
 	"go-devtools/internal/modules"
 	"go-devtools/internal/modules/authtoken"
+	"go-devtools/internal/modules/certs"
 	"go-devtools/internal/modules/chucknorris"
 	"go-devtools/internal/modules/cloudcli"
+	"go-devtools/internal/modules/convert"
+	"go-devtools/internal/modules/dotenv"
 	"go-devtools/internal/modules/envinfo"
+	"go-devtools/internal/modules/fake"
+	"go-devtools/internal/modules/generate"
+	"go-devtools/internal/modules/hash"
 	"go-devtools/internal/modules/helloworld"
+	"go-devtools/internal/modules/httpclient"
+	"go-devtools/internal/modules/kube"
+	"go-devtools/internal/modules/mockserver"
+	"go-devtools/internal/modules/otp"
+	"go-devtools/internal/modules/ports"
+	"go-devtools/internal/modules/ssh"
 )
 
+const recentLimit = 5
+
 func main() {
 	toolModules := []modules.Tool{
 		helloworld.New(),
@@ -21,18 +39,123 @@ func main() {
 		chucknorris.New(),
 		authtoken.New(),
 		cloudcli.New(),
+		httpclient.New(),
+		mockserver.New(),
+		kube.New(),
+		dotenv.New(),
+		certs.New(),
+		ssh.New(),
+		convert.New(),
+		generate.New(),
+		hash.New(),
+		otp.New(),
+		fake.New(),
+		ports.New(),
 	}
 
 	items := modules.ToMenuItems(toolModules)
 	items = append(items, menu.QuitItem("Exit"))
 	root := menu.New("Developer Tools CLI", items)
+	pinned := &favoritesMenu{root: root, tools: toolModules}
+	recent := &recentMenu{tools: toolModules}
+	root.Leading = func() []menu.Item {
+		return append(append([]menu.Item{}, pinned.Items()...), recent.Items()...)
+	}
 
 	runTUI := func() error {
-		return menu.Run(root)
+		return menu.NewRunner(root).
+			OnAction(func(event menu.Event) {
+				recordMenuEvent(toolModules, event)
+			}).
+			OnFavorite(pinned.Toggle).
+			Run()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	go func() {
+		// Restore the default handler once the action has been asked to
+		// stop, so a second Ctrl+C kills an action that ignores its context.
+		<-ctx.Done()
+		stop()
+	}()
+	err := cli.RunContext(ctx, os.Args[1:], os.Stdout, os.Stderr, toolModules, runTUI)
+	stop()
+	if err != nil {
+		os.Exit(cli.ReportError(os.Stderr, err))
 	}
+}
+
+// recentMenu caches the Recent section and only rebuilds it when the
+// history file changes, since Leading runs on every render.
+type recentMenu struct {
+	tools []modules.Tool
+	stamp string
+	items []menu.Item
+}
+
+func (r *recentMenu) Items() []menu.Item {
+	store, err := history.Default()
+	if err != nil {
+		return nil
+	}
+	stamp := fileStamp(store.Path())
+	if r.items == nil || stamp != r.stamp {
+		r.items = recentItems(r.tools, store)
+		r.stamp = stamp
+	}
+	return r.items
+}
 
-	if err := cli.Run(os.Args[1:], os.Stdout, os.Stderr, toolModules, runTUI); err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
+func fileStamp(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", info.ModTime().UnixNano(), info.Size())
+}
+
+func recentItems(tools []modules.Tool, store *history.Store) []menu.Item {
+	entries, err := store.Recent(recentLimit * 2)
+	if err != nil {
+		return nil
+	}
+
+	items := make([]menu.Item, 0, recentLimit)
+	for _, entry := range entries {
+		if len(items) == recentLimit {
+			break
+		}
+		tool, ok := modules.FindTool(tools, entry.Module)
+		if !ok {
+			continue
+		}
+		action, ok := modules.FindAction(tool, entry.Action)
+		if !ok {
+			continue
+		}
+
+		entry := entry
+		items = append(items, menu.Item{
+			ID:          fmt.Sprintf("history:%d", entry.ID),
+			Section:     "Recent",
+			Label:       fmt.Sprintf("%s / %s", tool.Label(), action.Label),
+			Description: entry.Command(),
+			Run: func() (string, error) {
+				return cli.Replay(context.Background(), io.Discard, tools, entry)
+			},
+		})
+	}
+	return items
+}
+
+func recordMenuEvent(tools []modules.Tool, event menu.Event) {
+	entry, ok := history.FromMenuEvent(tools, event)
+	if !ok {
+		return
+	}
+	store, err := history.Default()
+	if err != nil {
+		return
 	}
+	_, _ = store.Append(entry)
 }
@@ -0,0 +1,32 @@
+package appdir
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const envHome = "DEVTOOLS_HOME"
+
+func Dir() (string, error) {
+	dir := os.Getenv(envHome)
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate config directory (set %s): %w", envHome, err)
+		}
+		dir = filepath.Join(base, "go-devtools")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
+func Path(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-devtools/internal/modules"
+)
+
+func TestActionErrorTreatsFailuresAfterInterruptAsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	killed := errors.New("signal: interrupt")
+	if got := modules.ExitCode(actionError(ctx, killed)); got != modules.ExitActionFailed {
+		t.Errorf("before cancel exit code = %d, want %d", got, modules.ExitActionFailed)
+	}
+
+	cancel()
+	err := actionError(ctx, killed)
+	if got := modules.ExitCode(err); got != modules.ExitCancelled {
+		t.Errorf("after cancel exit code = %d, want %d", got, modules.ExitCancelled)
+	}
+	if !errors.Is(err, killed) {
+		t.Errorf("cancelled error %v does not wrap the action error", err)
+	}
+	if got := modules.ExitCode(actionError(ctx, modules.UsageError("bad"))); got != modules.ExitUsage {
+		t.Errorf("typed error exit code = %d, want %d", got, modules.ExitUsage)
+	}
+}
@@ -1,15 +1,27 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"go-devtools/internal/history"
 	"go-devtools/internal/modules"
+	"go-devtools/internal/scaffold"
 )
 
 func Run(args []string, stdout io.Writer, stderr io.Writer, tools []modules.Tool, runTUI func() error) error {
+	return RunContext(context.Background(), args, stdout, stderr, tools, runTUI)
+}
+
+// RunContext is Run with a context that is passed to the action; main
+// cancels it on SIGINT so interrupted runs exit with ExitCancelled.
+func RunContext(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer, tools []modules.Tool, runTUI func() error) error {
 	if len(args) == 0 {
 		return runTUI()
 	}
@@ -22,11 +34,23 @@ func Run(args []string, stdout io.Writer, stderr io.Writer, tools []modules.Tool
 	case "list":
 		return printList(stdout, tools)
 	case "run":
-		return runAction(stdout, stderr, tools, args[1:])
+		return runAction(ctx, stdout, stderr, tools, args[1:])
+	case "history":
+		return runHistory(ctx, stdout, stderr, tools, args[1:])
+	case "dev":
+		return runDev(stdout, tools, args[1:])
 	default:
 		// Shortcut form: devtools <module-id> <action-id> [args...]
-		return runAction(stdout, stderr, tools, args)
+		return runAction(ctx, stdout, stderr, tools, args)
+	}
+}
+
+func ReportError(stderr io.Writer, err error) int {
+	fmt.Fprintf(stderr, "error: %v\n", err)
+	if modules.KindOf(err) == modules.KindUsage {
+		fmt.Fprintln(stderr, "Run 'devtools help' for usage.")
 	}
+	return modules.ExitCode(err)
 }
 
 func printHelp(stdout io.Writer, tools []modules.Tool, topic []string) error {
@@ -40,20 +64,34 @@ func printHelp(stdout io.Writer, tools []modules.Tool, topic []string) error {
 		fmt.Fprintln(stdout, "  devtools help                 Show this help")
 		fmt.Fprintln(stdout, "  devtools help <module-id>     Show module actions")
 		fmt.Fprintln(stdout, "  devtools help <module-id> <action-id>")
-		fmt.Fprintln(stdout, "  devtools run <module-id> <action-id> [--key value|--key=value|key=value]")
+		fmt.Fprintln(stdout, "  devtools run <module-id> <action-id> [--key value|--key=value|key=value] [-- args...]")
 		fmt.Fprintln(stdout, "  devtools <module-id> <action-id> [args]   Shortcut for run")
+		fmt.Fprintln(stdout, "  devtools history [--search text] [--module id] [--limit n]")
+		fmt.Fprintln(stdout, "  devtools history show|replay <id>")
+		fmt.Fprintln(stdout, "  devtools history clear")
+		fmt.Fprintln(stdout, "  devtools dev new-module <module-id> [--root <repo-dir>]")
 		fmt.Fprintln(stdout, "")
 		fmt.Fprintln(stdout, "Examples:")
 		fmt.Fprintln(stdout, "  devtools run chuck-norris-facts random-fact")
 		fmt.Fprintln(stdout, "  devtools run auth-token-generator userpass-token --username alice --password secret")
 		fmt.Fprintln(stdout, "  devtools help auth-token-generator google-token")
+		fmt.Fprintln(stdout, "  devtools history replay 12")
+		fmt.Fprintln(stdout, "")
+		fmt.Fprintln(stdout, "Exit codes:")
+		fmt.Fprintf(stdout, "  %-3d success\n", modules.ExitOK)
+		fmt.Fprintf(stdout, "  %-3d action failed\n", modules.ExitActionFailed)
+		fmt.Fprintf(stdout, "  %-3d usage error (bad command or flags)\n", modules.ExitUsage)
+		fmt.Fprintf(stdout, "  %-3d unknown module, action or history entry\n", modules.ExitNotFound)
+		fmt.Fprintf(stdout, "  %-3d requirement failed (missing tool or env var)\n", modules.ExitRequirementFailed)
+		fmt.Fprintf(stdout, "  %-3d action timed out\n", modules.ExitTimeout)
+		fmt.Fprintf(stdout, "  %-3d action cancelled\n", modules.ExitCancelled)
 		fmt.Fprintln(stdout, "")
 		return printList(stdout, tools)
 	}
 
 	tool, ok := modules.FindTool(tools, topic[0])
 	if !ok {
-		return fmt.Errorf("unknown module %q", topic[0])
+		return modules.NotFoundError("unknown module %q", topic[0])
 	}
 
 	if len(topic) == 1 {
@@ -64,7 +102,7 @@ func printHelp(stdout io.Writer, tools []modules.Tool, topic []string) error {
 
 	action, ok := modules.FindAction(tool, topic[1])
 	if !ok {
-		return fmt.Errorf("unknown action %q for module %q", topic[1], topic[0])
+		return modules.NotFoundError("unknown action %q for module %q", topic[1], topic[0])
 	}
 
 	fmt.Fprintf(stdout, "Module: %s (%s)\n", tool.Label(), tool.ID())
@@ -102,9 +140,9 @@ func printModuleActions(stdout io.Writer, tool modules.Tool) error {
 	return nil
 }
 
-func runAction(stdout io.Writer, stderr io.Writer, tools []modules.Tool, args []string) error {
+func runAction(runCtx context.Context, stdout io.Writer, stderr io.Writer, tools []modules.Tool, args []string) error {
 	if len(args) < 2 {
-		return fmt.Errorf("usage: devtools run <module-id> <action-id> [--key value|--key=value|key=value]")
+		return modules.UsageError("usage: devtools run <module-id> <action-id> [--key value|--key=value|key=value]")
 	}
 
 	moduleID := args[0]
@@ -113,12 +151,12 @@ func runAction(stdout io.Writer, stderr io.Writer, tools []modules.Tool, args []
 
 	tool, ok := modules.FindTool(tools, moduleID)
 	if !ok {
-		return fmt.Errorf("unknown module %q", moduleID)
+		return modules.NotFoundError("unknown module %q", moduleID)
 	}
 
 	action, ok := modules.FindAction(tool, actionID)
 	if !ok {
-		return fmt.Errorf("unknown action %q for module %q", actionID, moduleID)
+		return modules.NotFoundError("unknown action %q for module %q", actionID, moduleID)
 	}
 
 	if hasHelpFlag(rest) {
@@ -132,7 +170,7 @@ func runAction(stdout io.Writer, stderr io.Writer, tools []modules.Tool, args []
 	}
 
 	if err := modules.ValidateRequirements(tool); err != nil {
-		return fmt.Errorf("requirements failed for module %q: %w", moduleID, err)
+		return modules.RequirementError("requirements failed for module %q: %w", moduleID, err)
 	}
 
 	params, positionals, err := parseArgs(rest)
@@ -140,21 +178,211 @@ func runAction(stdout io.Writer, stderr io.Writer, tools []modules.Tool, args []
 		return err
 	}
 
-	out, err := action.Run(modules.ActionContext{
+	ctx := modules.ActionContext{
 		Params:      params,
 		Positionals: positionals,
-	})
+		Ctx:         runCtx,
+	}
+	started := time.Now()
+	out, err := action.Run(ctx)
+	record(stderr, history.NewEntry(history.SourceCLI, tool.ID(), action, ctx, started, out, err))
+	// Reports that fail a check still return what they found; print it
+	// before the error so the exit code is not the only signal.
+	if out != "" {
+		fmt.Fprintln(stdout, out)
+	}
+	return actionError(runCtx, err)
+}
+
+func Replay(runCtx context.Context, stderr io.Writer, tools []modules.Tool, entry history.Entry) (string, error) {
+	action, ctx, err := history.Resolve(tools, entry)
+	if err != nil {
+		return "", err
+	}
+	ctx.Ctx = runCtx
+	started := time.Now()
+	out, err := action.Run(ctx)
+	record(stderr, history.NewEntry(history.SourceReplay, entry.Module, action, ctx, started, out, err))
+	return out, actionError(runCtx, err)
+}
+
+// actionError classifies an action failure, treating any error returned
+// after the run was interrupted as cancelled even when the action itself
+// did not check its context (for example a child process killed by SIGINT).
+func actionError(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil && !errors.Is(err, ctx.Err()) {
+		err = fmt.Errorf("%w: %w", ctx.Err(), err)
+	}
+	return modules.ActionError(err)
+}
+
+func record(stderr io.Writer, entry history.Entry) {
+	store, err := history.Default()
+	if err == nil {
+		_, err = store.Append(entry)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "warning: failed to record history: %v\n", err)
+	}
+}
+
+func runHistory(ctx context.Context, stdout io.Writer, stderr io.Writer, tools []modules.Tool, args []string) error {
+	store, err := history.Default()
 	if err != nil {
 		return err
 	}
 
-	if out != "" {
-		fmt.Fprintln(stdout, out)
+	params, positionals, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+
+	if len(positionals) == 0 {
+		return printHistory(stdout, store, params)
+	}
+
+	switch positionals[0] {
+	case "clear":
+		if err := store.Clear(); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, "History cleared.")
+		return nil
+	case "show", "replay":
+		if len(positionals) < 2 {
+			return modules.UsageError("usage: devtools history %s <id>", positionals[0])
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(positionals[1], "#"))
+		if err != nil {
+			return modules.UsageError("invalid history id %q", positionals[1])
+		}
+		entry, err := store.Find(id)
+		if err != nil {
+			return err
+		}
+		if positionals[0] == "show" {
+			printHistoryEntry(stdout, entry)
+			return nil
+		}
+
+		fmt.Fprintf(stderr, "replaying #%d: devtools run %s\n", entry.ID, entry.Command())
+		out, err := Replay(ctx, stderr, tools, entry)
+		if err != nil {
+			return err
+		}
+		if out != "" {
+			fmt.Fprintln(stdout, out)
+		}
+		return nil
+	default:
+		return modules.UsageError("unknown history command %q", positionals[0])
+	}
+}
+
+func runDev(stdout io.Writer, tools []modules.Tool, args []string) error {
+	params, positionals, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+	if len(positionals) != 2 || positionals[0] != "new-module" {
+		return modules.UsageError("usage: devtools dev new-module <module-id> [--root <repo-dir>]")
+	}
+
+	module, err := scaffold.NewModule(positionals[1])
+	if err != nil {
+		return err
+	}
+	if _, exists := modules.FindTool(tools, module.ID); exists {
+		return modules.UsageError("module %q is already registered", module.ID)
+	}
+
+	start := params["root"]
+	if start == "" {
+		start = "."
+	}
+	root, err := scaffold.FindRoot(start)
+	if err != nil {
+		return err
+	}
+
+	result, err := scaffold.Generate(root, module)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Created module %q (package %s):\n", module.ID, module.Package)
+	for _, path := range result.Files {
+		fmt.Fprintf(stdout, "  + %s\n", path)
+	}
+	for _, path := range result.Modified {
+		fmt.Fprintf(stdout, "  ~ %s\n", path)
+	}
+	if result.Alias != "" {
+		fmt.Fprintf(stdout, "\nImported as %s in main.go because %s is already used there.\n", result.Alias, module.Package)
+	}
+	fmt.Fprintf(stdout, "\nTry it: go run ./cmd/devtools run %s greet --name you\n", module.ID)
+	return nil
+}
+
+func printHistory(stdout io.Writer, store *history.Store, params map[string]string) error {
+	entries, err := store.List()
+	if err != nil {
+		return err
+	}
+	entries = history.Search(entries, params["search"], params["module"])
+
+	limit := 20
+	if raw, ok := params["limit"]; ok {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 0 {
+			return modules.UsageError("invalid limit %q", raw)
+		}
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(stdout, "No history entries.")
+		return nil
+	}
+	for _, entry := range entries {
+		fmt.Fprintf(stdout, "#%-5d %s  %-6s %-10s %6dms  %s\n",
+			entry.ID,
+			entry.StartedAt.Local().Format("2006-01-02 15:04:05"),
+			entry.Source,
+			entry.Status(),
+			entry.DurationMS,
+			entry.Command(),
+		)
 	}
-	_ = stderr
 	return nil
 }
 
+func printHistoryEntry(stdout io.Writer, entry history.Entry) {
+	fmt.Fprintf(stdout, "ID: %d\n", entry.ID)
+	fmt.Fprintf(stdout, "Source: %s\n", entry.Source)
+	fmt.Fprintf(stdout, "Command: devtools run %s\n", entry.Command())
+	if entry.Path != "" {
+		fmt.Fprintf(stdout, "Menu: %s\n", entry.Path)
+	}
+	fmt.Fprintf(stdout, "Started: %s\n", entry.StartedAt.Local().Format(time.RFC3339))
+	fmt.Fprintf(stdout, "Duration: %dms\n", entry.DurationMS)
+	fmt.Fprintf(stdout, "Status: %s\n", entry.Status())
+	if entry.Redacted {
+		fmt.Fprintln(stdout, "Redacted: yes (not replayable)")
+	}
+	if entry.Error != "" {
+		fmt.Fprintf(stdout, "Error: %s\n", entry.Error)
+	}
+	if entry.Output != "" {
+		fmt.Fprintf(stdout, "Output:\n%s\n", entry.Output)
+		if entry.Truncated {
+			fmt.Fprintln(stdout, "[output truncated]")
+		}
+	}
+}
+
 func hasHelpFlag(args []string) bool {
 	for _, arg := range args {
 		if arg == "--help" || arg == "-h" || arg == "help" {
@@ -173,12 +401,19 @@ func parseArgs(args []string) (map[string]string, []string, error) {
 		token := args[i]
 
 		switch {
+		case token == "--":
+			// Everything after a bare "--" is passed through untouched, so
+			// actions that wrap another command can receive its flags.
+			positionals = append(positionals, args[i+1:]...)
+			i = len(args)
+			continue
+
 		case strings.HasPrefix(token, "--"):
 			trimmed := strings.TrimPrefix(token, "--")
 			if strings.Contains(trimmed, "=") {
 				parts := strings.SplitN(trimmed, "=", 2)
 				if parts[0] == "" {
-					return nil, nil, fmt.Errorf("invalid flag %q", token)
+					return nil, nil, modules.UsageError("invalid flag %q", token)
 				}
 				params[parts[0]] = parts[1]
 				i++
@@ -186,19 +421,19 @@ func parseArgs(args []string) (map[string]string, []string, error) {
 			}
 
 			if i+1 >= len(args) {
-				return nil, nil, fmt.Errorf("missing value for flag %q", token)
+				return nil, nil, modules.UsageError("missing value for flag %q", token)
 			}
 			if strings.HasPrefix(args[i+1], "-") {
-				return nil, nil, fmt.Errorf("missing value for flag %q", token)
+				return nil, nil, modules.UsageError("missing value for flag %q", token)
 			}
 			params[trimmed] = args[i+1]
 			i += 2
 			continue
 
-		case strings.Contains(token, "="):
+		case strings.Contains(token, "=") && !isURL(token):
 			parts := strings.SplitN(token, "=", 2)
 			if parts[0] == "" {
-				return nil, nil, fmt.Errorf("invalid argument %q", token)
+				return nil, nil, modules.UsageError("invalid argument %q", token)
 			}
 			params[parts[0]] = parts[1]
 			i++
@@ -212,3 +447,11 @@ func parseArgs(args []string) (map[string]string, []string, error) {
 
 	return params, positionals, nil
 }
+
+// isURL reports whether token is a URL such as "http://host/x?a=b" or
+// "otpauth://totp/x?secret=y", whose "=" belongs to the query string rather
+// than a key=value param. "url=http://..." is still a param.
+func isURL(token string) bool {
+	scheme := strings.Index(token, "://")
+	return scheme > 0 && scheme < strings.Index(token, "=")
+}
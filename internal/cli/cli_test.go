@@ -0,0 +1,69 @@
+package cli_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/devtoolstest"
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/requirements"
+)
+
+type reportTool struct{}
+
+func (reportTool) ID() string                         { return "report" }
+func (reportTool) Label() string                      { return "Report" }
+func (reportTool) Description() string                { return "Partial report" }
+func (reportTool) Menu() *menu.Menu                   { return menu.New("Report", nil) }
+func (reportTool) Requirements() []requirements.Check { return nil }
+
+func (reportTool) Actions() []modules.Action {
+	return []modules.Action{
+		{
+			ID: "check",
+			Run: func(ctx modules.ActionContext) (string, error) {
+				if ctx.Params["fail"] == "true" {
+					return "node 18.0.0 (want 20.11.1)", errors.New("1 tool drifted")
+				}
+				return "all tools match", nil
+			},
+		},
+		{
+			ID: "args",
+			Run: func(ctx modules.ActionContext) (string, error) {
+				return fmt.Sprintf("params=%v positionals=%q", ctx.Params, ctx.Positionals), nil
+			},
+		},
+	}
+}
+
+func TestRunPrintsOutputOfFailedAction(t *testing.T) {
+	tools := []modules.Tool{reportTool{}}
+
+	result := devtoolstest.RunCLI(t, tools, "run", "report", "check")
+	if result.ExitCode != modules.ExitOK || result.Stdout != "all tools match\n" {
+		t.Errorf("success: %+v", result)
+	}
+
+	result = devtoolstest.RunCLI(t, tools, "run", "report", "check", "--fail", "true")
+	if result.ExitCode != modules.ExitActionFailed {
+		t.Errorf("ExitCode = %d, want %d", result.ExitCode, modules.ExitActionFailed)
+	}
+	if !strings.Contains(result.Stdout, "node 18.0.0 (want 20.11.1)") {
+		t.Errorf("Stdout = %q, want the partial report", result.Stdout)
+	}
+	if result.Err == nil || !strings.Contains(result.Err.Error(), "1 tool drifted") {
+		t.Errorf("Err = %v", result.Err)
+	}
+}
+
+func TestRunPassesArgsAfterDoubleDash(t *testing.T) {
+	result := devtoolstest.RunCLI(t, []modules.Tool{reportTool{}}, "run", "report", "args", "--file", ".env", "--", "go", "test", "--count=1", "-v", "a=b")
+	want := `params=map[file:.env] positionals=["go" "test" "--count=1" "-v" "a=b"]` + "\n"
+	if result.ExitCode != modules.ExitOK || result.Stdout != want {
+		t.Errorf("RunCLI() = %+v, want stdout %q", result, want)
+	}
+}
@@ -0,0 +1,66 @@
+package cliutil
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// stdin is shared so consecutive prompts do not lose input that an earlier
+// reader buffered. Tests replace it together with stdout.
+var (
+	stdin            = bufio.NewReader(os.Stdin)
+	stdout io.Writer = os.Stdout
+)
+
+// Prompt prints label and returns the next line of standard input without
+// surrounding whitespace.
+func Prompt(label string) (string, error) {
+	fmt.Fprint(stdout, label)
+	line, err := stdin.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// Run executes name with args and returns its trimmed stdout, or stderr when
+// stdout is empty (some CLIs print versions there), or a note when both are
+// empty. Failures include stderr.
+func Run(name string, args ...string) (string, error) {
+	var out, errOut bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s %s failed: %w (%s)", name, strings.Join(args, " "), err, strings.TrimSpace(errOut.String()))
+	}
+	if text := strings.TrimSpace(out.String()); text != "" {
+		return text, nil
+	}
+	if text := strings.TrimSpace(errOut.String()); text != "" {
+		return text, nil
+	}
+	return "Command completed with no output.", nil
+}
+
+func FirstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Dash returns "-" for empty values so table columns stay aligned.
+func Dash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
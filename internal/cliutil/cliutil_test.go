@@ -0,0 +1,91 @@
+package cliutil
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPrompt(t *testing.T) {
+	var out bytes.Buffer
+	stdin = bufio.NewReader(strings.NewReader("  first \nsecond\nlast"))
+	stdout = &out
+	t.Cleanup(func() {
+		stdin = bufio.NewReader(os.Stdin)
+		stdout = os.Stdout
+	})
+
+	for _, want := range []string{"first", "second", "last"} {
+		got, err := Prompt("Value: ")
+		if err != nil || got != want {
+			t.Errorf("Prompt() = %q, %v, want %q", got, err, want)
+		}
+	}
+	if _, err := Prompt("Value: "); err == nil {
+		t.Error("Prompt() at end of input error = nil")
+	}
+	if out.String() != strings.Repeat("Value: ", 4) {
+		t.Errorf("prompt output = %q", out.String())
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		want    string
+		wantErr string
+	}{
+		{name: "stdout", mode: "stdout", want: "on stdout"},
+		{name: "stderr fallback", mode: "stderr", want: "on stderr"},
+		{name: "no output", mode: "empty", want: "Command completed with no output."},
+		{name: "failure", mode: "fail", wantErr: "exit status 3 (broken)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CLIUTIL_HELPER", tt.mode)
+			got, err := Run(os.Args[0], "-test.run=TestHelperProcess")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Run() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Run() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestHelperProcess is the command started by TestRun.
+func TestHelperProcess(t *testing.T) {
+	switch os.Getenv("CLIUTIL_HELPER") {
+	case "stdout":
+		fmt.Println("  on stdout  ")
+	case "stderr":
+		fmt.Fprintln(os.Stderr, "on stderr")
+	case "fail":
+		fmt.Fprintln(os.Stderr, "broken")
+		os.Exit(3)
+	case "empty":
+	default:
+		return
+	}
+	os.Exit(0)
+}
+
+func TestFirstNonEmptyAndDash(t *testing.T) {
+	if got := FirstNonEmpty("", "", "b", "c"); got != "b" {
+		t.Errorf("FirstNonEmpty() = %q, want b", got)
+	}
+	if got := FirstNonEmpty(); got != "" {
+		t.Errorf("FirstNonEmpty() = %q, want empty", got)
+	}
+	if Dash("") != "-" || Dash("x") != "x" {
+		t.Errorf("Dash() = %q, %q", Dash(""), Dash("x"))
+	}
+}
@@ -0,0 +1,237 @@
+package devtoolstest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/cli"
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/requirements"
+)
+
+const (
+	KeyUp       = "\033[A"
+	KeyDown     = "\033[B"
+	KeyLeft     = "\033[D"
+	KeyEnter    = "\r"
+	KeyQuit     = "q"
+	KeyInstall  = "i"
+	KeyFavorite = "f"
+)
+
+const (
+	clearScreen = "\033[H\033[2J"
+	envUpdate   = "DEVTOOLS_UPDATE_GOLDEN"
+)
+
+var ansiPattern = regexp.MustCompile("\033\\[[0-9;]*[A-Za-z]")
+
+type Terminal struct {
+	input  *strings.Reader
+	frames []string
+	output bytes.Buffer
+	rawOn  bool
+}
+
+func NewTerminal(keys ...string) *Terminal {
+	return &Terminal{input: strings.NewReader(strings.Join(keys, ""))}
+}
+
+func (t *Terminal) Read(p []byte) (int, error) {
+	return t.input.Read(p)
+}
+
+func (t *Terminal) Write(p []byte) (int, error) {
+	chunk := string(p)
+	if strings.HasPrefix(chunk, clearScreen) {
+		frame := strings.TrimPrefix(chunk, clearScreen)
+		frame = strings.TrimPrefix(frame, "\r")
+		if frame != "" {
+			t.frames = append(t.frames, normalize(frame))
+		}
+	}
+	return t.output.Write(p)
+}
+
+func (t *Terminal) EnableRaw() error {
+	t.rawOn = true
+	return nil
+}
+
+func (t *Terminal) Restore() error {
+	t.rawOn = false
+	return nil
+}
+
+func (t *Terminal) Frames() []string {
+	return append([]string{}, t.frames...)
+}
+
+func (t *Terminal) LastFrame() string {
+	if len(t.frames) == 0 {
+		return ""
+	}
+	return t.frames[len(t.frames)-1]
+}
+
+func (t *Terminal) Output() string {
+	return t.output.String()
+}
+
+func (t *Terminal) Raw() bool {
+	return t.rawOn
+}
+
+func normalize(frame string) string {
+	frame = ansiPattern.ReplaceAllString(frame, "")
+	frame = strings.ReplaceAll(frame, "\r\n", "\n")
+	return strings.ReplaceAll(frame, "\r", "")
+}
+
+// RunMenu drives root with the given key presses and returns the captured
+// terminal. Running out of keys ends the session without an error.
+func RunMenu(tb testing.TB, root *menu.Menu, keys ...string) (*Terminal, error) {
+	tb.Helper()
+	term := NewTerminal(keys...)
+	err := menu.NewRunner(root).WithTerminal(term).WithColor(false).Run()
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+	return term, err
+}
+
+func RunTool(tb testing.TB, tool modules.Tool, keys ...string) (*Terminal, error) {
+	tb.Helper()
+	root := menu.New("Developer Tools CLI", []menu.Item{modules.ToMenuItem(tool), menu.QuitItem("Exit")})
+	return RunMenu(tb, root, keys...)
+}
+
+type CLIResult struct {
+	Stdout   string
+	Stderr   string
+	Err      error
+	ExitCode int
+}
+
+// RunCLI invokes cli.Run with captured writers. History is written to a
+// temporary DEVTOOLS_HOME so tests never touch the user's config directory.
+func RunCLI(tb testing.TB, tools []modules.Tool, args ...string) CLIResult {
+	tb.Helper()
+	Isolate(tb)
+
+	var stdout, stderr bytes.Buffer
+	runTUI := func() error {
+		return modules.UsageError("the TUI is not available in tests")
+	}
+	err := cli.Run(args, &stdout, &stderr, tools, runTUI)
+	return CLIResult{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Err:      err,
+		ExitCode: modules.ExitCode(err),
+	}
+}
+
+func Isolate(tb testing.TB) string {
+	tb.Helper()
+	dir := tb.TempDir()
+	tb.Setenv("DEVTOOLS_HOME", dir)
+	tb.Setenv("NO_COLOR", "1")
+	return dir
+}
+
+func RunAction(tb testing.TB, tool modules.Tool, actionID string, params map[string]string, positionals ...string) (string, error) {
+	tb.Helper()
+	action, ok := modules.FindAction(tool, actionID)
+	if !ok {
+		tb.Fatalf("action %q not found in module %q", actionID, tool.ID())
+	}
+	return action.Run(modules.ActionContext{Params: params, Positionals: positionals})
+}
+
+func PassingCheck(name string) requirements.Check {
+	return requirements.Check{
+		Name:     name,
+		Validate: func() error { return nil },
+	}
+}
+
+func FailingCheck(name, message string) requirements.Check {
+	return requirements.Check{
+		Name:     name,
+		Validate: func() error { return errors.New(message) },
+	}
+}
+
+type FakeInstaller struct {
+	Output string
+	Err    error
+	Calls  int
+}
+
+// FailingCheckWithInstaller fails until its installer has run successfully.
+func FailingCheckWithInstaller(name, message string, installer *FakeInstaller) requirements.Check {
+	installed := false
+	return requirements.Check{
+		Name: name,
+		Validate: func() error {
+			if installed {
+				return nil
+			}
+			return errors.New(message)
+		},
+		Installer: &requirements.InstallAction{
+			Label: fmt.Sprintf("Install %s", name),
+			Run: func() (string, error) {
+				installer.Calls++
+				if installer.Err != nil {
+					return "", installer.Err
+				}
+				installed = true
+				return installer.Output, nil
+			},
+		},
+	}
+}
+
+// AssertGolden compares got with testdata/<name>.golden. Set
+// DEVTOOLS_UPDATE_GOLDEN=1 to rewrite the file instead.
+func AssertGolden(tb testing.TB, name, got string) {
+	tb.Helper()
+	path := filepath.Join("testdata", name+".golden")
+
+	if os.Getenv(envUpdate) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			tb.Fatalf("failed to create testdata dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			tb.Fatalf("failed to update golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("failed to read golden file %s (run with %s=1 to create it): %v", path, envUpdate, err)
+	}
+	if string(want) != got {
+		tb.Errorf("frame does not match %s (run with %s=1 to update)\n--- want\n%s\n--- got\n%s", path, envUpdate, want, got)
+	}
+}
+
+func AssertFrameContains(tb testing.TB, frame string, substrings ...string) {
+	tb.Helper()
+	for _, want := range substrings {
+		if !strings.Contains(frame, want) {
+			tb.Errorf("frame does not contain %q:\n%s", want, frame)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package devtoolstest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/requirements"
+)
+
+type stubTool struct {
+	checks []requirements.Check
+}
+
+func (stubTool) ID() string                           { return "stub" }
+func (stubTool) Label() string                        { return "Stub" }
+func (stubTool) Description() string                  { return "Stub module" }
+func (s stubTool) Requirements() []requirements.Check { return s.checks }
+
+func (stubTool) Actions() []modules.Action {
+	return []modules.Action{
+		{
+			ID:    "echo",
+			Label: "Echo",
+			Usage: "devtools run stub echo [--text <value>]",
+			Run: func(ctx modules.ActionContext) (string, error) {
+				return "echo: " + ctx.Params["text"] + strings.Join(ctx.Positionals, ","), nil
+			},
+		},
+		{
+			ID:    "fail",
+			Label: "Fail",
+			Run: func(modules.ActionContext) (string, error) {
+				return "", errors.New("boom")
+			},
+		},
+	}
+}
+
+func (stubTool) Menu() *menu.Menu {
+	return menu.NewBuilder("Stub").
+		Action("Echo", "Prints a line", func() (string, error) { return "echoed", nil }).
+		WithBack().
+		Build()
+}
+
+func TestTerminalCapturesNormalizedFrames(t *testing.T) {
+	term := NewTerminal()
+	term.Write([]byte(clearScreen + "\r\033[1mTitle\033[0m\r\nline\r\n"))
+	term.Write([]byte("not a frame"))
+	term.Write([]byte(clearScreen))
+
+	if got := term.Frames(); len(got) != 1 || got[0] != "Title\nline\n" {
+		t.Fatalf("Frames() = %q, want one normalized frame", got)
+	}
+	if !strings.HasSuffix(term.Output(), clearScreen) {
+		t.Errorf("Output() = %q, want the raw bytes", term.Output())
+	}
+}
+
+func TestRunToolNavigatesAndRestoresTerminal(t *testing.T) {
+	term, err := RunTool(t, stubTool{}, KeyEnter, KeyEnter)
+	if err != nil {
+		t.Fatalf("RunTool() error = %v", err)
+	}
+	if term.Raw() {
+		t.Error("terminal left in raw mode")
+	}
+	AssertFrameContains(t, term.LastFrame(), "Menu: Stub", "▶ Echo", "echoed")
+
+	term, err = RunTool(t, stubTool{}, KeyEnter, KeyLeft, KeyQuit)
+	if err != nil {
+		t.Fatalf("RunTool() error = %v", err)
+	}
+	AssertFrameContains(t, term.LastFrame(), "Menu: Developer Tools CLI")
+}
+
+func TestRunToolRequirementInstaller(t *testing.T) {
+	installer := &FakeInstaller{Output: "installed stub"}
+	tool := stubTool{checks: []requirements.Check{
+		PassingCheck("git"),
+		FailingCheckWithInstaller("stubctl", "stubctl not found", installer),
+	}}
+
+	term, err := RunTool(t, tool, KeyEnter, KeyInstall, KeyEnter)
+	if err != nil {
+		t.Fatalf("RunTool() error = %v", err)
+	}
+	if installer.Calls != 1 {
+		t.Errorf("installer calls = %d, want 1", installer.Calls)
+	}
+	frames := term.Frames()
+	AssertFrameContains(t, frames[1], "Requirement failed", "stubctl not found", "i install")
+	AssertFrameContains(t, frames[2], "installed stub")
+	AssertFrameContains(t, term.LastFrame(), "Menu: Stub")
+}
+
+func TestFailingCheck(t *testing.T) {
+	if err := FailingCheck("docker", "docker missing").Run(); err == nil || !strings.Contains(err.Error(), "docker missing") {
+		t.Errorf("FailingCheck().Run() = %v, want docker missing", err)
+	}
+}
+
+func TestRunCLI(t *testing.T) {
+	tools := []modules.Tool{stubTool{}}
+	tests := []struct {
+		name       string
+		args       []string
+		wantStdout string
+		wantCode   int
+	}{
+		{name: "action", args: []string{"run", "stub", "echo", "--text", "hi"}, wantStdout: "echo: hi", wantCode: modules.ExitOK},
+		{name: "unknown module", args: []string{"run", "nope", "echo"}, wantCode: modules.ExitNotFound},
+		{name: "unknown action", args: []string{"run", "stub", "nope"}, wantCode: modules.ExitNotFound},
+		{name: "tui unavailable", args: nil, wantCode: modules.ExitUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RunCLI(t, tools, tt.args...)
+			if result.ExitCode != tt.wantCode {
+				t.Errorf("ExitCode = %d, want %d (err: %v)", result.ExitCode, tt.wantCode, result.Err)
+			}
+			if !strings.Contains(result.Stdout, tt.wantStdout) {
+				t.Errorf("Stdout = %q, want %q", result.Stdout, tt.wantStdout)
+			}
+		})
+	}
+}
+
+func TestIsolate(t *testing.T) {
+	dir := Isolate(t)
+	if got := os.Getenv("DEVTOOLS_HOME"); got != dir {
+		t.Errorf("DEVTOOLS_HOME = %q, want %q", got, dir)
+	}
+	if os.Getenv("NO_COLOR") == "" {
+		t.Error("NO_COLOR not set")
+	}
+}
+
+func TestRunAction(t *testing.T) {
+	out, err := RunAction(t, stubTool{}, "echo", map[string]string{"text": "a"}, "b")
+	if err != nil || out != "echo: ab" {
+		t.Errorf("RunAction() = %q, %v", out, err)
+	}
+	if _, err := RunAction(t, stubTool{}, "fail", nil); err == nil {
+		t.Error("RunAction(fail) error = nil")
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	t.Setenv(envUpdate, "1")
+	AssertGolden(t, "frame", "hello\n")
+
+	got, err := os.ReadFile(filepath.Join("testdata", "frame.golden"))
+	if err != nil || string(got) != "hello\n" {
+		t.Fatalf("golden file = %q, %v", got, err)
+	}
+
+	t.Setenv(envUpdate, "")
+	AssertGolden(t, "frame", "hello\n")
+}
@@ -0,0 +1,197 @@
+package envfile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const DefaultName = ".env"
+
+var keyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+type Entry struct {
+	Key   string
+	Value string
+	Line  int
+}
+
+type File struct {
+	Path    string
+	Entries []Entry
+}
+
+// Parse reads KEY=VALUE lines. It accepts an optional "export " prefix,
+// # comments, single-quoted literal values and double-quoted values with
+// \n, \t, \" and \\ escapes that may span several lines. Values are not
+// expanded.
+func Parse(r io.Reader) ([]Entry, error) {
+	entries := make([]Entry, 0)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		start := lineNo
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, raw, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", start)
+		}
+		if !keyPattern.MatchString(key) {
+			return nil, fmt.Errorf("line %d: invalid key %q", start, key)
+		}
+		raw = strings.TrimSpace(raw)
+
+		var value string
+		switch {
+		case strings.HasPrefix(raw, `"`):
+			body := raw[1:]
+			for !closesDoubleQuote(body) {
+				if !scanner.Scan() {
+					return nil, fmt.Errorf("line %d: unterminated double-quoted value for %s", start, key)
+				}
+				lineNo++
+				body += "\n" + scanner.Text()
+			}
+			value = unescape(body[:closingQuote(body)])
+		case strings.HasPrefix(raw, "'"):
+			end := strings.Index(raw[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated single-quoted value for %s", start, key)
+			}
+			value = raw[1 : end+1]
+		default:
+			if i := strings.Index(raw, " #"); i >= 0 {
+				raw = raw[:i]
+			}
+			value = strings.TrimSpace(raw)
+		}
+		entries = append(entries, Entry{Key: key, Value: value, Line: start})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func closingQuote(body string) int {
+	escaped := false
+	for i, r := range body {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			return i
+		}
+	}
+	return -1
+}
+
+func closesDoubleQuote(body string) bool {
+	return closingQuote(body) >= 0
+}
+
+func unescape(value string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\r`, "\r", `\"`, `"`, `\\`, `\`, `\$`, `$`)
+	return replacer.Replace(value)
+}
+
+func Load(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	entries, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &File{Path: path, Entries: entries}, nil
+}
+
+// Find walks up from dir and returns the first file called name.
+func Find(dir, name string) (string, bool) {
+	for {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// Lookup returns the last value assigned to key, matching how the file is
+// applied in order.
+func (f *File) Lookup(key string) (string, bool) {
+	for i := len(f.Entries) - 1; i >= 0; i-- {
+		if f.Entries[i].Key == key {
+			return f.Entries[i].Value, true
+		}
+	}
+	return "", false
+}
+
+func (f *File) Map() map[string]string {
+	values := make(map[string]string, len(f.Entries))
+	for _, entry := range f.Entries {
+		values[entry.Key] = entry.Value
+	}
+	return values
+}
+
+// Keys returns each key once, in first-seen order.
+func (f *File) Keys() []string {
+	seen := map[string]bool{}
+	keys := make([]string, 0, len(f.Entries))
+	for _, entry := range f.Entries {
+		if !seen[entry.Key] {
+			seen[entry.Key] = true
+			keys = append(keys, entry.Key)
+		}
+	}
+	return keys
+}
+
+// Duplicates returns keys assigned more than once, sorted.
+func (f *File) Duplicates() []string {
+	counts := map[string]int{}
+	for _, entry := range f.Entries {
+		counts[entry.Key]++
+	}
+	dups := make([]string, 0)
+	for key, count := range counts {
+		if count > 1 {
+			dups = append(dups, key)
+		}
+	}
+	sort.Strings(dups)
+	return dups
+}
+
+// Format renders a single KEY=VALUE line, quoting the value when it would not
+// survive an unquoted round trip.
+func Format(key, value string) string {
+	if value == "" || !strings.ContainsAny(value, " \t\n\r\"'#\\$=") {
+		return key + "=" + value
+	}
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "$", `\$`)
+	return key + `="` + escaper.Replace(value) + `"`
+}
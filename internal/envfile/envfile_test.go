@@ -0,0 +1,76 @@
+package envfile
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	input := strings.Join([]string{
+		"# comment",
+		"",
+		"PLAIN=value # trailing comment",
+		"export EXPORTED=yes",
+		"SINGLE='literal $HOME \\n'",
+		`DOUBLE="line one\nline \"two\""`,
+		`MULTI="first`,
+		`second"`,
+		"EMPTY=",
+		"PLAIN=again",
+	}, "\n")
+	entries, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []Entry{
+		{Key: "PLAIN", Value: "value", Line: 3},
+		{Key: "EXPORTED", Value: "yes", Line: 4},
+		{Key: "SINGLE", Value: `literal $HOME \n`, Line: 5},
+		{Key: "DOUBLE", Value: "line one\nline \"two\"", Line: 6},
+		{Key: "MULTI", Value: "first\nsecond", Line: 7},
+		{Key: "EMPTY", Value: "", Line: 9},
+		{Key: "PLAIN", Value: "again", Line: 10},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Parse() = %+v\nwant %+v", entries, want)
+	}
+
+	file := &File{Entries: entries}
+	if value, _ := file.Lookup("PLAIN"); value != "again" {
+		t.Errorf("Lookup(PLAIN) = %q, want the last assignment", value)
+	}
+	if dups := file.Duplicates(); !reflect.DeepEqual(dups, []string{"PLAIN"}) {
+		t.Errorf("Duplicates() = %v", dups)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "no equals", input: "JUSTAKEY", want: "line 1: expected KEY=VALUE"},
+		{name: "bad key", input: "\n1BAD=x", want: `line 2: invalid key "1BAD"`},
+		{name: "unterminated double", input: `KEY="open`, want: "unterminated double-quoted"},
+		{name: "unterminated single", input: `KEY='open`, want: "unterminated single-quoted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.input)); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	for _, value := range []string{"", "simple", "with space", "quote\"and\\slash", "multi\nline", "$NOT_EXPANDED", "a=b # c"} {
+		line := Format("KEY", value)
+		entries, err := Parse(strings.NewReader(line))
+		if err != nil || len(entries) != 1 || entries[0].Value != value {
+			t.Errorf("Format(%q) = %q, parsed back as %+v (err %v)", value, line, entries, err)
+		}
+	}
+}
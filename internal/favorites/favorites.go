@@ -0,0 +1,102 @@
+package favorites
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"go-devtools/internal/appdir"
+)
+
+const fileName = "favorites.json"
+
+type Store struct {
+	path string
+}
+
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+func Default() (*Store, error) {
+	path, err := appdir.Path(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return Open(path), nil
+}
+
+func (s *Store) Path() string {
+	return s.path
+}
+
+// keySeparator cannot appear in a menu label, unlike the " / " shown to users,
+// so two different paths never share a key.
+const keySeparator = "\x1f"
+
+func Key(path []string) string {
+	return strings.Join(path, keySeparator)
+}
+
+// Label is the path as shown in the Favorites section.
+func Label(path []string) string {
+	return strings.Join(path, " / ")
+}
+
+func (s *Store) List() ([][]string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read favorites: %w", err)
+	}
+
+	var paths [][]string
+	if err := json.Unmarshal(data, &paths); err != nil {
+		return nil, fmt.Errorf("failed to parse favorites file %s: %w", s.path, err)
+	}
+	return paths, nil
+}
+
+func (s *Store) Toggle(path []string) (bool, error) {
+	paths, err := s.List()
+	if err != nil {
+		return false, err
+	}
+
+	key := Key(path)
+	kept := make([][]string, 0, len(paths)+1)
+	removed := false
+	for _, existing := range paths {
+		if Key(existing) == key {
+			removed = true
+			continue
+		}
+		kept = append(kept, existing)
+	}
+	if !removed {
+		kept = append(kept, path)
+	}
+
+	if err := s.save(kept); err != nil {
+		return false, err
+	}
+	return !removed, nil
+}
+
+func (s *Store) save(paths [][]string) error {
+	data, err := json.MarshalIndent(paths, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode favorites: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write favorites: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write favorites: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package favorites
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestToggleAddsAndRemovesPaths(t *testing.T) {
+	store := Open(filepath.Join(t.TempDir(), fileName))
+	if paths, err := store.List(); err != nil || len(paths) != 0 {
+		t.Fatalf("List on a missing file = %v, %v", paths, err)
+	}
+
+	nested := []string{"Cloud", "AWS / SSO"}
+	split := []string{"Cloud", "AWS", "SSO"}
+	for _, path := range [][]string{nested, split} {
+		if added, err := store.Toggle(path); err != nil || !added {
+			t.Fatalf("Toggle(%q) = %v, %v, want added", path, added, err)
+		}
+	}
+	paths, err := store.List()
+	if err != nil || !reflect.DeepEqual(paths, [][]string{nested, split}) {
+		t.Fatalf("List = %q, %v", paths, err)
+	}
+
+	if added, err := store.Toggle(split); err != nil || added {
+		t.Fatalf("second Toggle = %v, %v, want removed", added, err)
+	}
+	if paths, _ := store.List(); !reflect.DeepEqual(paths, [][]string{nested}) {
+		t.Errorf("after removal List = %q, want only %q", paths, nested)
+	}
+}
+
+func TestKeysDoNotCollideWhenLabelsContainTheDisplaySeparator(t *testing.T) {
+	nested := []string{"Cloud", "AWS / SSO"}
+	split := []string{"Cloud", "AWS", "SSO"}
+	if Label(nested) != Label(split) {
+		t.Fatalf("labels differ: %q, %q", Label(nested), Label(split))
+	}
+	if Key(nested) == Key(split) {
+		t.Errorf("Key(%q) == Key(%q)", nested, split)
+	}
+}
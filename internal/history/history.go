@@ -0,0 +1,347 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go-devtools/internal/appdir"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/redact"
+)
+
+const (
+	fileName       = "history.jsonl"
+	maxOutputBytes = 4096
+	envDisable     = "DEVTOOLS_NO_HISTORY"
+	envOutput      = "DEVTOOLS_HISTORY_OUTPUT"
+
+	// Once the file grows past maxFileBytes it is moved to history.jsonl.1
+	// and the newest keepEntries lines start the new file.
+	maxFileBytes = 1 << 20
+	keepEntries  = 500
+	tailBytes    = 64 * 1024
+)
+
+const (
+	SourceCLI    = "cli"
+	SourceTUI    = "tui"
+	SourceReplay = "replay"
+)
+
+type Entry struct {
+	ID          int               `json:"id"`
+	Source      string            `json:"source"`
+	Module      string            `json:"module"`
+	Action      string            `json:"action"`
+	Path        string            `json:"path,omitempty"`
+	Params      map[string]string `json:"params,omitempty"`
+	Positionals []string          `json:"positionals,omitempty"`
+	Redacted    bool              `json:"redacted,omitempty"`
+	StartedAt   time.Time         `json:"started_at"`
+	DurationMS  int64             `json:"duration_ms"`
+	ExitStatus  int               `json:"exit_status"`
+	Error       string            `json:"error,omitempty"`
+	Output      string            `json:"output,omitempty"`
+	Truncated   bool              `json:"truncated,omitempty"`
+}
+
+func (e Entry) Status() string {
+	if e.ExitStatus == 0 {
+		return "ok"
+	}
+	return fmt.Sprintf("failed(%d)", e.ExitStatus)
+}
+
+func (e Entry) Command() string {
+	parts := []string{e.Module, e.Action}
+	for _, key := range sortedKeys(e.Params) {
+		parts = append(parts, fmt.Sprintf("--%s=%s", key, e.Params[key]))
+	}
+	parts = append(parts, e.Positionals...)
+	return strings.Join(parts, " ")
+}
+
+type Store struct {
+	path          string
+	disabled      bool
+	captureOutput bool
+}
+
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+// Default opens the history file in the config directory. Action output is
+// kept (truncated) unless DEVTOOLS_HISTORY_OUTPUT=0.
+func Default() (*Store, error) {
+	if os.Getenv(envDisable) != "" {
+		return &Store{disabled: true}, nil
+	}
+	path, err := appdir.Path(fileName)
+	if err != nil {
+		return nil, err
+	}
+	store := Open(path)
+	store.captureOutput = os.Getenv(envOutput) != "0"
+	return store, nil
+}
+
+func (s *Store) CaptureOutput(enabled bool) *Store {
+	s.captureOutput = enabled
+	return s
+}
+
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append assigns the next ID from the last line of the file and writes the
+// entry while holding a lock, so concurrent invocations never share an ID.
+func (s *Store) Append(entry Entry) (Entry, error) {
+	if s.disabled {
+		return entry, nil
+	}
+	if !s.captureOutput {
+		entry.Output = ""
+	}
+	if len(entry.Output) > maxOutputBytes {
+		entry.Output = strings.ToValidUTF8(entry.Output[:maxOutputBytes], "")
+		entry.Truncated = true
+	}
+
+	unlock, err := lockFile(s.path + ".lock")
+	if err != nil {
+		return entry, fmt.Errorf("failed to lock history file: %w", err)
+	}
+	defer unlock()
+
+	last, err := s.lastID()
+	if err != nil {
+		return entry, err
+	}
+	entry.ID = last + 1
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return entry, fmt.Errorf("failed to encode history entry: %w", err)
+	}
+	if err := s.rotate(len(line) + 1); err != nil {
+		return entry, err
+	}
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return entry, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return entry, fmt.Errorf("failed to write history file: %w", err)
+	}
+	return entry, nil
+}
+
+// lastID reads the ID of the newest entry from the end of the file. It only
+// falls back to a full read when the tail holds no parseable line.
+func (s *Store) lastID() (int, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read history file: %w", err)
+	}
+	offset := max(info.Size()-tailBytes, 0)
+	tail := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(tail, offset); err != nil && err != io.EOF {
+		return 0, fmt.Errorf("failed to read history file: %w", err)
+	}
+	lines := strings.Split(strings.TrimRight(string(tail), "\n"), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		var entry Entry
+		if json.Unmarshal([]byte(lines[i]), &entry) == nil && entry.ID > 0 {
+			return entry.ID, nil
+		}
+	}
+
+	entries, err := s.List()
+	if err != nil || len(entries) == 0 {
+		return 0, err
+	}
+	return entries[len(entries)-1].ID, nil
+}
+
+// rotate moves a full history file to <path>.1 and starts a new one with
+// the newest keepEntries lines, so IDs keep increasing across rotations.
+func (s *Store) rotate(incoming int) error {
+	info, err := os.Stat(s.path)
+	if err != nil || info.Size()+int64(incoming) <= maxFileBytes {
+		return nil
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read history file: %w", err)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > keepEntries {
+		lines = lines[len(lines)-keepEntries:]
+	}
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate history file: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		return fmt.Errorf("failed to rotate history file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to rotate history file: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) List() ([]Entry, error) {
+	if s.disabled {
+		return nil, nil
+	}
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	entries := make([]Entry, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+	return entries, nil
+}
+
+func (s *Store) Find(id int) (Entry, error) {
+	entries, err := s.List()
+	if err != nil {
+		return Entry{}, err
+	}
+	for _, entry := range entries {
+		if entry.ID == id {
+			return entry, nil
+		}
+	}
+	return Entry{}, modules.NotFoundError("history entry %d not found", id)
+}
+
+func (s *Store) Clear() error {
+	if s.disabled {
+		return nil
+	}
+	for _, path := range []string{s.path, s.path + ".1"} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to clear history: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) Recent(limit int) ([]Entry, error) {
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	recent := make([]Entry, 0, limit)
+	for i := len(entries) - 1; i >= 0 && len(recent) < limit; i-- {
+		entry := entries[i]
+		// TUI items that are not module actions are recorded with their
+		// path only and cannot be replayed.
+		if entry.Redacted || entry.Module == "" || entry.Action == "" {
+			continue
+		}
+		command := entry.Command()
+		if seen[command] {
+			continue
+		}
+		seen[command] = true
+		recent = append(recent, entry)
+	}
+	return recent, nil
+}
+
+func Search(entries []Entry, query, module string) []Entry {
+	query = strings.ToLower(query)
+	matched := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		if module != "" && entry.Module != module {
+			continue
+		}
+		if query != "" {
+			haystack := strings.ToLower(strings.Join([]string{entry.Command(), entry.Path, entry.Error, entry.Output}, "\n"))
+			if !strings.Contains(haystack, query) {
+				continue
+			}
+		}
+		matched = append(matched, entry)
+	}
+	return matched
+}
+
+func RedactParams(params map[string]string) (map[string]string, bool) {
+	if len(params) == 0 {
+		return nil, false
+	}
+	redacted := false
+	out := make(map[string]string, len(params))
+	for key, value := range params {
+		if redact.IsSecretKey(key) && value != "" {
+			out[key] = redact.Placeholder
+			redacted = true
+			continue
+		}
+		out[key] = value
+	}
+	return out, redacted
+}
+
+func RedactPositionals(positionals []string) []string {
+	out := make([]string, len(positionals))
+	for i := range positionals {
+		out[i] = redact.Placeholder
+	}
+	return out
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
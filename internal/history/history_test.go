@@ -0,0 +1,165 @@
+package history
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func tempStore(t *testing.T) *Store {
+	t.Helper()
+	t.Setenv("DEVTOOLS_HOME", t.TempDir())
+	t.Setenv(envDisable, "")
+	t.Setenv(envOutput, "")
+	store, err := Default()
+	if err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+	return store
+}
+
+func TestAppendAssignsIDsAndTruncatesOutput(t *testing.T) {
+	store := tempStore(t)
+
+	first, err := store.Append(Entry{Module: "hello", Action: "greet", Output: "hi"})
+	if err != nil {
+		t.Fatalf("Append error = %v", err)
+	}
+	second, err := store.Append(Entry{Module: "hello", Action: "greet", Output: strings.Repeat("x", maxOutputBytes+10)})
+	if err != nil {
+		t.Fatalf("Append error = %v", err)
+	}
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("IDs = %d, %d, want 1, 2", first.ID, second.ID)
+	}
+
+	entries, err := store.List()
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("List = %d entries, %v", len(entries), err)
+	}
+	if entries[0].Output != "hi" {
+		t.Errorf("output = %q, want it recorded by default", entries[0].Output)
+	}
+	if len(entries[1].Output) != maxOutputBytes || !entries[1].Truncated {
+		t.Errorf("long output = %d bytes, truncated %v", len(entries[1].Output), entries[1].Truncated)
+	}
+	if info, err := os.Stat(store.Path()); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("history file mode = %v, %v, want 0600", info, err)
+	}
+}
+
+func TestOutputOptOutAndDisable(t *testing.T) {
+	store := tempStore(t)
+	t.Setenv(envOutput, "0")
+	quiet, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := quiet.Append(Entry{Module: "hello", Action: "greet", Output: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	if entry, err := store.Find(1); err != nil || entry.Output != "" {
+		t.Errorf("entry = %+v, %v, want no output", entry, err)
+	}
+
+	t.Setenv(envDisable, "1")
+	disabled, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := disabled.Append(Entry{Module: "hello", Action: "greet"}); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := store.List(); len(entries) != 1 {
+		t.Errorf("disabled store wrote an entry: %d entries", len(entries))
+	}
+}
+
+func TestAppendRotatesAndKeepsIDs(t *testing.T) {
+	store := tempStore(t)
+	payload := strings.Repeat("y", maxOutputBytes)
+	count := maxFileBytes/maxOutputBytes + 10
+	for i := 0; i < count; i++ {
+		if _, err := store.Append(Entry{Module: "hello", Action: "greet", Output: payload}); err != nil {
+			t.Fatalf("Append %d error = %v", i, err)
+		}
+	}
+	if _, err := os.Stat(store.Path() + ".1"); err != nil {
+		t.Fatalf("rotated file missing: %v", err)
+	}
+	entries, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) > keepEntries+count {
+		t.Errorf("%d entries after rotation", len(entries))
+	}
+	if last := entries[len(entries)-1]; last.ID != count {
+		t.Errorf("last ID = %d, want %d", last.ID, count)
+	}
+
+	if err := store.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(store.Path() + ".1"); !os.IsNotExist(err) {
+		t.Errorf("Clear left the rotated file: %v", err)
+	}
+}
+
+func TestConcurrentAppendsGetUniqueIDs(t *testing.T) {
+	store := tempStore(t)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := Open(store.Path()).Append(Entry{Module: "hello", Action: "greet"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[int]bool{}
+	for _, entry := range entries {
+		if seen[entry.ID] {
+			t.Errorf("duplicate ID %d", entry.ID)
+		}
+		seen[entry.ID] = true
+	}
+	if len(entries) != 20 {
+		t.Errorf("%d entries, want 20", len(entries))
+	}
+}
+
+func TestRecentSkipsDuplicatesAndUnreplayableEntries(t *testing.T) {
+	store := tempStore(t)
+	for _, entry := range []Entry{
+		{Module: "hello", Action: "greet", Params: map[string]string{"name": "a"}},
+		{Module: "hello", Action: "greet", Params: map[string]string{"name": "b"}},
+		{Module: "hello", Action: "greet", Params: map[string]string{"name": "a"}},
+		{Module: "auth", Action: "login", Redacted: true},
+		{Module: "kube", Path: "Kube / Switch context / dev"},
+	} {
+		if _, err := store.Append(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recent, err := store.Recent(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var commands []string
+	for _, entry := range recent {
+		commands = append(commands, entry.Command())
+	}
+	if got := strings.Join(commands, "|"); got != "hello greet --name=a|hello greet --name=b" {
+		t.Errorf("Recent = %q", got)
+	}
+}
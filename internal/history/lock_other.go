@@ -0,0 +1,9 @@
+//go:build !unix
+
+package history
+
+// lockFile is a no-op where flock is unavailable; concurrent appends there
+// may still race on the next ID.
+func lockFile(string) (func(), error) {
+	return func() {}, nil
+}
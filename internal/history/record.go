@@ -0,0 +1,102 @@
+package history
+
+import (
+	"strings"
+	"time"
+
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/redact"
+)
+
+func NewEntry(source, moduleID string, action modules.Action, ctx modules.ActionContext, started time.Time, out string, runErr error) Entry {
+	params, redacted := RedactParams(ctx.Params)
+	positionals := ctx.Positionals
+	if action.Sensitive && len(positionals) > 0 {
+		positionals = RedactPositionals(positionals)
+		redacted = true
+	}
+
+	entry := Entry{
+		Source:      source,
+		Module:      moduleID,
+		Action:      action.ID,
+		Params:      params,
+		Positionals: positionals,
+		Redacted:    redacted,
+		StartedAt:   started,
+		DurationMS:  time.Since(started).Milliseconds(),
+		Output:      out,
+	}
+	if action.SecretOutput && out != "" {
+		entry.Output = redact.Placeholder
+	}
+	if runErr != nil {
+		entry.ExitStatus = modules.ExitCode(modules.ActionError(runErr))
+		entry.Error = runErr.Error()
+	}
+	return entry
+}
+
+func FromMenuEvent(tools []modules.Tool, event menu.Event) (Entry, bool) {
+	if len(event.Trail) == 0 {
+		return Entry{}, false
+	}
+
+	labels := make([]string, 0, len(event.Trail)+1)
+	for _, item := range event.Trail {
+		labels = append(labels, item.Label)
+	}
+	labels = append(labels, event.Item.Label)
+
+	entry := Entry{
+		Source:     SourceTUI,
+		Module:     event.Trail[0].ID,
+		Action:     event.Item.ID,
+		Path:       strings.Join(labels, " / "),
+		StartedAt:  event.Started,
+		DurationMS: event.Duration.Milliseconds(),
+		Output:     event.Output,
+	}
+	if tool, ok := modules.FindTool(tools, entry.Module); ok {
+		for _, action := range tool.Actions() {
+			if action.ID == event.Item.ID || (event.Item.ID == "" && action.Label == event.Item.Label) {
+				entry.Action = action.ID
+				if action.SecretOutput && entry.Output != "" {
+					entry.Output = redact.Placeholder
+				}
+				break
+			}
+		}
+	}
+	if event.Err != nil {
+		entry.ExitStatus = modules.ExitCode(modules.ActionError(event.Err))
+		entry.Error = event.Err.Error()
+	}
+	return entry, true
+}
+
+func Resolve(tools []modules.Tool, entry Entry) (modules.Action, modules.ActionContext, error) {
+	if entry.Redacted {
+		return modules.Action{}, modules.ActionContext{}, modules.UsageError("history entry %d has redacted parameters and cannot be replayed", entry.ID)
+	}
+
+	tool, ok := modules.FindTool(tools, entry.Module)
+	if !ok {
+		return modules.Action{}, modules.ActionContext{}, modules.NotFoundError("history entry %d: unknown module %q", entry.ID, entry.Module)
+	}
+	action, ok := modules.FindAction(tool, entry.Action)
+	if !ok {
+		return modules.Action{}, modules.ActionContext{}, modules.NotFoundError("history entry %d (%s) is not linked to a runnable action", entry.ID, entry.Path)
+	}
+	if err := modules.ValidateRequirements(tool); err != nil {
+		return modules.Action{}, modules.ActionContext{}, modules.RequirementError("requirements failed for module %q: %w", entry.Module, err)
+	}
+
+	params := map[string]string{}
+	for key, value := range entry.Params {
+		params[key] = value
+	}
+	positionals := append([]string{}, entry.Positionals...)
+	return action, modules.ActionContext{Params: params, Positionals: positionals}, nil
+}
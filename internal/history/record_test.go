@@ -0,0 +1,50 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/redact"
+	"go-devtools/internal/requirements"
+)
+
+type secretTool struct{}
+
+func (secretTool) ID() string                         { return "secret" }
+func (secretTool) Label() string                      { return "Secret" }
+func (secretTool) Description() string                { return "Secret output" }
+func (secretTool) Menu() *menu.Menu                   { return menu.New("Secret", nil) }
+func (secretTool) Requirements() []requirements.Check { return nil }
+
+func (secretTool) Actions() []modules.Action {
+	return []modules.Action{
+		{ID: "password", Label: "Password", SecretOutput: true},
+		{ID: "uuid", Label: "UUID"},
+	}
+}
+
+func TestSecretOutputIsNotRecorded(t *testing.T) {
+	actions := secretTool{}.Actions()
+	started := time.Now()
+
+	entry := NewEntry(SourceCLI, "secret", actions[0], modules.ActionContext{}, started, "hunter2", nil)
+	if entry.Output != redact.Placeholder {
+		t.Errorf("CLI output = %q, want %q", entry.Output, redact.Placeholder)
+	}
+	entry = NewEntry(SourceCLI, "secret", actions[1], modules.ActionContext{}, started, "0190-uuid", nil)
+	if entry.Output != "0190-uuid" {
+		t.Errorf("CLI output = %q, want it kept", entry.Output)
+	}
+
+	event := menu.Event{
+		Trail:  []menu.Item{{ID: "secret", Label: "Secret"}},
+		Item:   menu.Item{Label: "Password"},
+		Output: "hunter2",
+	}
+	entry, ok := FromMenuEvent([]modules.Tool{secretTool{}}, event)
+	if !ok || entry.Output != redact.Placeholder {
+		t.Errorf("TUI entry = %+v, want redacted output", entry)
+	}
+}
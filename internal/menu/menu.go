@@ -3,9 +3,10 @@ package menu
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"strings"
+	"time"
 
 	"go-devtools/internal/requirements"
 )
@@ -34,17 +35,64 @@ const (
 )
 
 type Item struct {
+	ID      string
+	Section string
+	// Key identifies generated items, such as pinned favorites, independently
+	// of their label.
+	Key          string
 	Label        string
 	Description  string
 	NextMenu     *Menu
 	Run          func() (string, error)
+	Live         func(out io.Writer) (string, error)
 	Requirements []requirements.Check
 	Action       Action
+	Danger       bool // shown in red; actions run only after a second Enter
 }
 
 type Menu struct {
-	Title string
-	Items []Item
+	Title   string
+	Items   []Item
+	Leading func() []Item
+	// OnOpen runs each time the Runner enters the menu, so Leading can
+	// cache what it loads until the menu is opened again.
+	OnOpen func()
+}
+
+func (m *Menu) AllItems() []Item {
+	if m.Leading == nil {
+		return m.Items
+	}
+	leading := m.Leading()
+	items := make([]Item, 0, len(leading)+len(m.Items))
+	items = append(items, leading...)
+	return append(items, m.Items...)
+}
+
+// Resolve follows labels through the static Items of each menu. Leading
+// items are generated on every render and cannot be resolved, so they
+// cannot be pinned either.
+func (m *Menu) Resolve(labels []string) ([]Item, bool) {
+	current := m
+	trail := make([]Item, 0, len(labels))
+	for _, label := range labels {
+		if current == nil {
+			return nil, false
+		}
+		found := false
+		for _, item := range current.Items {
+			if item.Label == label && item.Action == ActionNone {
+				trail = append(trail, item)
+				current = item.NextMenu
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return trail, len(trail) > 0
 }
 
 func New(title string, items []Item) *Menu {
@@ -101,6 +149,17 @@ func (b *Builder) Action(label, description string, run func() (string, error))
 	return b
 }
 
+// LiveAction adds an item whose progress is streamed into an output pane
+// while it runs, for long-running actions such as servers.
+func (b *Builder) LiveAction(label, description string, run func(out io.Writer) (string, error)) *Builder {
+	b.items = append(b.items, Item{
+		Label:       label,
+		Description: description,
+		Live:        run,
+	})
+	return b
+}
+
 func (b *Builder) SubMenu(label, description string, submenu *Menu, checks ...requirements.Check) *Builder {
 	b.items = append(b.items, Item{
 		Label:        label,
@@ -139,32 +198,44 @@ const (
 	keyLeft
 	keyEnter
 	keyInstall
+	keyFavorite
 	keyQuit
 )
 
-type terminalState struct {
-	previous string
-}
-
 type requirementFailure struct {
 	err       error
 	installer *requirements.InstallAction
 }
 
+type Event struct {
+	Trail    []Item
+	Item     Item
+	Started  time.Time
+	Duration time.Duration
+	Output   string
+	Err      error
+}
+
 type Runner struct {
 	stack          []*Menu
+	trail          []Item
+	items          []Item
 	cursor         int
 	status         string
 	maxDepth       int
-	term           *terminalState
+	term           Terminal
 	pendingInstall *requirements.InstallAction
+	confirming     string
 	useColor       bool
+	observer       func(Event)
+	favorite       func(trail []Item, item Item) (string, error)
 }
 
 func NewRunner(root *Menu) *Runner {
 	return &Runner{
 		stack:    []*Menu{root},
 		maxDepth: defaultMaxDepth,
+		term:     &stdTerminal{},
 		useColor: os.Getenv("NO_COLOR") == "",
 	}
 }
@@ -173,15 +244,33 @@ func Run(root *Menu) error {
 	return NewRunner(root).Run()
 }
 
+func (r *Runner) OnAction(observer func(Event)) *Runner {
+	r.observer = observer
+	return r
+}
+
+func (r *Runner) WithTerminal(term Terminal) *Runner {
+	r.term = term
+	return r
+}
+
+func (r *Runner) WithColor(enabled bool) *Runner {
+	r.useColor = enabled
+	return r
+}
+
+func (r *Runner) OnFavorite(toggle func(trail []Item, item Item) (string, error)) *Runner {
+	r.favorite = toggle
+	return r
+}
+
 func (r *Runner) Run() error {
-	state, err := setRawMode()
-	if err != nil {
+	if err := r.term.EnableRaw(); err != nil {
 		return err
 	}
-	r.term = state
-	defer restoreMode(state)
+	defer r.term.Restore()
 
-	reader := bufio.NewReader(os.Stdin)
+	reader := bufio.NewReader(r.term)
 	for {
 		r.render()
 
@@ -195,14 +284,19 @@ func (r *Runner) Run() error {
 			return err
 		}
 		if done {
-			fmt.Print("\033[H\033[2J\r")
+			fmt.Fprint(r.term, clearScreen)
 			return nil
 		}
 	}
 }
 
 func (r *Runner) handleKey(pressed key) (bool, error) {
-	current := r.currentMenu()
+	current := r.items
+	if current == nil {
+		current = r.refreshItems()
+	}
+	confirming := r.confirming
+	r.confirming = ""
 	switch pressed {
 	case keyQuit:
 		return true, nil
@@ -223,90 +317,143 @@ func (r *Runner) handleKey(pressed key) (bool, error) {
 		}
 		r.pendingInstall = nil
 		return false, nil
+	case keyFavorite:
+		if r.favorite == nil || len(current) == 0 {
+			return false, nil
+		}
+		selected := current[r.cursor]
+		if selected.Action != ActionNone {
+			r.status = "Exit items cannot be pinned."
+			return false, nil
+		}
+		out, err := r.favorite(r.trailCopy(), selected)
+		if err != nil {
+			r.status = fmt.Sprintf("Error: %v", err)
+		} else {
+			r.status = out
+		}
+		return false, nil
 	case keyUp:
-		if len(current.Items) == 0 {
+		if len(current) == 0 {
 			return false, nil
 		}
 		r.cursor--
 		if r.cursor < 0 {
-			r.cursor = len(current.Items) - 1
+			r.cursor = len(current) - 1
 		}
 	case keyDown:
-		if len(current.Items) == 0 {
+		if len(current) == 0 {
 			return false, nil
 		}
 		r.cursor++
-		if r.cursor >= len(current.Items) {
+		if r.cursor >= len(current) {
 			r.cursor = 0
 		}
 	case keyLeft:
-		if len(r.stack) > 1 {
-			r.stack = r.stack[:len(r.stack)-1]
-			r.cursor = 0
-			r.status = ""
-			r.pendingInstall = nil
-		}
+		r.pop()
 	case keyEnter:
-		if len(current.Items) == 0 {
+		if len(current) == 0 {
 			return false, nil
 		}
 
-		selected := current.Items[r.cursor]
+		selected := current[r.cursor]
 		switch selected.Action {
 		case ActionQuit:
 			return true, nil
 		case ActionBack:
-			if len(r.stack) > 1 {
-				r.stack = r.stack[:len(r.stack)-1]
-				r.cursor = 0
-				r.status = ""
-				r.pendingInstall = nil
-			}
+			r.pop()
 			return false, nil
 		}
 
-		if selected.NextMenu != nil {
-			if len(r.stack) >= r.maxDepth {
-				r.status = fmt.Sprintf("Max menu depth reached (%d). Go back before opening more submenus.", r.maxDepth)
-				return false, nil
-			}
+		if selected.NextMenu != nil && len(r.stack) >= r.maxDepth {
+			r.status = fmt.Sprintf("Max menu depth reached (%d). Go back before opening more submenus.", r.maxDepth)
+			return false, nil
+		}
 
-			if failure := firstFailedRequirement(selected.Requirements); failure != nil {
-				r.status = fmt.Sprintf("Requirement failed: %v", failure.err)
-				r.pendingInstall = failure.installer
-				if r.pendingInstall != nil {
-					r.status = fmt.Sprintf("%s | Press i to %s.", r.status, r.pendingInstall.Label)
-				}
-				return false, nil
+		if failure := firstFailedRequirement(selected.Requirements); failure != nil {
+			r.status = fmt.Sprintf("Requirement failed: %v", failure.err)
+			r.pendingInstall = failure.installer
+			if r.pendingInstall != nil {
+				r.status = fmt.Sprintf("%s | Press i to %s.", r.status, r.pendingInstall.Label)
 			}
+			return false, nil
+		}
 
+		if selected.NextMenu != nil {
+			if selected.NextMenu.OnOpen != nil {
+				selected.NextMenu.OnOpen()
+			}
 			r.stack = append(r.stack, selected.NextMenu)
+			r.trail = append(r.trail, selected)
 			r.cursor = 0
 			r.status = ""
 			r.pendingInstall = nil
 			return false, nil
 		}
 
-		if selected.Run != nil {
-			out, err := r.runAction(selected.Run)
+		run := selected.Run
+		if selected.Live != nil {
+			run = func() (string, error) {
+				return selected.Live(newOutputPane(r, selected.Label))
+			}
+		}
+		if run != nil && selected.Danger && confirming != selected.Label {
+			r.confirming = selected.Label
+			r.status = fmt.Sprintf("%q is marked as dangerous. Press Enter again to confirm, any other key to cancel.", selected.Label)
+			return false, nil
+		}
+		if run != nil {
+			started := time.Now()
+			out, err := r.runAction(run)
+			r.notify(selected, started, out, err)
+			r.status = out
 			if err != nil {
-				r.status = fmt.Sprintf("Error: %v", err)
-			} else {
-				r.status = out
+				// Like the CLI, keep what a failing report found above the error.
+				r.status = strings.TrimSpace(fmt.Sprintf("%s\n\nError: %v", out, err))
 			}
 		}
 	}
 	return false, nil
 }
 
+func (r *Runner) pop() {
+	if len(r.stack) > 1 {
+		r.stack = r.stack[:len(r.stack)-1]
+		r.trail = r.trail[:len(r.trail)-1]
+		r.cursor = 0
+		r.status = ""
+		r.pendingInstall = nil
+	}
+}
+
+func (r *Runner) notify(item Item, started time.Time, out string, err error) {
+	if r.observer == nil {
+		return
+	}
+	r.observer(Event{
+		Trail:    r.trailCopy(),
+		Item:     item,
+		Started:  started,
+		Duration: time.Since(started),
+		Output:   out,
+		Err:      err,
+	})
+}
+
+func (r *Runner) trailCopy() []Item {
+	trail := make([]Item, len(r.trail))
+	copy(trail, r.trail)
+	return trail
+}
+
 func (r *Runner) runAction(action func() (string, error)) (string, error) {
 	if action == nil {
 		return "", nil
 	}
 
-	restoreMode(r.term)
+	_ = r.term.Restore()
 	out, runErr := action()
-	rawErr := enableRawMode()
+	rawErr := r.term.EnableRaw()
 	if rawErr != nil {
 		if runErr != nil {
 			return out, fmt.Errorf("%v; failed to restore raw mode: %w", runErr, rawErr)
@@ -318,9 +465,10 @@ func (r *Runner) runAction(action func() (string, error)) (string, error) {
 
 func (r *Runner) render() {
 	current := r.currentMenu()
+	items := r.refreshItems()
 	var b strings.Builder
 
-	b.WriteString("\033[H\033[2J\r")
+	b.WriteString(clearScreen)
 	topRule := strings.Repeat("=", uiWidth)
 	bottomRule := strings.Repeat("-", uiWidth)
 
@@ -330,16 +478,32 @@ func (r *Runner) render() {
 	fmt.Fprintf(&b, "%s %d/%d\r\n", r.paint("Depth:", ansiBold+ansiBlue), len(r.stack), r.maxDepth)
 	fmt.Fprintf(&b, "%s\r\n\r\n", r.paint(topRule, ansiCyan))
 
-	for i, item := range current.Items {
+	section := ""
+	for i, item := range items {
+		if item.Section != section {
+			if i > 0 {
+				b.WriteString("\r\n")
+			}
+			if item.Section != "" {
+				fmt.Fprintf(&b, "%s\r\n", r.paint(item.Section, ansiBold+ansiBlue))
+			}
+			section = item.Section
+		}
+
 		cursor := "  "
 		if r.cursor == i {
 			cursor = "▶ "
 		}
 
 		label := fmt.Sprintf("%s%s", cursor, item.Label)
-		if r.cursor == i {
+		switch {
+		case item.Danger && r.cursor == i:
+			label = r.paint(label, ansiBold+ansiRed)
+		case item.Danger:
+			label = r.paint(label, ansiRed)
+		case r.cursor == i:
 			label = r.paint(label, ansiBold+ansiGreen)
-		} else {
+		default:
 			label = r.paint(label, ansiWhite)
 		}
 		fmt.Fprint(&b, label)
@@ -352,6 +516,9 @@ func (r *Runner) render() {
 
 	fmt.Fprintf(&b, "\r\n%s\r\n", r.paint(bottomRule, ansiCyan))
 	controls := "↑/↓ move | Enter select | ← back | q quit"
+	if r.favorite != nil {
+		controls += " | f pin"
+	}
 	if r.pendingInstall != nil {
 		controls += " | i install"
 	}
@@ -360,13 +527,21 @@ func (r *Runner) render() {
 		fmt.Fprintf(&b, "\r\n%s\r\n", r.formatStatus(r.status))
 	}
 
-	fmt.Print(b.String())
+	fmt.Fprint(r.term, b.String())
 }
 
 func (r *Runner) currentMenu() *Menu {
 	return r.stack[len(r.stack)-1]
 }
 
+func (r *Runner) refreshItems() []Item {
+	r.items = r.currentMenu().AllItems()
+	if r.cursor >= len(r.items) {
+		r.cursor = 0
+	}
+	return r.items
+}
+
 func firstFailedRequirement(checks []requirements.Check) *requirementFailure {
 	for _, check := range checks {
 		if err := check.Run(); err != nil {
@@ -376,6 +551,9 @@ func firstFailedRequirement(checks []requirements.Check) *requirementFailure {
 			}
 		}
 	}
+	if err := requirements.ExportAll(checks); err != nil {
+		return &requirementFailure{err: err}
+	}
 	return nil
 }
 
@@ -390,6 +568,8 @@ func readKey(reader *bufio.Reader) (key, error) {
 		return keyQuit, nil
 	case 'i', 'I':
 		return keyInstall, nil
+	case 'f', 'F':
+		return keyFavorite, nil
 	case '\r', '\n':
 		return keyEnter, nil
 	case 27:
@@ -416,39 +596,6 @@ func readKey(reader *bufio.Reader) (key, error) {
 	return keyUnknown, nil
 }
 
-func setRawMode() (*terminalState, error) {
-	get := exec.Command("stty", "-g")
-	get.Stdin = os.Stdin
-	output, err := get.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to read terminal state: %w", err)
-	}
-
-	prev := strings.TrimSpace(string(output))
-	if err := enableRawMode(); err != nil {
-		return nil, err
-	}
-	return &terminalState{previous: prev}, nil
-}
-
-func enableRawMode() error {
-	raw := exec.Command("stty", "raw", "-echo")
-	raw.Stdin = os.Stdin
-	if err := raw.Run(); err != nil {
-		return fmt.Errorf("failed to enable raw mode: %w", err)
-	}
-	return nil
-}
-
-func restoreMode(state *terminalState) {
-	if state == nil {
-		return
-	}
-	cmd := exec.Command("stty", state.previous)
-	cmd.Stdin = os.Stdin
-	_ = cmd.Run()
-}
-
 func normalizeCRLF(input string) string {
 	normalized := strings.ReplaceAll(input, "\r\n", "\n")
 	normalized = strings.ReplaceAll(normalized, "\r", "\n")
@@ -0,0 +1,203 @@
+package menu_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/devtoolstest"
+	"go-devtools/internal/menu"
+)
+
+func testMenu() *menu.Menu {
+	deeper := menu.NewBuilder("Deeper").
+		Action("Fail", "Always fails", func() (string, error) { return "2 problems found", errors.New("boom") }).
+		WithBack().
+		Build()
+	sub := menu.NewBuilder("Sub").
+		Action("Hello", "Says hello", func() (string, error) { return "hello", nil }).
+		SubMenu("Deeper", "Nested", deeper).
+		WithBack().
+		Build()
+	return menu.NewBuilder("Root").
+		SubMenu("Sub", "Opens a submenu", sub).
+		WithQuit().
+		Build()
+}
+
+func run(t *testing.T, runner *menu.Runner, keys ...string) *devtoolstest.Terminal {
+	t.Helper()
+	term := devtoolstest.NewTerminal(keys...)
+	err := runner.WithTerminal(term).WithColor(false).Run()
+	if err != nil && !errors.Is(err, io.EOF) {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if term.Raw() {
+		t.Error("terminal left in raw mode")
+	}
+	return term
+}
+
+func TestRunnerNavigation(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		want []string
+	}{
+		{name: "open submenu", keys: []string{devtoolstest.KeyEnter}, want: []string{"Menu: Sub", "Depth: 2/4", "▶ Hello"}},
+		{name: "run action", keys: []string{devtoolstest.KeyEnter, devtoolstest.KeyEnter}, want: []string{"Menu: Sub", "hello"}},
+		{name: "action error", keys: []string{devtoolstest.KeyEnter, devtoolstest.KeyDown, devtoolstest.KeyEnter, devtoolstest.KeyEnter}, want: []string{"Menu: Deeper", "2 problems found\n\nError: boom"}},
+		{name: "back item", keys: []string{devtoolstest.KeyEnter, devtoolstest.KeyUp, devtoolstest.KeyEnter}, want: []string{"Menu: Root", "Depth: 1/4"}},
+		{name: "left arrow", keys: []string{devtoolstest.KeyEnter, devtoolstest.KeyLeft}, want: []string{"Menu: Root"}},
+		{name: "cursor wraps", keys: []string{devtoolstest.KeyUp}, want: []string{"▶ Exit"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := run(t, menu.NewRunner(testMenu()), tt.keys...)
+			devtoolstest.AssertFrameContains(t, term.LastFrame(), tt.want...)
+		})
+	}
+}
+
+func TestRunnerQuit(t *testing.T) {
+	for _, keys := range [][]string{{devtoolstest.KeyQuit}, {devtoolstest.KeyDown, devtoolstest.KeyEnter}} {
+		runner := menu.NewRunner(testMenu()).WithTerminal(devtoolstest.NewTerminal(keys...)).WithColor(false)
+		if err := runner.Run(); err != nil {
+			t.Errorf("Run(%q) error = %v, want clean exit", keys, err)
+		}
+	}
+}
+
+func TestRunnerOnAction(t *testing.T) {
+	var events []menu.Event
+	runner := menu.NewRunner(testMenu()).OnAction(func(event menu.Event) {
+		events = append(events, event)
+	})
+	run(t, runner, devtoolstest.KeyEnter, devtoolstest.KeyEnter, devtoolstest.KeyDown, devtoolstest.KeyEnter, devtoolstest.KeyEnter)
+
+	if len(events) != 2 {
+		t.Fatalf("events = %d, want 2", len(events))
+	}
+	if events[0].Item.Label != "Hello" || events[0].Output != "hello" || len(events[0].Trail) != 1 {
+		t.Errorf("first event = %+v", events[0])
+	}
+	if events[1].Item.Label != "Fail" || events[1].Err == nil || len(events[1].Trail) != 2 {
+		t.Errorf("second event = %+v", events[1])
+	}
+}
+
+func TestRunnerOnFavorite(t *testing.T) {
+	var pinned []string
+	runner := menu.NewRunner(testMenu()).OnFavorite(func(trail []menu.Item, item menu.Item) (string, error) {
+		for _, parent := range trail {
+			pinned = append(pinned, parent.Label)
+		}
+		pinned = append(pinned, item.Label)
+		return "pinned", nil
+	})
+	term := run(t, runner, devtoolstest.KeyEnter, devtoolstest.KeyFavorite)
+
+	if strings.Join(pinned, "/") != "Sub/Hello" {
+		t.Errorf("pinned path = %q, want Sub/Hello", pinned)
+	}
+	devtoolstest.AssertFrameContains(t, term.LastFrame(), "f pin", "pinned")
+
+	term = run(t, runner.OnFavorite(func([]menu.Item, menu.Item) (string, error) {
+		t.Error("exit items must not be pinned")
+		return "", nil
+	}), devtoolstest.KeyUp, devtoolstest.KeyFavorite)
+	devtoolstest.AssertFrameContains(t, term.LastFrame(), "Exit items cannot be pinned.")
+}
+
+func TestLeadingSections(t *testing.T) {
+	root := testMenu()
+	root.Leading = func() []menu.Item {
+		return []menu.Item{{Section: "Recent", Label: "recent one", Run: func() (string, error) { return "replayed", nil }}}
+	}
+	term := run(t, menu.NewRunner(root), devtoolstest.KeyEnter)
+	devtoolstest.AssertFrameContains(t, term.LastFrame(), "Recent\n▶ recent one", "replayed")
+}
+
+func TestOnOpenRunsWhenEnteringMenu(t *testing.T) {
+	opened := 0
+	sub := menu.New("Sub", []menu.Item{menu.BackItem("Back")})
+	sub.OnOpen = func() { opened++ }
+	root := menu.New("Root", []menu.Item{{Label: "Sub", NextMenu: sub}})
+
+	run(t, menu.NewRunner(root), devtoolstest.KeyEnter, devtoolstest.KeyEnter, devtoolstest.KeyDown, devtoolstest.KeyEnter)
+	if opened != 2 {
+		t.Errorf("OnOpen ran %d times, want once per entry (2)", opened)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	root := testMenu()
+	trail, ok := root.Resolve([]string{"Sub", "Deeper", "Fail"})
+	if !ok || len(trail) != 3 || trail[2].Label != "Fail" {
+		t.Errorf("Resolve() = %v, %v", trail, ok)
+	}
+	for _, path := range [][]string{{"Sub", "Missing"}, {"Sub", "Back"}, {"Sub", "Hello", "Extra"}, {}} {
+		if _, ok := root.Resolve(path); ok {
+			t.Errorf("Resolve(%q) succeeded, want failure", path)
+		}
+	}
+}
+
+func TestLiveActionStreamsIntoPane(t *testing.T) {
+	root := menu.NewBuilder("Root").
+		LiveAction("Listen", "Streams lines", func(out io.Writer) (string, error) {
+			fmt.Fprintln(out, "first request")
+			fmt.Fprint(out, "second ")
+			fmt.Fprintln(out, "request")
+			return "stopped", nil
+		}).
+		WithQuit().
+		Build()
+
+	var events []menu.Event
+	term := run(t, menu.NewRunner(root).OnAction(func(event menu.Event) {
+		events = append(events, event)
+	}), devtoolstest.KeyEnter)
+
+	var pane string
+	for _, frame := range term.Frames() {
+		if strings.Contains(frame, "Running: Listen") {
+			pane = frame
+		}
+	}
+	devtoolstest.AssertFrameContains(t, pane, "first request\nsecond request\n", "Live output")
+	devtoolstest.AssertFrameContains(t, term.LastFrame(), "Menu: Root", "stopped")
+	if len(events) != 1 || events[0].Output != "stopped" {
+		t.Errorf("events = %+v", events)
+	}
+}
+
+func TestDangerItemsNeedConfirmation(t *testing.T) {
+	runs := 0
+	root := menu.NewBuilder("Root").
+		Custom(menu.Item{Label: "prod-eu", Danger: true, Run: func() (string, error) {
+			runs++
+			return "switched", nil
+		}}).
+		WithQuit().
+		Build()
+
+	term := run(t, menu.NewRunner(root), devtoolstest.KeyEnter)
+	devtoolstest.AssertFrameContains(t, term.LastFrame(), `"prod-eu" is marked as dangerous. Press Enter again to confirm`)
+	if runs != 0 {
+		t.Fatalf("dangerous item ran without confirmation")
+	}
+
+	run(t, menu.NewRunner(root), devtoolstest.KeyEnter, devtoolstest.KeyDown, devtoolstest.KeyUp, devtoolstest.KeyEnter)
+	if runs != 0 {
+		t.Fatalf("confirmation survived moving the cursor")
+	}
+
+	term = run(t, menu.NewRunner(root), devtoolstest.KeyEnter, devtoolstest.KeyEnter)
+	if runs != 1 {
+		t.Fatalf("runs = %d after confirming, want 1", runs)
+	}
+	devtoolstest.AssertFrameContains(t, term.LastFrame(), "switched")
+}
@@ -0,0 +1,64 @@
+package menu
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const paneLines = 20
+
+// outputPane receives the output of a live action and redraws the screen
+// with its most recent lines. Writes may come from several goroutines.
+type outputPane struct {
+	mu      sync.Mutex
+	runner  *Runner
+	title   string
+	lines   []string
+	partial string
+}
+
+func newOutputPane(runner *Runner, title string) *outputPane {
+	pane := &outputPane{runner: runner, title: title}
+	pane.render()
+	return pane
+}
+
+func (p *outputPane) Write(data []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	parts := strings.Split(p.partial+strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	p.partial = parts[len(parts)-1]
+	p.lines = append(p.lines, parts[:len(parts)-1]...)
+	if len(p.lines) > paneLines {
+		p.lines = p.lines[len(p.lines)-paneLines:]
+	}
+	p.render()
+	return len(data), nil
+}
+
+func (p *outputPane) render() {
+	r := p.runner
+	topRule := strings.Repeat("=", uiWidth)
+	bottomRule := strings.Repeat("-", uiWidth)
+
+	var b strings.Builder
+	b.WriteString(clearScreen)
+	fmt.Fprintf(&b, "%s\r\n", r.paint(topRule, ansiCyan))
+	fmt.Fprintf(&b, "%s\r\n", r.paint("DEV TOOLS CLI", ansiBold+ansiWhite))
+	fmt.Fprintf(&b, "%s %s\r\n", r.paint("Menu:", ansiBold+ansiBlue), r.paint(r.currentMenu().Title, ansiYellow))
+	fmt.Fprintf(&b, "%s %s\r\n", r.paint("Running:", ansiBold+ansiBlue), r.paint(p.title, ansiYellow))
+	fmt.Fprintf(&b, "%s\r\n\r\n", r.paint(topRule, ansiCyan))
+
+	for _, line := range p.lines {
+		fmt.Fprintf(&b, "%s\r\n", line)
+	}
+	if p.partial != "" {
+		fmt.Fprintf(&b, "%s\r\n", p.partial)
+	}
+
+	fmt.Fprintf(&b, "\r\n%s\r\n", r.paint(bottomRule, ansiCyan))
+	fmt.Fprintf(&b, "%s\r\n", r.paint(fmt.Sprintf("Live output (last %d lines)", paneLines), ansiDim))
+	fmt.Fprint(r.term, b.String())
+}
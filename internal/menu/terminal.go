@@ -0,0 +1,57 @@
+package menu
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const clearScreen = "\033[H\033[2J\r"
+
+type Terminal interface {
+	Read(p []byte) (int, error)
+	Write(p []byte) (int, error)
+	EnableRaw() error
+	Restore() error
+}
+
+type stdTerminal struct {
+	previous string
+}
+
+func (t *stdTerminal) Read(p []byte) (int, error) {
+	return os.Stdin.Read(p)
+}
+
+func (t *stdTerminal) Write(p []byte) (int, error) {
+	return os.Stdout.Write(p)
+}
+
+func (t *stdTerminal) EnableRaw() error {
+	if t.previous == "" {
+		get := exec.Command("stty", "-g")
+		get.Stdin = os.Stdin
+		output, err := get.Output()
+		if err != nil {
+			return fmt.Errorf("failed to read terminal state: %w", err)
+		}
+		t.previous = strings.TrimSpace(string(output))
+	}
+
+	raw := exec.Command("stty", "raw", "-echo")
+	raw.Stdin = os.Stdin
+	if err := raw.Run(); err != nil {
+		return fmt.Errorf("failed to enable raw mode: %w", err)
+	}
+	return nil
+}
+
+func (t *stdTerminal) Restore() error {
+	if t.previous == "" {
+		return nil
+	}
+	cmd := exec.Command("stty", t.previous)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
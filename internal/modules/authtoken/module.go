@@ -32,11 +32,13 @@ func (Tool) Requirements() []requirements.Check { return nil }
 func (Tool) Actions() []modules.Action {
 	return []modules.Action{
 		{
-			ID:          "userpass-token",
-			Label:       "Generate username/password token",
-			Description: "Generate token for username and password flow",
-			Usage:       "devtools run auth-token-generator userpass-token --username <name> --password <secret>",
-			Run:         generateUserPassTokenAction,
+			ID:           "userpass-token",
+			Label:        "Generate username/password token",
+			Description:  "Generate token for username and password flow",
+			Usage:        "devtools run auth-token-generator userpass-token --username <name> --password <secret>",
+			Sensitive:    true,
+			SecretOutput: true,
+			Run:          generateUserPassTokenAction,
 		},
 		{
 			ID:          "google-token",
@@ -106,10 +108,10 @@ func generateUserPassTokenAction(ctx modules.ActionContext) (string, error) {
 	username := paramOrPositional(ctx, "username", 0)
 	password := paramOrPositional(ctx, "password", 1)
 	if username == "" {
-		return "", fmt.Errorf("missing username (use --username or first positional argument)")
+		return "", modules.UsageError("missing username (use --username or first positional argument)")
 	}
 	if password == "" {
-		return "", fmt.Errorf("missing password (use --password or second positional argument)")
+		return "", modules.UsageError("missing password (use --password or second positional argument)")
 	}
 	return createUserPassToken(username, password)
 }
@@ -117,7 +119,7 @@ func generateUserPassTokenAction(ctx modules.ActionContext) (string, error) {
 func generateGoogleTokenAction(ctx modules.ActionContext) (string, error) {
 	email := paramOrPositional(ctx, "email", 0)
 	if email == "" {
-		return "", fmt.Errorf("missing email (use --email or first positional argument)")
+		return "", modules.UsageError("missing email (use --email or first positional argument)")
 	}
 	return createGoogleToken(email)
 }
@@ -0,0 +1,273 @@
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-devtools/internal/appdir"
+	"go-devtools/internal/modules"
+)
+
+const (
+	caDirName       = "certs"
+	caCertFile      = "ca.pem"
+	caKeyFile       = "ca-key.pem"
+	defaultCAName   = "go-devtools Development CA"
+	defaultCADays   = 3650
+	defaultLeafDays = 825
+)
+
+type authority struct {
+	Dir  string
+	Cert *x509.Certificate
+	Key  crypto.Signer
+}
+
+func caDir(ctx modules.ActionContext) (string, error) {
+	if dir := ctx.Params["ca-dir"]; dir != "" {
+		return dir, nil
+	}
+	return appdir.Path(caDirName)
+}
+
+func newKey(kind string) (crypto.Signer, error) {
+	switch strings.ToLower(kind) {
+	case "", "ecdsa", "ec", "p256":
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "rsa", "rsa2048":
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case "rsa4096":
+		return rsa.GenerateKey(rand.Reader, 4096)
+	default:
+		return nil, modules.UsageError("unknown key type %q (use ecdsa, rsa or rsa4096)", kind)
+	}
+}
+
+func serialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	return serial, nil
+}
+
+// subjectKeyID hashes the encoded public key; it only needs to be stable so
+// issued certificates can point back at the CA.
+func subjectKeyID(pub crypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum(der)
+	return sum[:], nil
+}
+
+func createCA(dir, name string, days int, keyType string) (*authority, error) {
+	key, err := newKey(keyType)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+	skid, err := subjectKeyID(key.Public())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode public key: %w", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name, Organization: []string{"go-devtools"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(0, 0, days),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+		SubjectKeyId:          skid,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	if err := writeKey(filepath.Join(dir, caKeyFile), key); err != nil {
+		return nil, err
+	}
+	if err := writeCerts(filepath.Join(dir, caCertFile), cert); err != nil {
+		return nil, err
+	}
+	return &authority{Dir: dir, Cert: cert, Key: key}, nil
+}
+
+func loadCA(dir string) (*authority, error) {
+	certPath := filepath.Join(dir, caCertFile)
+	certs, err := readCertificates(certPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, modules.NotFoundError("no development CA in %s (run create-ca first)", dir)
+		}
+		return nil, err
+	}
+
+	keyPath := filepath.Join(dir, caKeyFile)
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s does not contain a PEM private key", keyPath)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA key: %w", err)
+	}
+	key, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported CA key type %T", parsed)
+	}
+	return &authority{Dir: dir, Cert: certs[0], Key: key}, nil
+}
+
+type leafRequest struct {
+	Names   []string
+	Days    int
+	KeyType string
+	Client  bool
+}
+
+func (ca *authority) issue(req leafRequest) (*x509.Certificate, crypto.Signer, error) {
+	if len(req.Names) == 0 {
+		return nil, nil, modules.UsageError("at least one hostname, IP, email or URI is required")
+	}
+	key, err := newKey(req.KeyType)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	notAfter := now.AddDate(0, 0, req.Days)
+	if notAfter.After(ca.Cert.NotAfter) {
+		notAfter = ca.Cert.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber:   serial,
+		Subject:        pkix.Name{CommonName: req.Names[0], Organization: []string{"go-devtools development certificate"}},
+		NotBefore:      now.Add(-time.Hour),
+		NotAfter:       notAfter,
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		AuthorityKeyId: ca.Cert.SubjectKeyId,
+	}
+	if _, isRSA := key.(*rsa.PrivateKey); isRSA {
+		template.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+	if req.Client {
+		template.ExtKeyUsage = append(template.ExtKeyUsage, x509.ExtKeyUsageClientAuth)
+	}
+	for _, name := range req.Names {
+		if err := addSAN(template, name); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, key.Public(), ca.Key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	return cert, key, nil
+}
+
+func addSAN(template *x509.Certificate, name string) error {
+	if ip := net.ParseIP(name); ip != nil {
+		template.IPAddresses = append(template.IPAddresses, ip)
+		return nil
+	}
+	if strings.Contains(name, "://") {
+		u, err := url.Parse(name)
+		if err != nil {
+			return modules.UsageError("invalid URI SAN %q: %v", name, err)
+		}
+		template.URIs = append(template.URIs, u)
+		return nil
+	}
+	if strings.Contains(name, "@") {
+		if _, err := mail.ParseAddress(name); err != nil {
+			return modules.UsageError("invalid email SAN %q: %v", name, err)
+		}
+		template.EmailAddresses = append(template.EmailAddresses, name)
+		return nil
+	}
+	host := strings.TrimPrefix(name, "*.")
+	if host == "" || strings.ContainsAny(host, " /:*") {
+		return modules.UsageError("invalid hostname %q", name)
+	}
+	template.DNSNames = append(template.DNSNames, strings.ToLower(name))
+	return nil
+}
+
+func writeKey(path string, key crypto.Signer) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode private key: %w", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	return writePrivate(path, data)
+}
+
+// writePrivate writes key material readable by the owner only. os.WriteFile
+// keeps the mode of a file it replaces, so the mode is set explicitly.
+func writePrivate(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		return fmt.Errorf("failed to restrict permissions on %s: %w", path, err)
+	}
+	return nil
+}
+
+func writeCerts(path string, certs ...*x509.Certificate) error {
+	var data []byte
+	for _, cert := range certs {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
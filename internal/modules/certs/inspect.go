@@ -0,0 +1,303 @@
+package certs
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go-devtools/internal/modules"
+)
+
+const defaultExpiryDays = 30
+
+var certExtensions = map[string]bool{".pem": true, ".crt": true, ".cer": true, ".der": true}
+
+// walkDir is replaced in tests to simulate unreadable directories.
+var walkDir = filepath.WalkDir
+
+// readCertificates accepts a PEM bundle or a single DER certificate. Other
+// PEM blocks such as private keys are skipped.
+func readCertificates(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseCertificates(path, data)
+}
+
+func parseCertificates(path string, data []byte) ([]*x509.Certificate, error) {
+	if !bytes.Contains(data, []byte("-----BEGIN")) {
+		cert, err := x509.ParseCertificate(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s is neither PEM nor a DER certificate: %w", path, err)
+		}
+		return []*x509.Certificate{cert}, nil
+	}
+
+	certs := make([]*x509.Certificate, 0)
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate %d in %s: %w", len(certs)+1, path, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("%s contains no certificates", path)
+	}
+	return certs, nil
+}
+
+func inspectFile(ctx modules.ActionContext) (string, error) {
+	path := ctx.Params["file"]
+	if path == "" && len(ctx.Positionals) > 0 {
+		path = ctx.Positionals[0]
+	}
+	if path == "" {
+		return "", modules.UsageError("missing certificate file")
+	}
+	certs, err := readCertificates(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", modules.NotFoundError("%s does not exist", path)
+		}
+		return "", err
+	}
+
+	var b strings.Builder
+	now := time.Now()
+	for i, cert := range certs {
+		if len(certs) > 1 {
+			fmt.Fprintf(&b, "Certificate %d of %d\n", i+1, len(certs))
+		}
+		writeDetails(&b, cert, now)
+		if i+1 < len(certs) {
+			if err := cert.CheckSignatureFrom(certs[i+1]); err != nil {
+				fmt.Fprintf(&b, "  Chain:         NOT signed by the next certificate (%v)\n", err)
+			} else {
+				b.WriteString("  Chain:         signed by the next certificate\n")
+			}
+			b.WriteString("\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func writeDetails(b *strings.Builder, cert *x509.Certificate, now time.Time) {
+	fmt.Fprintf(b, "  Subject:       %s\n", cert.Subject)
+	fmt.Fprintf(b, "  Issuer:        %s\n", cert.Issuer)
+	fmt.Fprintf(b, "  Serial:        %s\n", formatHex(cert.SerialNumber.Bytes()))
+	fmt.Fprintf(b, "  Not before:    %s\n", cert.NotBefore.Local().Format(time.RFC3339))
+	fmt.Fprintf(b, "  Not after:     %s (%s)\n", cert.NotAfter.Local().Format(time.RFC3339), expiryStatus(cert, now))
+	if sans := subjectAltNames(cert); len(sans) > 0 {
+		fmt.Fprintf(b, "  SANs:          %s\n", strings.Join(sans, ", "))
+	}
+	fmt.Fprintf(b, "  Public key:    %s\n", describeKey(cert))
+	fmt.Fprintf(b, "  Signature:     %s\n", cert.SignatureAlgorithm)
+	if cert.IsCA {
+		b.WriteString("  CA:            yes\n")
+	}
+	if cert.CheckSignatureFrom(cert) == nil {
+		b.WriteString("  Self-signed:   yes\n")
+	}
+	sha256Sum := sha256.Sum256(cert.Raw)
+	sha1Sum := sha1.Sum(cert.Raw)
+	fmt.Fprintf(b, "  SHA-256:       %s\n", formatHex(sha256Sum[:]))
+	fmt.Fprintf(b, "  SHA-1:         %s\n", formatHex(sha1Sum[:]))
+}
+
+func subjectAltNames(cert *x509.Certificate) []string {
+	sans := make([]string, 0)
+	for _, name := range cert.DNSNames {
+		sans = append(sans, "DNS:"+name)
+	}
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, "IP:"+ip.String())
+	}
+	for _, email := range cert.EmailAddresses {
+		sans = append(sans, "email:"+email)
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, "URI:"+uri.String())
+	}
+	return sans
+}
+
+func describeKey(cert *x509.Certificate) string {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d bits", key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("ECDSA %s", key.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return "Ed25519"
+	default:
+		return cert.PublicKeyAlgorithm.String()
+	}
+}
+
+func expiryStatus(cert *x509.Certificate, now time.Time) string {
+	switch {
+	case now.Before(cert.NotBefore):
+		return "not yet valid"
+	case now.After(cert.NotAfter):
+		return fmt.Sprintf("EXPIRED %s ago", formatDays(now.Sub(cert.NotAfter)))
+	default:
+		return "expires in " + formatDays(cert.NotAfter.Sub(now))
+	}
+}
+
+func formatDays(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	if days == 0 {
+		return d.Round(time.Minute).String()
+	}
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+func formatHex(data []byte) string {
+	encoded := strings.ToUpper(hex.EncodeToString(data))
+	parts := make([]string, 0, len(encoded)/2)
+	for i := 0; i+1 < len(encoded); i += 2 {
+		parts = append(parts, encoded[i:i+2])
+	}
+	return strings.Join(parts, ":")
+}
+
+type expiryRow struct {
+	Path    string
+	Subject string
+	Cert    *x509.Certificate
+}
+
+func checkExpiry(ctx modules.ActionContext) (string, error) {
+	dir := ctx.Params["dir"]
+	if dir == "" && len(ctx.Positionals) > 0 {
+		dir = ctx.Positionals[0]
+	}
+	if dir == "" {
+		dir = "."
+	}
+	days := defaultExpiryDays
+	if raw := ctx.Params["days"]; raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			return "", modules.UsageError("--days must be a non-negative number")
+		}
+		days = parsed
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return "", modules.NotFoundError("%s does not exist", dir)
+	}
+
+	rows := make([]expiryRow, 0)
+	skipped, skippedDirs := 0, 0
+	err := walkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// An unreadable subdirectory should not hide what the rest of
+			// the tree holds; only a failure on dir itself is fatal.
+			if path == dir || entry == nil {
+				return err
+			}
+			if entry.IsDir() {
+				skippedDirs++
+				return fs.SkipDir
+			}
+			skipped++
+			return nil
+		}
+		if entry.IsDir() || !certExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		certs, err := readCertificates(path)
+		if err != nil {
+			// Key files often share the .pem extension.
+			skipped++
+			return nil
+		}
+		for _, cert := range certs {
+			rows = append(rows, expiryRow{Path: path, Subject: commonName(cert), Cert: cert})
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+	if len(rows) == 0 {
+		out := fmt.Sprintf("No certificates found in %s.", dir)
+		if skippedDirs > 0 {
+			out += fmt.Sprintf(" %d unreadable directory(ies) skipped.", skippedDirs)
+		}
+		return out, nil
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Cert.NotAfter.Before(rows[j].Cert.NotAfter) })
+
+	now := time.Now()
+	threshold := now.AddDate(0, 0, days)
+	expired, expiring := 0, 0
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tNOT AFTER\tSUBJECT\tFILE")
+	for _, row := range rows {
+		status := "ok"
+		switch {
+		case now.After(row.Cert.NotAfter):
+			status = "EXPIRED"
+			expired++
+		case row.Cert.NotAfter.Before(threshold):
+			status = "EXPIRING"
+			expiring++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", status, row.Cert.NotAfter.Local().Format("2006-01-02"), row.Subject, row.Path)
+	}
+	w.Flush()
+
+	fmt.Fprintf(&b, "\n%d certificate(s), %d expired, %d expiring within %d days.", len(rows), expired, expiring, days)
+	if skipped > 0 {
+		fmt.Fprintf(&b, " %d unreadable or non-certificate file(s) skipped.", skipped)
+	}
+	if skippedDirs > 0 {
+		fmt.Fprintf(&b, " %d unreadable directory(ies) skipped.", skippedDirs)
+	}
+	out := b.String()
+	if expired+expiring > 0 {
+		return out, fmt.Errorf("%d certificate(s) expired or expiring within %d days", expired+expiring, days)
+	}
+	return out, nil
+}
+
+func commonName(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if sans := subjectAltNames(cert); len(sans) > 0 {
+		return sans[0]
+	}
+	return cert.Subject.String()
+}
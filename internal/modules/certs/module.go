@@ -0,0 +1,304 @@
+package certs
+
+import (
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/requirements"
+	"go-devtools/internal/safefile"
+)
+
+type Tool struct{}
+
+func New() modules.Tool {
+	return Tool{}
+}
+
+func (Tool) ID() string { return "certs" }
+
+func (Tool) Label() string { return "Certificates" }
+
+func (Tool) Description() string { return "Local development CA, certificates and expiry checks" }
+
+func (Tool) Requirements() []requirements.Check { return nil }
+
+func (Tool) Actions() []modules.Action {
+	return []modules.Action{
+		{
+			ID:          "create-ca",
+			Label:       "Create development CA",
+			Description: "Create a local CA used to sign development certificates",
+			Usage:       "devtools run certs create-ca [--name <cn>] [--days 3650] [--key ecdsa|rsa] [--force true] [--ca-dir <dir>]",
+			Run:         createCAAction,
+		},
+		{
+			ID:          "ca-info",
+			Label:       "Show development CA",
+			Description: "Show the CA certificate and how to trust it",
+			Usage:       "devtools run certs ca-info [--ca-dir <dir>]",
+			Run:         showCA,
+		},
+		{
+			ID:          "issue",
+			Label:       "Issue certificate",
+			Description: "Issue a certificate for hostnames, IPs, emails or URIs signed by the development CA",
+			Usage:       "devtools run certs issue <name>... [--out-dir .] [--file-name <base>] [--days 825] [--key ecdsa|rsa] [--client true] [--p12 true] [--p12-password <pw>] [--force true] [--ca-dir <dir>]",
+			Run:         issueAction,
+		},
+		{
+			ID:          "inspect",
+			Label:       "Inspect certificate",
+			Description: "Show subject, SANs, validity and fingerprints of a PEM or DER certificate or chain",
+			Usage:       "devtools run certs inspect <file>",
+			Run:         inspectFile,
+		},
+		{
+			ID:          "check-expiry",
+			Label:       "Check expiry",
+			Description: "Scan a directory for certificates that are expired or expire soon",
+			Usage:       "devtools run certs check-expiry [dir] [--days 30]",
+			Run:         checkExpiry,
+		},
+	}
+}
+
+func (Tool) Menu() *menu.Menu {
+	return menu.NewBuilder("Certificates").
+		Action("Show development CA", "CA certificate and trust instructions", func() (string, error) {
+			return showCA(modules.ActionContext{})
+		}).
+		Action("Create development CA", "Refuses to replace an existing CA", func() (string, error) {
+			return createCAAction(modules.ActionContext{})
+		}).
+		Action("Issue certificate", "Prompt for hostnames; files are written to the current directory", issuePrompt).
+		Action("Inspect certificate", "Prompt for a PEM or DER file", inspectPrompt).
+		Action("Check expiry", "Certificates under the current directory expiring within 30 days", func() (string, error) {
+			return checkExpiry(modules.ActionContext{})
+		}).
+		WithBack().
+		Build()
+}
+
+func createCAAction(ctx modules.ActionContext) (string, error) {
+	dir, err := caDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	days, err := intParam(ctx, "days", defaultCADays)
+	if err != nil {
+		return "", err
+	}
+	certPath := filepath.Join(dir, caCertFile)
+	if _, err := os.Stat(certPath); err == nil && ctx.Params["force"] != "true" {
+		return "", modules.UsageError("a development CA already exists in %s (use --force true to replace it; certificates it signed will stop being trusted)", dir)
+	}
+
+	// A replaced CA key cannot be recovered, and everything it signed stops
+	// validating, so keep the old pair next to the new one.
+	backups := make([]string, 0, 2)
+	for _, name := range []string{caKeyFile, caCertFile} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		backup, err := safefile.Backup(path)
+		if err != nil {
+			return "", err
+		}
+		backups = append(backups, backup)
+	}
+
+	ca, err := createCA(dir, cliutil.FirstNonEmpty(ctx.Params["name"], defaultCAName), days, ctx.Params["key"])
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Created %q, valid until %s.\n", ca.Cert.Subject.CommonName, ca.Cert.NotAfter.Local().Format("2006-01-02"))
+	fmt.Fprintf(&b, "  Certificate: %s\n", certPath)
+	fmt.Fprintf(&b, "  Private key: %s (keep this private)\n", filepath.Join(dir, caKeyFile))
+	if len(backups) > 0 {
+		fmt.Fprintf(&b, "  Previous CA: %s\n", strings.Join(backups, ", "))
+	}
+	b.WriteString("\n")
+	b.WriteString(trustHint(certPath))
+	return b.String(), nil
+}
+
+func showCA(ctx modules.ActionContext) (string, error) {
+	dir, err := caDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	ca, err := loadCA(dir)
+	if err != nil {
+		return "", err
+	}
+	certPath := filepath.Join(dir, caCertFile)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", certPath)
+	writeDetails(&b, ca.Cert, time.Now())
+	b.WriteString("\n")
+	b.WriteString(trustHint(certPath))
+	return b.String(), nil
+}
+
+func trustHint(certPath string) string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "Trust it with:\n  sudo security add-trusted-cert -d -r trustRoot -k /Library/Keychains/System.keychain " + certPath
+	case "windows":
+		return "Trust it with:\n  certutil -addstore -f ROOT " + certPath
+	default:
+		return "Trust it with (Debian/Ubuntu):\n  sudo cp " + certPath + " /usr/local/share/ca-certificates/devtools-ca.crt && sudo update-ca-certificates\n" +
+			"Node.js: export NODE_EXTRA_CA_CERTS=" + certPath
+	}
+}
+
+func issueAction(ctx modules.ActionContext) (string, error) {
+	names := ctx.Positionals
+	if len(names) == 0 {
+		return "", modules.UsageError("missing hostname (for example: devtools run certs issue localhost 127.0.0.1)")
+	}
+	days, err := intParam(ctx, "days", defaultLeafDays)
+	if err != nil {
+		return "", err
+	}
+	dir, err := caDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	ca, err := loadCA(dir)
+	if err != nil {
+		return "", err
+	}
+
+	outDir := cliutil.FirstNonEmpty(ctx.Params["out-dir"], ".")
+	base := cliutil.FirstNonEmpty(ctx.Params["file-name"], fileBase(names[0]))
+	certPath := filepath.Join(outDir, base+".pem")
+	keyPath := filepath.Join(outDir, base+"-key.pem")
+	p12Path := filepath.Join(outDir, base+".p12")
+	wantP12 := ctx.Params["p12"] == "true" || ctx.Params["p12-password"] != ""
+	if ctx.Params["force"] != "true" {
+		targets := []string{certPath, keyPath}
+		if wantP12 {
+			targets = append(targets, p12Path)
+		}
+		existing := make([]string, 0, len(targets))
+		for _, path := range targets {
+			if _, err := os.Stat(path); err == nil {
+				existing = append(existing, path)
+			}
+		}
+		if len(existing) > 0 {
+			return "", modules.UsageError("%s already exist (use --force true to replace them, or --file-name)", strings.Join(existing, ", "))
+		}
+	}
+
+	cert, key, err := ca.issue(leafRequest{
+		Names:   names,
+		Days:    days,
+		KeyType: ctx.Params["key"],
+		Client:  ctx.Params["client"] == "true",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", outDir, err)
+	}
+	if err := writeCerts(certPath, cert); err != nil {
+		return "", err
+	}
+	if err := writeKey(keyPath, key); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Issued certificate for %s, valid until %s.\n", strings.Join(subjectAltNames(cert), ", "), cert.NotAfter.Local().Format("2006-01-02"))
+	fmt.Fprintf(&b, "  Certificate: %s\n", certPath)
+	fmt.Fprintf(&b, "  Private key: %s\n", keyPath)
+
+	if wantP12 {
+		password := cliutil.FirstNonEmpty(ctx.Params["p12-password"], pkcs12.DefaultPassword)
+		if err := writePKCS12(p12Path, key, cert, ca.Cert, password); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "  PKCS#12:     %s", p12Path)
+		if ctx.Params["p12-password"] == "" {
+			fmt.Fprintf(&b, " (password %q)", pkcs12.DefaultPassword)
+		}
+		b.WriteString("\n")
+	}
+	if cert.NotAfter.Equal(ca.Cert.NotAfter) {
+		b.WriteString("Validity was shortened to match the CA expiry.\n")
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func writePKCS12(path string, key crypto.Signer, cert, caCert *x509.Certificate, password string) error {
+	data, err := pkcs12.Modern.Encode(key, cert, []*x509.Certificate{caCert}, password)
+	if err != nil {
+		return fmt.Errorf("failed to encode PKCS#12: %w", err)
+	}
+	return writePrivate(path, data)
+}
+
+// fileBase turns the first SAN into a file name, e.g. "*.example.test"
+// becomes "_wildcard.example.test".
+func fileBase(name string) string {
+	name = strings.Replace(name, "*", "_wildcard", 1)
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:@?#`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+func intParam(ctx modules.ActionContext, name string, fallback int) (int, error) {
+	raw := ctx.Params[name]
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value <= 0 {
+		return 0, modules.UsageError("--%s must be a positive number", name)
+	}
+	return value, nil
+}
+
+func issuePrompt() (string, error) {
+	line, err := cliutil.Prompt("Hostnames or IPs (space separated) [localhost 127.0.0.1 ::1]: ")
+	if err != nil {
+		return "", err
+	}
+	names := strings.Fields(line)
+	if len(names) == 0 {
+		names = []string{"localhost", "127.0.0.1", "::1"}
+	}
+	return issueAction(modules.ActionContext{Positionals: names})
+}
+
+func inspectPrompt() (string, error) {
+	path, err := cliutil.Prompt("Certificate file: ")
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", fmt.Errorf("file cannot be empty")
+	}
+	return inspectFile(modules.ActionContext{Positionals: []string{path}})
+}
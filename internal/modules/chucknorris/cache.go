@@ -0,0 +1,86 @@
+package chucknorris
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"go-devtools/internal/appdir"
+	"go-devtools/internal/safefile"
+)
+
+const (
+	cacheFile  = "chucknorris-cache.json"
+	cacheLimit = 500
+)
+
+type cache struct {
+	path string
+}
+
+func newCache(path string) *cache {
+	return &cache{path: path}
+}
+
+func (c *cache) resolve() (string, error) {
+	if c.path != "" {
+		return c.path, nil
+	}
+	return appdir.Path(cacheFile)
+}
+
+func (c *cache) load() ([]jokeResponse, error) {
+	path, err := c.resolve()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	var jokes []jokeResponse
+	if err := json.Unmarshal(data, &jokes); err != nil {
+		return nil, fmt.Errorf("failed to parse cache %s: %w", path, err)
+	}
+	return jokes, nil
+}
+
+// add stores jokes for offline use. Cache failures never fail the action.
+func (c *cache) add(jokes ...jokeResponse) {
+	if len(jokes) == 0 {
+		return
+	}
+	path, err := c.resolve()
+	if err != nil {
+		return
+	}
+	existing, err := c.load()
+	if err != nil {
+		existing = nil
+	}
+
+	seen := map[string]bool{}
+	for _, joke := range existing {
+		seen[joke.ID] = true
+	}
+	for _, joke := range jokes {
+		if joke.ID == "" || seen[joke.ID] {
+			continue
+		}
+		seen[joke.ID] = true
+		existing = append(existing, joke)
+	}
+	if len(existing) > cacheLimit {
+		existing = existing[len(existing)-cacheLimit:]
+	}
+
+	data, err := json.Marshal(existing)
+	if err != nil {
+		return
+	}
+	_ = safefile.Write(path, data)
+}
@@ -2,72 +2,360 @@ package chucknorris
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand"
 	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"go-devtools/internal/cliutil"
 	"go-devtools/internal/menu"
 	"go-devtools/internal/modules"
 	"go-devtools/internal/requirements"
 )
 
-type Tool struct{}
+const (
+	defaultBaseURL = "https://api.chucknorris.io"
+	envBaseURL     = "CHUCKNORRIS_BASE_URL"
+	defaultLimit   = 5
+)
+
+type Config struct {
+	BaseURL   string
+	Client    *http.Client
+	CachePath string
+}
+
+type Tool struct {
+	baseURL string
+	client  *http.Client
+	cache   *cache
+}
 
 func New() modules.Tool {
-	return Tool{}
+	return NewWithConfig(Config{})
+}
+
+func NewWithConfig(cfg Config) modules.Tool {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = os.Getenv(envBaseURL)
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultBaseURL
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return Tool{
+		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
+		client:  cfg.Client,
+		cache:   newCache(cfg.CachePath),
+	}
 }
 
 func (Tool) ID() string { return "chuck-norris-facts" }
 
 func (Tool) Label() string { return "Chuck Norris Fact" }
 
-func (Tool) Description() string { return "Fetch a random fact from api.chucknorris.io" }
+func (Tool) Description() string { return "Fetch facts from api.chucknorris.io (cached offline)" }
 
 func (Tool) Requirements() []requirements.Check { return nil }
 
-func (Tool) Actions() []modules.Action {
+func (t Tool) Actions() []modules.Action {
 	return []modules.Action{
 		{
 			ID:          "random-fact",
 			Label:       "Get random fact",
-			Description: "Calls GET https://api.chucknorris.io/jokes/random",
-			Usage:       "devtools run chuck-norris-facts random-fact",
-			Run:         fetchFactAction,
+			Description: "Calls GET /jokes/random, optionally filtered by category",
+			Usage:       "devtools run chuck-norris-facts random-fact [--category <name>] [--base-url <url>] [--offline true]",
+			Run:         t.fetchFactAction,
+		},
+		{
+			ID:          "categories",
+			Label:       "List categories",
+			Description: "Calls GET /jokes/categories",
+			Usage:       "devtools run chuck-norris-facts categories [--base-url <url>] [--offline true]",
+			Run:         t.categoriesAction,
+		},
+		{
+			ID:          "search",
+			Label:       "Search facts",
+			Description: "Calls GET /jokes/search?query=<text>",
+			Usage:       "devtools run chuck-norris-facts search --query <text> [--limit 5] [--base-url <url>] [--offline true]",
+			Run:         t.searchAction,
 		},
 	}
 }
 
-func (Tool) Menu() *menu.Menu {
+func (t Tool) Menu() *menu.Menu {
 	return menu.NewBuilder("Chuck Norris Fact Tool").
-		Action("Get random fact", "Calls GET https://api.chucknorris.io/jokes/random", func() (string, error) {
-			return fetchFactAction(modules.ActionContext{})
+		Action("Get random fact", "Calls GET /jokes/random", func() (string, error) {
+			return t.fetchFactAction(modules.ActionContext{})
 		}).
+		Action("Random fact by category", "Prompt for a category", t.categoryPrompt).
+		Action("List categories", "Calls GET /jokes/categories", func() (string, error) {
+			return t.categoriesAction(modules.ActionContext{})
+		}).
+		Action("Search facts", "Prompt for a search query", t.searchPrompt).
 		WithBack().
 		Build()
 }
 
 type jokeResponse struct {
-	ID    string `json:"id"`
-	URL   string `json:"url"`
-	Value string `json:"value"`
+	ID         string   `json:"id"`
+	URL        string   `json:"url"`
+	Value      string   `json:"value"`
+	Categories []string `json:"categories"`
+}
+
+type searchResponse struct {
+	Total  int            `json:"total"`
+	Result []jokeResponse `json:"result"`
 }
 
-func fetchFactAction(_ modules.ActionContext) (string, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get("https://api.chucknorris.io/jokes/random")
+func (t Tool) fetchFactAction(ctx modules.ActionContext) (string, error) {
+	category := ctx.Params["category"]
+	if category == "" && len(ctx.Positionals) > 0 {
+		category = ctx.Positionals[0]
+	}
+	return t.randomFact(ctx, category)
+}
+
+func (t Tool) categoryPrompt() (string, error) {
+	category, err := cliutil.Prompt("Category: ")
 	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
+		return "", err
+	}
+	if category == "" {
+		return "", fmt.Errorf("category cannot be empty")
+	}
+	return t.randomFact(modules.ActionContext{}, category)
+}
+
+func (t Tool) randomFact(ctx modules.ActionContext, category string) (string, error) {
+	query := url.Values{}
+	if category != "" {
+		query.Set("category", category)
+	}
+
+	var joke jokeResponse
+	err := t.get(ctx, "/jokes/random", query, &joke)
+	if err == nil {
+		t.cache.add(joke)
+		return formatJoke(joke), nil
+	}
+	if !isOffline(err) {
+		return "", err
+	}
+
+	cached, cacheErr := t.cache.load()
+	if cacheErr != nil {
+		return "", fmt.Errorf("%w (cache unavailable: %v)", err, cacheErr)
+	}
+	matches := filterByCategory(cached, category)
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%w (no cached facts%s)", err, forCategory(category))
+	}
+	joke = matches[rand.Intn(len(matches))]
+	return formatJoke(joke) + offlineNote(err), nil
+}
+
+func (t Tool) categoriesAction(ctx modules.ActionContext) (string, error) {
+	var categories []string
+	err := t.get(ctx, "/jokes/categories", nil, &categories)
+	if err == nil {
+		return strings.Join(categories, "\n"), nil
+	}
+	if !isOffline(err) {
+		return "", err
+	}
+
+	cached, cacheErr := t.cache.load()
+	if cacheErr != nil || len(cached) == 0 {
+		return "", fmt.Errorf("%w (no cached facts)", err)
+	}
+	seen := map[string]bool{}
+	for _, joke := range cached {
+		for _, category := range joke.Categories {
+			if category != "" && !seen[category] {
+				seen[category] = true
+				categories = append(categories, category)
+			}
+		}
+	}
+	sort.Strings(categories)
+	if len(categories) == 0 {
+		return "No categories in the local cache." + offlineNote(err), nil
+	}
+	return strings.Join(categories, "\n") + offlineNote(err), nil
+}
+
+func (t Tool) searchAction(ctx modules.ActionContext) (string, error) {
+	text := ctx.Params["query"]
+	if text == "" {
+		text = strings.Join(ctx.Positionals, " ")
+	}
+	if text == "" {
+		return "", modules.UsageError("missing query (use --query or positional arguments)")
+	}
+
+	limit := defaultLimit
+	if raw, ok := ctx.Params["limit"]; ok {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			return "", modules.UsageError("invalid limit %q", raw)
+		}
+		limit = parsed
+	}
+	return t.search(ctx, text, limit)
+}
+
+func (t Tool) searchPrompt() (string, error) {
+	text, err := cliutil.Prompt("Search query: ")
+	if err != nil {
+		return "", err
+	}
+	if text == "" {
+		return "", fmt.Errorf("query cannot be empty")
+	}
+	return t.search(modules.ActionContext{}, text, defaultLimit)
+}
+
+func (t Tool) search(ctx modules.ActionContext, text string, limit int) (string, error) {
+	var resp searchResponse
+	err := t.get(ctx, "/jokes/search", url.Values{"query": {text}}, &resp)
+	note := ""
+	if err == nil {
+		t.cache.add(resp.Result...)
+	} else {
+		if !isOffline(err) {
+			return "", err
+		}
+		cached, cacheErr := t.cache.load()
+		if cacheErr != nil {
+			return "", fmt.Errorf("%w (cache unavailable: %v)", err, cacheErr)
+		}
+		resp.Result = filterByText(cached, text)
+		resp.Total = len(resp.Result)
+		note = offlineNote(err)
+	}
+
+	if resp.Total == 0 {
+		return fmt.Sprintf("No facts found for %q.", text) + note, nil
+	}
+
+	var b strings.Builder
+	shown := resp.Result
+	if len(shown) > limit {
+		shown = shown[:limit]
+	}
+	fmt.Fprintf(&b, "Showing %d of %d facts matching %q:\n", len(shown), resp.Total, text)
+	for i, joke := range shown {
+		fmt.Fprintf(&b, "\n%d. %s\n   %s\n", i+1, joke.Value, joke.URL)
+	}
+	return strings.TrimRight(b.String(), "\n") + note, nil
+}
+
+type offlineError struct {
+	err error
+}
+
+func (e *offlineError) Error() string {
+	return fmt.Sprintf("request failed: %v", e.err)
+}
+
+func (e *offlineError) Unwrap() error {
+	return e.err
+}
+
+func isOffline(err error) bool {
+	var offline *offlineError
+	return errors.As(err, &offline)
+}
+
+func (t Tool) get(ctx modules.ActionContext, path string, query url.Values, out any) error {
+	if ctx.Params["offline"] == "true" {
+		return &offlineError{err: errors.New("offline mode requested")}
+	}
+
+	base := t.baseURL
+	if override := ctx.Params["base-url"]; override != "" {
+		base = strings.TrimRight(override, "/")
+	}
+	endpoint := base + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx.Context(), http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		if ctx.Context().Err() != nil {
+			return err
+		}
+		return &offlineError{err: err}
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound && query.Has("category") {
+		return modules.NotFoundError("unknown category %q (see the categories action)", query.Get("category"))
+	}
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status: %s", resp.Status)
+		return fmt.Errorf("unexpected status: %s", resp.Status)
 	}
 
-	var joke jokeResponse
-	if err := json.NewDecoder(resp.Body).Decode(&joke); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
+	return nil
+}
 
-	return fmt.Sprintf("%s\n\nSource: %s", joke.Value, joke.URL), nil
+func formatJoke(joke jokeResponse) string {
+	return fmt.Sprintf("%s\n\nSource: %s", joke.Value, joke.URL)
+}
+
+func offlineNote(err error) string {
+	return fmt.Sprintf("\n\n(offline: served from local cache; %v)", err)
+}
+
+func forCategory(category string) string {
+	if category == "" {
+		return ""
+	}
+	return fmt.Sprintf(" in category %q", category)
+}
+
+func filterByCategory(jokes []jokeResponse, category string) []jokeResponse {
+	if category == "" {
+		return jokes
+	}
+	matches := make([]jokeResponse, 0)
+	for _, joke := range jokes {
+		for _, c := range joke.Categories {
+			if c == category {
+				matches = append(matches, joke)
+				break
+			}
+		}
+	}
+	return matches
+}
+
+func filterByText(jokes []jokeResponse, text string) []jokeResponse {
+	text = strings.ToLower(text)
+	matches := make([]jokeResponse, 0)
+	for _, joke := range jokes {
+		if strings.Contains(strings.ToLower(joke.Value), text) {
+			matches = append(matches, joke)
+		}
+	}
+	return matches
 }
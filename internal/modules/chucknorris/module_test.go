@@ -0,0 +1,192 @@
+package chucknorris
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/devtoolstest"
+	"go-devtools/internal/modules"
+)
+
+var testJokes = []jokeResponse{
+	{ID: "a1", URL: "https://example.test/a1", Value: "Chuck Norris counted to infinity. Twice.", Categories: []string{"science"}},
+	{ID: "b2", URL: "https://example.test/b2", Value: "Chuck Norris can unit test an entire program with a single assert.", Categories: []string{"dev"}},
+	{ID: "c3", URL: "https://example.test/c3", Value: "Chuck Norris doesn't read books. He stares them down.", Categories: nil},
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jokes/categories", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]string{"dev", "science"})
+	})
+	mux.HandleFunc("/jokes/random", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("category") {
+		case "":
+			json.NewEncoder(w).Encode(testJokes[2])
+		case "science":
+			json.NewEncoder(w).Encode(testJokes[0])
+		case "dev":
+			json.NewEncoder(w).Encode(testJokes[1])
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	mux.HandleFunc("/jokes/search", func(w http.ResponseWriter, r *http.Request) {
+		query := strings.ToLower(r.URL.Query().Get("query"))
+		resp := searchResponse{Result: []jokeResponse{}}
+		for _, joke := range testJokes {
+			if strings.Contains(strings.ToLower(joke.Value), query) {
+				resp.Result = append(resp.Result, joke)
+			}
+		}
+		resp.Total = len(resp.Result)
+		json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("/broken/jokes/categories", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTool(t *testing.T, baseURL string) (modules.Tool, string) {
+	t.Helper()
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+	return NewWithConfig(Config{BaseURL: baseURL, CachePath: cachePath}), cachePath
+}
+
+func TestOnlineActions(t *testing.T) {
+	server := newServer(t)
+	tool, _ := newTool(t, server.URL)
+
+	tests := []struct {
+		name     string
+		action   string
+		params   map[string]string
+		args     []string
+		want     []string
+		wantErr  bool
+		wantKind modules.ErrorKind
+	}{
+		{name: "categories", action: "categories", want: []string{"dev\nscience"}},
+		{name: "random", action: "random-fact", want: []string{"stares them down", "Source: https://example.test/c3"}},
+		{name: "random by category", action: "random-fact", params: map[string]string{"category": "dev"}, want: []string{"single assert"}},
+		{name: "positional category", action: "random-fact", args: []string{"science"}, want: []string{"infinity"}},
+		{name: "unknown category", action: "random-fact", params: map[string]string{"category": "nope"}, wantErr: true, wantKind: modules.KindNotFound},
+		{name: "search", action: "search", params: map[string]string{"query": "chuck"}, want: []string{`Showing 3 of 3 facts matching "chuck"`, "1. Chuck Norris counted"}},
+		{name: "search limit", action: "search", params: map[string]string{"query": "chuck", "limit": "1"}, want: []string{"Showing 1 of 3"}},
+		{name: "search positional", action: "search", args: []string{"single", "assert"}, want: []string{"Showing 1 of 1"}},
+		{name: "search no results", action: "search", params: map[string]string{"query": "roundhouse"}, want: []string{`No facts found for "roundhouse".`}},
+		{name: "search missing query", action: "search", wantErr: true, wantKind: modules.KindUsage},
+		{name: "search bad limit", action: "search", params: map[string]string{"query": "chuck", "limit": "0"}, wantErr: true, wantKind: modules.KindUsage},
+		{name: "server error", action: "categories", params: map[string]string{"base-url": server.URL + "/broken"}, wantErr: true, wantKind: modules.KindActionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := devtoolstest.RunAction(t, tool, tt.action, tt.params, tt.args...)
+			if tt.wantErr {
+				if err == nil || modules.KindOf(err) != tt.wantKind {
+					t.Fatalf("error = %v (kind %v), want %v", err, modules.KindOf(err), tt.wantKind)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output missing %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestOfflineUsesCache(t *testing.T) {
+	server := newServer(t)
+	tool, _ := newTool(t, server.URL)
+
+	// Warm the cache with every fact, then stop the server.
+	if _, err := devtoolstest.RunAction(t, tool, "search", map[string]string{"query": "chuck"}); err != nil {
+		t.Fatalf("warming search error = %v", err)
+	}
+	server.Close()
+
+	tests := []struct {
+		name   string
+		action string
+		params map[string]string
+		want   []string
+	}{
+		{name: "categories", action: "categories", want: []string{"dev\nscience", "offline: served from local cache"}},
+		{name: "random by category", action: "random-fact", params: map[string]string{"category": "science"}, want: []string{"infinity", "offline"}},
+		{name: "search", action: "search", params: map[string]string{"query": "books"}, want: []string{"Showing 1 of 1", "stares them down", "offline"}},
+		{name: "forced offline", action: "search", params: map[string]string{"query": "assert", "offline": "true"}, want: []string{"single assert", "offline mode requested"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := devtoolstest.RunAction(t, tool, tt.action, tt.params)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output missing %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+
+	if _, err := devtoolstest.RunAction(t, tool, "random-fact", map[string]string{"category": "history"}); err == nil || !strings.Contains(err.Error(), `no cached facts in category "history"`) {
+		t.Errorf("random-fact for uncached category error = %v", err)
+	}
+}
+
+func TestOfflineWithEmptyCache(t *testing.T) {
+	tool, _ := newTool(t, "http://127.0.0.1:1")
+	for _, action := range []string{"random-fact", "categories"} {
+		if _, err := devtoolstest.RunAction(t, tool, action, map[string]string{"offline": "true"}); err == nil || !strings.Contains(err.Error(), "no cached facts") {
+			t.Errorf("%s error = %v, want no cached facts", action, err)
+		}
+	}
+}
+
+func TestCacheDeduplicatesAndCaps(t *testing.T) {
+	c := newCache(filepath.Join(t.TempDir(), "cache.json"))
+	c.add(testJokes...)
+	c.add(testJokes[0], jokeResponse{Value: "no id"})
+
+	cached, err := c.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cached) != len(testJokes) {
+		t.Errorf("cached %d facts, want %d", len(cached), len(testJokes))
+	}
+
+	many := make([]jokeResponse, cacheLimit+10)
+	for i := range many {
+		many[i] = jokeResponse{ID: strings.Repeat("x", i+1)}
+	}
+	c.add(many...)
+	if cached, _ = c.load(); len(cached) != cacheLimit {
+		t.Errorf("cached %d facts, want cap %d", len(cached), cacheLimit)
+	}
+}
+
+func TestBaseURLFromEnvironment(t *testing.T) {
+	devtoolstest.Isolate(t)
+	server := newServer(t)
+	t.Setenv(envBaseURL, server.URL+"/")
+
+	out, err := devtoolstest.RunAction(t, New(), "categories", nil)
+	if err != nil || out != "dev\nscience" {
+		t.Errorf("categories = %q, %v", out, err)
+	}
+}
@@ -0,0 +1,525 @@
+package cloudcli
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/modules"
+)
+
+type iniSection struct {
+	Name   string
+	Values map[string]string
+}
+
+type awsProfile struct {
+	Name              string
+	Region            string
+	Output            string
+	SSOSession        string
+	SSOStartURL       string
+	SSORegion         string
+	SSOAccountID      string
+	SSORoleName       string
+	RoleARN           string
+	SourceProfile     string
+	CredentialSource  string
+	CredentialProcess string
+	StaticKeys        bool
+	InConfig          bool
+	InCredentials     bool
+}
+
+func (p awsProfile) kind() string {
+	switch {
+	case p.RoleARN != "":
+		return "assume-role"
+	case p.SSOSession != "" || p.SSOStartURL != "":
+		return "sso"
+	case p.CredentialProcess != "":
+		return "process"
+	case p.StaticKeys:
+		return "static"
+	default:
+		return "config-only"
+	}
+}
+
+type awsSSOSession struct {
+	Name     string
+	StartURL string
+	Region   string
+	Scopes   string
+}
+
+type awsSSOToken struct {
+	File        string    `json:"-"`
+	StartURL    string    `json:"startUrl"`
+	Region      string    `json:"region"`
+	ExpiresAt   time.Time `json:"-"`
+	RawExpires  string    `json:"expiresAt"`
+	SessionName string    `json:"-"`
+}
+
+type awsFiles struct {
+	ConfigPath      string
+	CredentialsPath string
+	SSOCacheDir     string
+	Profiles        map[string]*awsProfile
+	Sessions        map[string]*awsSSOSession
+}
+
+func awsPaths(ctx modules.ActionContext) (string, string, string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil && (ctx.Params["config"] == "" || ctx.Params["credentials"] == "" || ctx.Params["sso-cache"] == "") {
+		return "", "", "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	config := cliutil.FirstNonEmpty(ctx.Params["config"], os.Getenv("AWS_CONFIG_FILE"), filepath.Join(home, ".aws", "config"))
+	credentials := cliutil.FirstNonEmpty(ctx.Params["credentials"], os.Getenv("AWS_SHARED_CREDENTIALS_FILE"), filepath.Join(home, ".aws", "credentials"))
+	cache := cliutil.FirstNonEmpty(ctx.Params["sso-cache"], filepath.Join(home, ".aws", "sso", "cache"))
+	return config, credentials, cache, nil
+}
+
+func loadAWSFiles(ctx modules.ActionContext) (*awsFiles, error) {
+	configPath, credentialsPath, cacheDir, err := awsPaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	files := &awsFiles{
+		ConfigPath:      configPath,
+		CredentialsPath: credentialsPath,
+		SSOCacheDir:     cacheDir,
+		Profiles:        map[string]*awsProfile{},
+		Sessions:        map[string]*awsSSOSession{},
+	}
+
+	configSections, err := parseINIFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, section := range configSections {
+		switch {
+		case section.Name == "default":
+			files.applyConfig("default", section.Values)
+		case strings.HasPrefix(section.Name, "profile "):
+			files.applyConfig(strings.TrimSpace(strings.TrimPrefix(section.Name, "profile ")), section.Values)
+		case strings.HasPrefix(section.Name, "sso-session "):
+			name := strings.TrimSpace(strings.TrimPrefix(section.Name, "sso-session "))
+			files.Sessions[name] = &awsSSOSession{
+				Name:     name,
+				StartURL: section.Values["sso_start_url"],
+				Region:   section.Values["sso_region"],
+				Scopes:   section.Values["sso_registration_scopes"],
+			}
+		}
+	}
+
+	credentialSections, err := parseINIFile(credentialsPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, section := range credentialSections {
+		profile := files.profile(section.Name)
+		profile.InCredentials = true
+		if section.Values["aws_access_key_id"] != "" {
+			profile.StaticKeys = true
+		}
+		if value := section.Values["role_arn"]; value != "" && profile.RoleARN == "" {
+			profile.RoleARN = value
+			profile.SourceProfile = section.Values["source_profile"]
+		}
+	}
+
+	if len(files.Profiles) == 0 && len(files.Sessions) == 0 {
+		return nil, modules.NotFoundError("no AWS profiles found in %s or %s", configPath, credentialsPath)
+	}
+	return files, nil
+}
+
+func (f *awsFiles) profile(name string) *awsProfile {
+	profile, ok := f.Profiles[name]
+	if !ok {
+		profile = &awsProfile{Name: name}
+		f.Profiles[name] = profile
+	}
+	return profile
+}
+
+func (f *awsFiles) applyConfig(name string, values map[string]string) {
+	profile := f.profile(name)
+	profile.InConfig = true
+	profile.Region = values["region"]
+	profile.Output = values["output"]
+	profile.SSOSession = values["sso_session"]
+	profile.SSOStartURL = values["sso_start_url"]
+	profile.SSORegion = values["sso_region"]
+	profile.SSOAccountID = values["sso_account_id"]
+	profile.SSORoleName = values["sso_role_name"]
+	profile.RoleARN = values["role_arn"]
+	profile.SourceProfile = values["source_profile"]
+	profile.CredentialSource = values["credential_source"]
+	profile.CredentialProcess = values["credential_process"]
+	if values["aws_access_key_id"] != "" {
+		profile.StaticKeys = true
+	}
+}
+
+func (f *awsFiles) names() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (f *awsFiles) roleChain(name string) ([]string, error) {
+	chain := []string{}
+	seen := map[string]bool{}
+	for name != "" {
+		if seen[name] {
+			chain = append(chain, name)
+			return chain, fmt.Errorf("source_profile cycle detected: %s", strings.Join(chain, " -> "))
+		}
+		seen[name] = true
+		chain = append(chain, name)
+
+		profile, ok := f.Profiles[name]
+		if !ok {
+			return chain, fmt.Errorf("profile %q is referenced but not defined", name)
+		}
+		if profile.RoleARN == "" {
+			break
+		}
+		if profile.SourceProfile == "" {
+			if profile.CredentialSource != "" {
+				chain = append(chain, "credential_source:"+profile.CredentialSource)
+			}
+			break
+		}
+		name = profile.SourceProfile
+	}
+	return chain, nil
+}
+
+func activeAWSProfile() (string, string) {
+	if name := os.Getenv("AWS_PROFILE"); name != "" {
+		return name, "AWS_PROFILE"
+	}
+	if name := os.Getenv("AWS_DEFAULT_PROFILE"); name != "" {
+		return name, "AWS_DEFAULT_PROFILE"
+	}
+	return "default", "default"
+}
+
+func parseINIFile(path string) ([]iniSection, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	sections := make([]iniSection, 0)
+	var current *iniSection
+	parent := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			sections = append(sections, iniSection{
+				Name:   strings.TrimSpace(line[1 : len(line)-1]),
+				Values: map[string]string{},
+			})
+			current = &sections[len(sections)-1]
+			parent = ""
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		indented := raw != strings.TrimLeft(raw, " \t")
+		switch {
+		case indented && parent != "":
+			current.Values[parent+"."+key] = value
+		case value == "":
+			parent = key
+		default:
+			parent = ""
+			current.Values[key] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return sections, nil
+}
+
+func loadSSOTokens(dir string, sessions map[string]*awsSSOSession) ([]awsSSOToken, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read SSO cache %s: %w", dir, err)
+	}
+
+	byHash := map[string]string{}
+	for name, session := range sessions {
+		byHash[sha1Hex(name)] = name
+		if session.StartURL != "" {
+			byHash[sha1Hex(session.StartURL)] = name
+		}
+	}
+
+	tokens := make([]awsSSOToken, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var token awsSSOToken
+		if err := json.Unmarshal(data, &token); err != nil || token.RawExpires == "" || token.StartURL == "" {
+			continue
+		}
+		token.File = path
+		token.ExpiresAt, err = parseAWSTime(token.RawExpires)
+		if err != nil {
+			continue
+		}
+		token.SessionName = byHash[strings.TrimSuffix(entry.Name(), ".json")]
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].ExpiresAt.Before(tokens[j].ExpiresAt) })
+	return tokens, nil
+}
+
+func parseAWSTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05UTC", "2006-01-02T15:04:05Z0700"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised time %q", value)
+}
+
+func sha1Hex(value string) string {
+	sum := sha1.Sum([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func showAWSProfiles(ctx modules.ActionContext) (string, error) {
+	files, err := loadAWSFiles(ctx)
+	if err != nil {
+		return "", err
+	}
+	active, _ := activeAWSProfile()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Config: %s\nCredentials: %s\n\n", files.ConfigPath, files.CredentialsPath)
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tPROFILE\tTYPE\tREGION\tDETAILS")
+	for _, name := range files.names() {
+		profile := files.Profiles[name]
+		marker := ""
+		if name == active {
+			marker = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", marker, name, profile.kind(), cliutil.Dash(profile.Region), profileDetails(profile))
+	}
+	_ = w.Flush()
+
+	if len(files.Sessions) > 0 {
+		b.WriteString("\nSSO sessions:\n")
+		w = tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		names := make([]string, 0, len(files.Sessions))
+		for name := range files.Sessions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			session := files.Sessions[name]
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", name, cliutil.Dash(session.StartURL), cliutil.Dash(session.Region))
+		}
+		_ = w.Flush()
+	}
+	b.WriteString("\n* = active profile")
+	return b.String(), nil
+}
+
+func profileDetails(p *awsProfile) string {
+	switch p.kind() {
+	case "assume-role":
+		source := p.SourceProfile
+		if source == "" {
+			source = "credential_source=" + p.CredentialSource
+		}
+		return fmt.Sprintf("%s via %s", p.RoleARN, source)
+	case "sso":
+		session := p.SSOSession
+		if session == "" {
+			session = p.SSOStartURL
+		}
+		return fmt.Sprintf("account %s role %s (session %s)", cliutil.Dash(p.SSOAccountID), cliutil.Dash(p.SSORoleName), session)
+	case "process":
+		return "credential_process"
+	case "static":
+		return "access keys in credentials file"
+	default:
+		return ""
+	}
+}
+
+func showAWSActive(ctx modules.ActionContext) (string, error) {
+	files, err := loadAWSFiles(ctx)
+	if err != nil {
+		return "", err
+	}
+	name, source := activeAWSProfile()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Active profile: %s (from %s)\n", name, source)
+
+	profile, ok := files.Profiles[name]
+	if !ok {
+		fmt.Fprintf(&b, "Warning: profile %q is not defined in %s or %s\n", name, files.ConfigPath, files.CredentialsPath)
+	} else {
+		fmt.Fprintf(&b, "Type: %s\n", profile.kind())
+		if details := profileDetails(profile); details != "" {
+			fmt.Fprintf(&b, "Details: %s\n", details)
+		}
+	}
+
+	region := cliutil.FirstNonEmpty(os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"))
+	switch {
+	case region != "":
+		fmt.Fprintf(&b, "Region: %s (from environment)\n", region)
+	case ok && profile.Region != "":
+		fmt.Fprintf(&b, "Region: %s (from profile)\n", profile.Region)
+	default:
+		b.WriteString("Region: not set\n")
+	}
+
+	if os.Getenv("AWS_ACCESS_KEY_ID") != "" {
+		b.WriteString("Note: AWS_ACCESS_KEY_ID is set; environment credentials take precedence over the profile.\n")
+		if os.Getenv("AWS_SESSION_TOKEN") != "" {
+			b.WriteString("Note: AWS_SESSION_TOKEN is set (temporary credentials).\n")
+		}
+	}
+
+	if ok && profile.RoleARN != "" {
+		chain, err := files.roleChain(name)
+		fmt.Fprintf(&b, "Role chain: %s\n", strings.Join(chain, " -> "))
+		if err != nil {
+			fmt.Fprintf(&b, "Warning: %v\n", err)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func showAWSRoleChain(ctx modules.ActionContext) (string, error) {
+	files, err := loadAWSFiles(ctx)
+	if err != nil {
+		return "", err
+	}
+	name := ctx.Params["profile"]
+	if name == "" && len(ctx.Positionals) > 0 {
+		name = ctx.Positionals[0]
+	}
+	if name == "" {
+		name, _ = activeAWSProfile()
+	}
+	if _, ok := files.Profiles[name]; !ok {
+		return "", modules.NotFoundError("unknown AWS profile %q", name)
+	}
+
+	chain, chainErr := files.roleChain(name)
+	var b strings.Builder
+	for i, link := range chain {
+		indent := strings.Repeat("  ", i)
+		profile, ok := files.Profiles[link]
+		switch {
+		case !ok:
+			fmt.Fprintf(&b, "%s%s\n", indent, link)
+		case profile.RoleARN != "":
+			fmt.Fprintf(&b, "%s%s assumes %s\n", indent, link, profile.RoleARN)
+		default:
+			fmt.Fprintf(&b, "%s%s (%s credentials)\n", indent, link, profile.kind())
+		}
+	}
+	if chainErr != nil {
+		return strings.TrimRight(b.String(), "\n"), chainErr
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func showAWSSSO(ctx modules.ActionContext) (string, error) {
+	files, err := loadAWSFiles(ctx)
+	if err != nil {
+		return "", err
+	}
+	tokens, err := loadSSOTokens(files.SSOCacheDir, files.Sessions)
+	if err != nil {
+		return "", err
+	}
+	if len(tokens) == 0 {
+		return fmt.Sprintf("No cached SSO tokens in %s.", files.SSOCacheDir), nil
+	}
+
+	now := time.Now()
+	var b strings.Builder
+	fmt.Fprintf(&b, "SSO cache: %s\n\n", files.SSOCacheDir)
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tSESSION\tSTART URL\tEXPIRES")
+	expired := 0
+	for _, token := range tokens {
+		status := "valid"
+		remaining := token.ExpiresAt.Sub(now)
+		when := fmt.Sprintf("%s (in %s)", token.ExpiresAt.Local().Format("2006-01-02 15:04"), humanDuration(remaining))
+		if !token.ExpiresAt.After(now) {
+			status = "EXPIRED"
+			expired++
+			when = fmt.Sprintf("%s (%s ago)", token.ExpiresAt.Local().Format("2006-01-02 15:04"), humanDuration(-remaining))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", status, cliutil.Dash(token.SessionName), token.StartURL, when)
+	}
+	_ = w.Flush()
+	if expired > 0 {
+		fmt.Fprintf(&b, "\n%d expired token(s); run `aws sso login` for the affected sessions.", expired)
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func humanDuration(d time.Duration) string {
+	if d >= 48*time.Hour {
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+	return d.Round(time.Minute).String()
+}
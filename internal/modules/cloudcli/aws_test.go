@@ -0,0 +1,248 @@
+package cloudcli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/modules"
+)
+
+func testAWSContext() modules.ActionContext {
+	dir := filepath.Join("testdata", "aws")
+	return modules.ActionContext{Params: map[string]string{
+		"config":      filepath.Join(dir, "config"),
+		"credentials": filepath.Join(dir, "credentials"),
+		"sso-cache":   filepath.Join(dir, "sso", "cache"),
+	}}
+}
+
+func clearAWSEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"AWS_PROFILE", "AWS_DEFAULT_PROFILE", "AWS_REGION", "AWS_DEFAULT_REGION", "AWS_ACCESS_KEY_ID", "AWS_SESSION_TOKEN"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestParseINIFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	content := strings.Join([]string{
+		"orphan = ignored before any section",
+		"# comment",
+		"; another comment",
+		"[ profile spaced ]",
+		"Region = eu-west-1",
+		"not a key value line",
+		"s3 =",
+		"  max_concurrent_requests = 20",
+		"\taddressing_style = path",
+		"output = json",
+		"  indented_without_parent = x",
+		"[empty]",
+	}, "\n")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	sections, err := parseINIFile(path)
+	if err != nil {
+		t.Fatalf("parseINIFile() error = %v", err)
+	}
+	want := []iniSection{
+		{Name: "profile spaced", Values: map[string]string{
+			"region":                     "eu-west-1",
+			"s3.max_concurrent_requests": "20",
+			"s3.addressing_style":        "path",
+			"output":                     "json",
+			"indented_without_parent":    "x",
+		}},
+		{Name: "empty", Values: map[string]string{}},
+	}
+	if !reflect.DeepEqual(sections, want) {
+		t.Errorf("parseINIFile() = %#v, want %#v", sections, want)
+	}
+
+	if sections, err := parseINIFile(filepath.Join(t.TempDir(), "missing")); err != nil || sections != nil {
+		t.Errorf("parseINIFile(missing) = %v, %v, want nil, nil", sections, err)
+	}
+}
+
+func TestLoadAWSFilesProfiles(t *testing.T) {
+	files, err := loadAWSFiles(testAWSContext())
+	if err != nil {
+		t.Fatalf("loadAWSFiles() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		kind          string
+		region        string
+		inConfig      bool
+		inCredentials bool
+	}{
+		{name: "default", kind: "static", region: "eu-west-1", inConfig: true, inCredentials: true},
+		{name: "ci", kind: "static", inCredentials: true},
+		{name: "dev", kind: "sso", region: "eu-central-1", inConfig: true},
+		{name: "legacy-sso", kind: "sso", inConfig: true},
+		{name: "admin", kind: "assume-role", inConfig: true},
+		{name: "ec2", kind: "assume-role", inConfig: true},
+		{name: "vault", kind: "process", inConfig: true},
+		{name: "s3-tuned", kind: "config-only", region: "us-east-1", inConfig: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, ok := files.Profiles[tt.name]
+			if !ok {
+				t.Fatalf("profile %q not loaded", tt.name)
+			}
+			if profile.kind() != tt.kind || profile.Region != tt.region || profile.InConfig != tt.inConfig || profile.InCredentials != tt.inCredentials {
+				t.Errorf("profile = %+v (kind %s), want kind %s region %q inConfig %v inCredentials %v",
+					profile, profile.kind(), tt.kind, tt.region, tt.inConfig, tt.inCredentials)
+			}
+		})
+	}
+
+	session := files.Sessions["corp"]
+	if session == nil || session.StartURL != "https://corp.awsapps.com/start" || session.Scopes != "sso:account:access" {
+		t.Errorf("sso-session corp = %+v", session)
+	}
+}
+
+func TestLoadAWSFilesEmpty(t *testing.T) {
+	dir := t.TempDir()
+	ctx := modules.ActionContext{Params: map[string]string{
+		"config":      filepath.Join(dir, "config"),
+		"credentials": filepath.Join(dir, "credentials"),
+		"sso-cache":   filepath.Join(dir, "cache"),
+	}}
+	if _, err := loadAWSFiles(ctx); modules.KindOf(err) != modules.KindNotFound {
+		t.Errorf("loadAWSFiles(empty) error = %v, want not found", err)
+	}
+}
+
+func TestRoleChain(t *testing.T) {
+	files, err := loadAWSFiles(testAWSContext())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		profile string
+		want    []string
+		wantErr string
+	}{
+		{profile: "dev", want: []string{"dev"}},
+		{profile: "admin", want: []string{"admin", "dev"}},
+		{profile: "break-glass", want: []string{"break-glass", "admin", "dev"}},
+		{profile: "ec2", want: []string{"ec2", "credential_source:Ec2InstanceMetadata"}},
+		{profile: "loop-a", want: []string{"loop-a", "loop-b", "loop-a"}, wantErr: "cycle detected"},
+		{profile: "orphan", want: []string{"orphan", "missing"}, wantErr: `"missing" is referenced but not defined`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			chain, err := files.roleChain(tt.profile)
+			if !reflect.DeepEqual(chain, tt.want) {
+				t.Errorf("roleChain() = %v, want %v", chain, tt.want)
+			}
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("roleChain() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("roleChain() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestActiveProfileResolution(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		wantName   string
+		wantSource string
+		want       []string
+	}{
+		{name: "default", wantName: "default", wantSource: "default", want: []string{"Type: static", "Region: eu-west-1 (from profile)"}},
+		{name: "AWS_PROFILE", env: map[string]string{"AWS_PROFILE": "break-glass", "AWS_DEFAULT_PROFILE": "dev"}, wantName: "break-glass", wantSource: "AWS_PROFILE",
+			want: []string{"Role chain: break-glass -> admin -> dev", "Region: us-west-2 (from profile)"}},
+		{name: "AWS_DEFAULT_PROFILE", env: map[string]string{"AWS_DEFAULT_PROFILE": "dev", "AWS_REGION": "ap-south-1"}, wantName: "dev", wantSource: "AWS_DEFAULT_PROFILE",
+			want: []string{"Type: sso", "Region: ap-south-1 (from environment)"}},
+		{name: "undefined", env: map[string]string{"AWS_PROFILE": "nope"}, wantName: "nope", wantSource: "AWS_PROFILE", want: []string{`Warning: profile "nope" is not defined`, "Region: not set"}},
+		{name: "env credentials", env: map[string]string{"AWS_ACCESS_KEY_ID": "AKIA", "AWS_SESSION_TOKEN": "token"}, wantName: "default", wantSource: "default",
+			want: []string{"environment credentials take precedence", "temporary credentials"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearAWSEnv(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			name, source := activeAWSProfile()
+			if name != tt.wantName || source != tt.wantSource {
+				t.Errorf("activeAWSProfile() = %q, %q, want %q, %q", name, source, tt.wantName, tt.wantSource)
+			}
+			out, err := showAWSActive(testAWSContext())
+			if err != nil {
+				t.Fatalf("showAWSActive() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output missing %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestShowAWSRoleChain(t *testing.T) {
+	clearAWSEnv(t)
+	ctx := testAWSContext()
+	ctx.Positionals = []string{"break-glass"}
+	out, err := showAWSRoleChain(ctx)
+	if err != nil {
+		t.Fatalf("showAWSRoleChain() error = %v", err)
+	}
+	want := "break-glass assumes arn:aws:iam::444444444444:role/BreakGlass\n" +
+		"  admin assumes arn:aws:iam::333333333333:role/Admin\n" +
+		"    dev (sso credentials)"
+	if out != want {
+		t.Errorf("showAWSRoleChain() =\n%s\nwant\n%s", out, want)
+	}
+
+	ctx.Positionals = []string{"unknown"}
+	if _, err := showAWSRoleChain(ctx); modules.KindOf(err) != modules.KindNotFound {
+		t.Errorf("showAWSRoleChain(unknown) error = %v, want not found", err)
+	}
+}
+
+func TestSSOTokens(t *testing.T) {
+	files, err := loadAWSFiles(testAWSContext())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := loadSSOTokens(files.SSOCacheDir, files.Sessions)
+	if err != nil {
+		t.Fatalf("loadSSOTokens() error = %v", err)
+	}
+	if len(tokens) != 2 {
+		t.Fatalf("loadSSOTokens() returned %d tokens, want 2 (client registrations and broken files skipped)", len(tokens))
+	}
+	if tokens[0].StartURL != "https://legacy.awsapps.com/start" || tokens[0].SessionName != "" {
+		t.Errorf("first token = %+v, want the expired legacy token", tokens[0])
+	}
+	if tokens[1].SessionName != "corp" {
+		t.Errorf("second token session = %q, want corp", tokens[1].SessionName)
+	}
+
+	out, err := showAWSSSO(testAWSContext())
+	if err != nil {
+		t.Fatalf("showAWSSSO() error = %v", err)
+	}
+	for _, want := range []string{"EXPIRED", "valid", "1 expired token(s)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
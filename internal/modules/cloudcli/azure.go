@@ -0,0 +1,370 @@
+package cloudcli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/safefile"
+)
+
+const azureProfileFile = "azureProfile.json"
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+type azureUser struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type azureSubscription struct {
+	ID                  string    `json:"id"`
+	Name                string    `json:"name"`
+	State               string    `json:"state"`
+	User                azureUser `json:"user"`
+	IsDefault           bool      `json:"isDefault"`
+	TenantID            string    `json:"tenantId"`
+	HomeTenantID        string    `json:"homeTenantId"`
+	TenantDisplayName   string    `json:"tenantDisplayName"`
+	TenantDefaultDomain string    `json:"tenantDefaultDomain"`
+	EnvironmentName     string    `json:"environmentName"`
+}
+
+// azureProfile keeps the raw subscription objects next to the decoded ones so
+// that switching the default rewrites only the isDefault flags and leaves
+// fields this tool does not know about untouched.
+type azureProfile struct {
+	Path          string
+	Dir           string
+	Subscriptions []azureSubscription
+	raw           map[string]json.RawMessage
+	rawSubs       []map[string]json.RawMessage
+	bom           bool
+}
+
+func azureConfigDir(ctx modules.ActionContext) (string, error) {
+	if dir := cliutil.FirstNonEmpty(ctx.Params["azure-dir"], os.Getenv("AZURE_CONFIG_DIR")); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".azure"), nil
+}
+
+func loadAzureProfile(ctx modules.ActionContext) (*azureProfile, error) {
+	dir, err := azureConfigDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, azureProfileFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, modules.NotFoundError("no Azure profile at %s (run `az login` first)", path)
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	profile := &azureProfile{Path: path, Dir: dir}
+	if bytes.HasPrefix(data, utf8BOM) {
+		profile.bom = true
+		data = data[len(utf8BOM):]
+	}
+	if err := json.Unmarshal(data, &profile.raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if subs, ok := profile.raw["subscriptions"]; ok {
+		var rawSubs []map[string]json.RawMessage
+		if err := json.Unmarshal(subs, &rawSubs); err != nil {
+			return nil, fmt.Errorf("failed to parse subscriptions in %s: %w", path, err)
+		}
+		// null entries carry no subscription; they are dropped here and so
+		// also from the file when the default is switched.
+		for _, raw := range rawSubs {
+			if raw == nil {
+				continue
+			}
+			encoded, err := json.Marshal(raw)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse subscriptions in %s: %w", path, err)
+			}
+			var sub azureSubscription
+			if err := json.Unmarshal(encoded, &sub); err != nil {
+				return nil, fmt.Errorf("failed to parse subscriptions in %s: %w", path, err)
+			}
+			profile.rawSubs = append(profile.rawSubs, raw)
+			profile.Subscriptions = append(profile.Subscriptions, sub)
+		}
+	}
+	return profile, nil
+}
+
+func (p *azureProfile) defaultSubscription() (azureSubscription, bool) {
+	for _, sub := range p.Subscriptions {
+		if sub.IsDefault {
+			return sub, true
+		}
+	}
+	return azureSubscription{}, false
+}
+
+// find matches a subscription by id, by exact name, or by 1-based position in
+// the listing, in that order.
+func (p *azureProfile) find(query string) (int, error) {
+	for i, sub := range p.Subscriptions {
+		if strings.EqualFold(sub.ID, query) {
+			return i, nil
+		}
+	}
+	matches := make([]int, 0)
+	for i, sub := range p.Subscriptions {
+		if strings.EqualFold(sub.Name, query) {
+			matches = append(matches, i)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+	default:
+		return 0, modules.UsageError("subscription name %q is ambiguous (%d matches); use the subscription id", query, len(matches))
+	}
+	if n, err := strconv.Atoi(query); err == nil && n >= 1 && n <= len(p.Subscriptions) {
+		return n - 1, nil
+	}
+	return 0, modules.NotFoundError("unknown Azure subscription %q", query)
+}
+
+func (p *azureProfile) setDefault(index int) error {
+	for i := range p.Subscriptions {
+		isDefault := i == index
+		p.Subscriptions[i].IsDefault = isDefault
+		p.rawSubs[i]["isDefault"] = json.RawMessage(strconv.FormatBool(isDefault))
+	}
+	subs, err := json.Marshal(p.rawSubs)
+	if err != nil {
+		return fmt.Errorf("failed to encode subscriptions: %w", err)
+	}
+	p.raw["subscriptions"] = subs
+	data, err := json.Marshal(p.raw)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", p.Path, err)
+	}
+	if p.bom {
+		data = append(append([]byte{}, utf8BOM...), data...)
+	}
+	if _, err := safefile.Backup(p.Path); err != nil {
+		return err
+	}
+	return safefile.Write(p.Path, data)
+}
+
+func azureCLIConfig(dir string) map[string]string {
+	values := map[string]string{}
+	sections, err := parseINIFile(filepath.Join(dir, "config"))
+	if err != nil {
+		return values
+	}
+	for _, section := range sections {
+		for key, value := range section.Values {
+			values[section.Name+"."+key] = value
+		}
+	}
+	return values
+}
+
+func tenantLabel(sub azureSubscription) string {
+	name := cliutil.FirstNonEmpty(sub.TenantDisplayName, sub.TenantDefaultDomain)
+	if name == "" {
+		return sub.TenantID
+	}
+	return fmt.Sprintf("%s (%s)", name, sub.TenantID)
+}
+
+func showAzureSubscriptions(ctx modules.ActionContext) (string, error) {
+	profile, err := loadAzureProfile(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(profile.Subscriptions) == 0 {
+		return fmt.Sprintf("No subscriptions in %s (run `az login`).", profile.Path), nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Profile: %s\n\n", profile.Path)
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\t#\tNAME\tSUBSCRIPTION ID\tSTATE\tTENANT\tUSER")
+	for i, sub := range profile.Subscriptions {
+		marker := ""
+		if sub.IsDefault {
+			marker = "*"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n", marker, i+1, sub.Name, sub.ID, cliutil.Dash(sub.State), tenantLabel(sub), cliutil.Dash(sub.User.Name))
+	}
+	_ = w.Flush()
+	b.WriteString("\n* = default subscription")
+	return b.String(), nil
+}
+
+func showAzureTenants(ctx modules.ActionContext) (string, error) {
+	profile, err := loadAzureProfile(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	type tenant struct {
+		label string
+		count int
+		home  bool
+	}
+	tenants := map[string]*tenant{}
+	for _, sub := range profile.Subscriptions {
+		t, ok := tenants[sub.TenantID]
+		if !ok {
+			t = &tenant{label: tenantLabel(sub)}
+			tenants[sub.TenantID] = t
+		}
+		t.count++
+		if sub.HomeTenantID != "" && sub.HomeTenantID == sub.TenantID {
+			t.home = true
+		}
+	}
+	if len(tenants) == 0 {
+		return fmt.Sprintf("No tenants in %s (run `az login`).", profile.Path), nil
+	}
+
+	ids := make([]string, 0, len(tenants))
+	for id := range tenants {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return tenants[ids[i]].label < tenants[ids[j]].label })
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TENANT\tSUBSCRIPTIONS\tHOME")
+	for _, id := range ids {
+		t := tenants[id]
+		home := ""
+		if t.home {
+			home = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", t.label, t.count, cliutil.Dash(home))
+	}
+	_ = w.Flush()
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func showAzureActive(ctx modules.ActionContext) (string, error) {
+	profile, err := loadAzureProfile(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	sub, ok := profile.defaultSubscription()
+	if !ok {
+		fmt.Fprintf(&b, "No default subscription in %s.\n", profile.Path)
+	} else {
+		fmt.Fprintf(&b, "Subscription: %s (%s)\n", sub.Name, sub.ID)
+		fmt.Fprintf(&b, "Tenant: %s\n", tenantLabel(sub))
+		fmt.Fprintf(&b, "User: %s (%s)\n", cliutil.Dash(sub.User.Name), cliutil.Dash(sub.User.Type))
+		fmt.Fprintf(&b, "Cloud: %s\n", cliutil.Dash(sub.EnvironmentName))
+		if sub.State != "" && sub.State != "Enabled" {
+			fmt.Fprintf(&b, "Warning: subscription state is %s\n", sub.State)
+		}
+	}
+
+	config := azureCLIConfig(profile.Dir)
+	if group := config["defaults.group"]; group != "" {
+		fmt.Fprintf(&b, "Default resource group: %s\n", group)
+	}
+	if location := config["defaults.location"]; location != "" {
+		fmt.Fprintf(&b, "Default location: %s\n", location)
+	}
+
+	if env := os.Getenv("AZURE_SUBSCRIPTION_ID"); env != "" && (!ok || !strings.EqualFold(env, sub.ID)) {
+		fmt.Fprintf(&b, "Note: AZURE_SUBSCRIPTION_ID=%s overrides the default for SDKs and Terraform.\n", env)
+	}
+	if os.Getenv("AZURE_CLIENT_ID") != "" {
+		b.WriteString("Note: AZURE_CLIENT_ID is set; SDK credential chains will use the service principal.\n")
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func setAzureDefault(ctx modules.ActionContext) (string, error) {
+	query := ctx.Params["subscription"]
+	if query == "" && len(ctx.Positionals) > 0 {
+		query = ctx.Positionals[0]
+	}
+	if query == "" {
+		return "", modules.UsageError("missing subscription (id, name or list number)")
+	}
+
+	profile, err := loadAzureProfile(ctx)
+	if err != nil {
+		return "", err
+	}
+	index, err := profile.find(query)
+	if err != nil {
+		return "", err
+	}
+	return switchAzureDefault(profile, index)
+}
+
+func switchAzureDefault(profile *azureProfile, index int) (string, error) {
+	previous, hadDefault := profile.defaultSubscription()
+	target := profile.Subscriptions[index]
+	if hadDefault && previous.ID == target.ID {
+		return fmt.Sprintf("%s (%s) is already the default subscription.", target.Name, target.ID), nil
+	}
+	if err := profile.setDefault(index); err != nil {
+		return "", err
+	}
+
+	out := fmt.Sprintf("Default subscription set to %s (%s) in tenant %s.\nBackup: %s.bak", target.Name, target.ID, tenantLabel(target), profile.Path)
+	if hadDefault {
+		out += fmt.Sprintf("\nPrevious default: %s (%s)", previous.Name, previous.ID)
+	}
+	return out, nil
+}
+
+func switchAzurePrompt() (string, error) {
+	profile, err := loadAzureProfile(modules.ActionContext{})
+	if err != nil {
+		return "", err
+	}
+	if len(profile.Subscriptions) == 0 {
+		return "", modules.NotFoundError("no subscriptions in %s (run `az login`)", profile.Path)
+	}
+
+	for i, sub := range profile.Subscriptions {
+		marker := " "
+		if sub.IsDefault {
+			marker = "*"
+		}
+		fmt.Printf("%s %2d. %s (%s)\n", marker, i+1, sub.Name, sub.ID)
+	}
+	query, err := cliutil.Prompt("Subscription number, name or id: ")
+	if err != nil {
+		return "", err
+	}
+	if query == "" {
+		return "", fmt.Errorf("subscription cannot be empty")
+	}
+
+	index, err := profile.find(query)
+	if err != nil {
+		return "", err
+	}
+	return switchAzureDefault(profile, index)
+}
@@ -0,0 +1,165 @@
+package cloudcli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/modules"
+)
+
+func testAzureContext(dir string) modules.ActionContext {
+	return modules.ActionContext{Params: map[string]string{"azure-dir": dir}}
+}
+
+// copyAzureFixture copies testdata/azure into a temporary directory so tests
+// that switch the default do not modify the fixture.
+func copyAzureFixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{azureProfileFile, "config"} {
+		data, err := os.ReadFile(filepath.Join("testdata", "azure", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadAzureProfileSkipsNullSubscriptions(t *testing.T) {
+	profile, err := loadAzureProfile(testAzureContext(filepath.Join("testdata", "azure")))
+	if err != nil {
+		t.Fatalf("loadAzureProfile() error = %v", err)
+	}
+	if !profile.bom {
+		t.Error("BOM not detected")
+	}
+	if len(profile.Subscriptions) != 4 || len(profile.rawSubs) != 4 {
+		t.Fatalf("got %d subscriptions (%d raw), want 4", len(profile.Subscriptions), len(profile.rawSubs))
+	}
+	if sub, ok := profile.defaultSubscription(); !ok || sub.Name != "Dev" {
+		t.Errorf("defaultSubscription() = %+v, %v", sub, ok)
+	}
+
+	if _, err := loadAzureProfile(testAzureContext(t.TempDir())); modules.KindOf(err) != modules.KindNotFound {
+		t.Errorf("missing profile error = %v, want not found", err)
+	}
+}
+
+func TestAzureProfileFind(t *testing.T) {
+	profile, err := loadAzureProfile(testAzureContext(filepath.Join("testdata", "azure")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		query string
+		want  int
+		kind  modules.ErrorKind
+	}{
+		{query: "22222222-2222-2222-2222-222222222222", want: 1},
+		{query: "prod", want: 1},
+		{query: "1", want: 0},
+		{query: "4", want: 3},
+		{query: "Shared", kind: modules.KindUsage},
+		{query: "5", kind: modules.KindNotFound},
+		{query: "staging", kind: modules.KindNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := profile.find(tt.query)
+			if tt.kind != modules.KindActionFailed {
+				if modules.KindOf(err) != tt.kind {
+					t.Fatalf("find(%q) error = %v, want %v", tt.query, err, tt.kind)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("find(%q) = %d, %v, want %d", tt.query, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestShowAzureActions(t *testing.T) {
+	t.Setenv("AZURE_SUBSCRIPTION_ID", "")
+	t.Setenv("AZURE_CLIENT_ID", "")
+	ctx := testAzureContext(filepath.Join("testdata", "azure"))
+
+	tests := []struct {
+		name string
+		run  func(modules.ActionContext) (string, error)
+		want []string
+	}{
+		{"subscriptions", showAzureSubscriptions, []string{"* 1 Dev", "2 Prod", "Disabled", "fabrikam.onmicrosoft.com (bbbbbbbb-0000-0000-0000-000000000002)"}},
+		{"tenants", showAzureTenants, []string{"Contoso (aaaaaaaa-0000-0000-0000-000000000001) 3 yes", "fabrikam.onmicrosoft.com (bbbbbbbb-0000-0000-0000-000000000002) 1 -"}},
+		{"active", showAzureActive, []string{"Subscription: Dev (11111111-1111-1111-1111-111111111111)", "User: dev@example.com (user)", "Default resource group: dev-rg", "Default location: westeurope"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := tt.run(ctx)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			// Compare with table padding collapsed to single spaces.
+			words := strings.Join(strings.Fields(out), " ")
+			for _, want := range tt.want {
+				if !strings.Contains(words, want) {
+					t.Errorf("output missing %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestSetAzureDefaultBacksUpAndKeepsUnknownFields(t *testing.T) {
+	dir := copyAzureFixture(t)
+	path := filepath.Join(dir, azureProfileFile)
+	original, _ := os.ReadFile(path)
+
+	ctx := testAzureContext(dir)
+	ctx.Positionals = []string{"Prod"}
+	out, err := setAzureDefault(ctx)
+	if err != nil {
+		t.Fatalf("setAzureDefault() error = %v", err)
+	}
+	if !strings.Contains(out, "Default subscription set to Prod") || !strings.Contains(out, "Previous default: Dev") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	if backup, _ := os.ReadFile(path + ".bak"); !bytes.Equal(backup, original) {
+		t.Error("backup does not match the original profile")
+	}
+	data, _ := os.ReadFile(path)
+	if !bytes.HasPrefix(data, utf8BOM) {
+		t.Error("BOM not preserved")
+	}
+	var raw struct {
+		InstallationID string                       `json:"installationId"`
+		Subscriptions  []map[string]json.RawMessage `json:"subscriptions"`
+	}
+	if err := json.Unmarshal(data[len(utf8BOM):], &raw); err != nil {
+		t.Fatal(err)
+	}
+	if raw.InstallationID == "" || raw.Subscriptions[0]["managedByTenants"] == nil {
+		t.Error("unknown fields were dropped")
+	}
+	for i, want := range []string{"false", "true", "false", "false"} {
+		if got := string(raw.Subscriptions[i]["isDefault"]); got != want {
+			t.Errorf("subscription %d isDefault = %s, want %s", i, got, want)
+		}
+	}
+
+	ctx.Positionals = []string{"2"}
+	if out, err := setAzureDefault(ctx); err != nil || !strings.Contains(out, "already the default") {
+		t.Errorf("second switch = %q, %v", out, err)
+	}
+	if _, err := setAzureDefault(testAzureContext(dir)); modules.KindOf(err) != modules.KindUsage {
+		t.Errorf("missing subscription error = %v, want usage error", err)
+	}
+}
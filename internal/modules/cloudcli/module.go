@@ -1,14 +1,13 @@
 package cloudcli
 
 import (
-	"bytes"
-	"fmt"
-	"os/exec"
 	"strings"
 
+	"go-devtools/internal/cliutil"
 	"go-devtools/internal/menu"
 	"go-devtools/internal/modules"
 	"go-devtools/internal/requirements"
+	"go-devtools/internal/toolversions"
 )
 
 type Tool struct{}
@@ -21,12 +20,26 @@ func (Tool) ID() string { return "cloud-cli-checks" }
 
 func (Tool) Label() string { return "Cloud CLI Checks" }
 
-func (Tool) Description() string { return "AWS/Azure CLI checks with install actions" }
+func (Tool) Description() string { return "Cloud and dev CLI checks and versions" }
 
 func (Tool) Requirements() []requirements.Check { return nil }
 
 func (Tool) Actions() []modules.Action {
 	return []modules.Action{
+		{
+			ID:          "versions",
+			Label:       "Show tool versions",
+			Description: "Check installed CLI versions in parallel, comparing against .tool-versions when present",
+			Usage:       "devtools run cloud-cli-checks versions [--tool-versions <file>]",
+			Run:         showVersions,
+		},
+		{
+			ID:          "tool-version",
+			Label:       "Show one tool version",
+			Description: "Run the version command of a registered tool",
+			Usage:       "devtools run cloud-cli-checks tool-version <aws|az|gcloud|kubectl|terraform|docker|node|python|go|git>",
+			Run:         showToolVersion,
+		},
 		{
 			ID:          "aws-version",
 			Label:       "Show aws version",
@@ -41,6 +54,62 @@ func (Tool) Actions() []modules.Action {
 			Usage:       "devtools run cloud-cli-checks azure-version",
 			Run:         showAzureVersion,
 		},
+		{
+			ID:          "aws-profiles",
+			Label:       "List AWS profiles",
+			Description: "Parse ~/.aws/config and ~/.aws/credentials and list profiles and SSO sessions",
+			Usage:       "devtools run cloud-cli-checks aws-profiles [--config <file>] [--credentials <file>]",
+			Run:         showAWSProfiles,
+		},
+		{
+			ID:          "aws-active",
+			Label:       "Show active AWS profile",
+			Description: "Resolve the active profile and region from AWS_PROFILE and environment variables",
+			Usage:       "devtools run cloud-cli-checks aws-active [--config <file>] [--credentials <file>]",
+			Run:         showAWSActive,
+		},
+		{
+			ID:          "aws-role-chain",
+			Label:       "Show AWS role chain",
+			Description: "Follow source_profile links for an assume-role profile",
+			Usage:       "devtools run cloud-cli-checks aws-role-chain [profile] [--config <file>] [--credentials <file>]",
+			Run:         showAWSRoleChain,
+		},
+		{
+			ID:          "aws-sso-tokens",
+			Label:       "Check AWS SSO tokens",
+			Description: "Flag expired cached SSO tokens in ~/.aws/sso/cache",
+			Usage:       "devtools run cloud-cli-checks aws-sso-tokens [--config <file>] [--credentials <file>] [--sso-cache <dir>]",
+			Run:         showAWSSSO,
+		},
+		{
+			ID:          "azure-subscriptions",
+			Label:       "List Azure subscriptions",
+			Description: "Parse ~/.azure/azureProfile.json and list subscriptions",
+			Usage:       "devtools run cloud-cli-checks azure-subscriptions [--azure-dir <dir>]",
+			Run:         showAzureSubscriptions,
+		},
+		{
+			ID:          "azure-tenants",
+			Label:       "List Azure tenants",
+			Description: "Group Azure subscriptions by tenant",
+			Usage:       "devtools run cloud-cli-checks azure-tenants [--azure-dir <dir>]",
+			Run:         showAzureTenants,
+		},
+		{
+			ID:          "azure-active",
+			Label:       "Show default Azure subscription",
+			Description: "Show the default subscription, tenant, user and CLI defaults",
+			Usage:       "devtools run cloud-cli-checks azure-active [--azure-dir <dir>]",
+			Run:         showAzureActive,
+		},
+		{
+			ID:          "azure-set-default",
+			Label:       "Switch default Azure subscription",
+			Description: "Rewrite azureProfile.json so the given subscription is the default",
+			Usage:       "devtools run cloud-cli-checks azure-set-default <id|name|number> [--azure-dir <dir>]",
+			Run:         setAzureDefault,
+		},
 	}
 }
 
@@ -52,6 +121,22 @@ func (Tool) Menu() *menu.Menu {
 		WithBack().
 		Build()
 
+	awsProfilesMenu := menu.NewBuilder("Cloud CLI / AWS profiles").
+		Action("List AWS profiles", "Profiles, regions and SSO sessions", func() (string, error) {
+			return showAWSProfiles(modules.ActionContext{})
+		}).
+		Action("Show active AWS profile", "Resolved from AWS_PROFILE and env vars", func() (string, error) {
+			return showAWSActive(modules.ActionContext{})
+		}).
+		Action("Show AWS role chain", "source_profile chain of the active profile", func() (string, error) {
+			return showAWSRoleChain(modules.ActionContext{})
+		}).
+		Action("Check AWS SSO tokens", "Flags expired tokens in ~/.aws/sso/cache", func() (string, error) {
+			return showAWSSSO(modules.ActionContext{})
+		}).
+		WithBack().
+		Build()
+
 	azureMenu := menu.NewBuilder("Cloud CLI / Azure").
 		Action("Show az version", "Runs az version", func() (string, error) {
 			return showAzureVersion(modules.ActionContext{})
@@ -59,38 +144,85 @@ func (Tool) Menu() *menu.Menu {
 		WithBack().
 		Build()
 
+	azureProfileMenu := menu.NewBuilder("Cloud CLI / Azure profile").
+		Action("Show default Azure subscription", "Subscription, tenant and user az will use", func() (string, error) {
+			return showAzureActive(modules.ActionContext{})
+		}).
+		Action("List Azure subscriptions", "All subscriptions in azureProfile.json", func() (string, error) {
+			return showAzureSubscriptions(modules.ActionContext{})
+		}).
+		Action("List Azure tenants", "Subscriptions grouped by tenant", func() (string, error) {
+			return showAzureTenants(modules.ActionContext{})
+		}).
+		Action("Switch default Azure subscription", "Prompt for a subscription", switchAzurePrompt).
+		WithBack().
+		Build()
+
 	return menu.NewBuilder("Cloud CLI Checks").
-		SubMenu("AWS CLI", "Requires aws command", awsMenu, requirements.CommandExistsWithBrew("aws", "awscli")).
-		SubMenu("Azure CLI", "Requires az command", azureMenu, requirements.CommandExistsWithBrew("az", "azure-cli")).
+		Action("Tool versions", "Installed CLI versions vs .tool-versions", func() (string, error) {
+			return showVersions(modules.ActionContext{})
+		}).
+		SubMenu("AWS CLI", "Requires aws command", awsMenu, toolRequirement("aws")).
+		SubMenu("AWS profiles", "Reads ~/.aws files directly", awsProfilesMenu).
+		SubMenu("Azure CLI", "Requires az command", azureMenu, toolRequirement("az")).
+		SubMenu("Azure profile", "Reads ~/.azure files directly", azureProfileMenu).
 		WithBack().
 		Build()
 }
 
 func showAWSVersion(_ modules.ActionContext) (string, error) {
-	return runCommand("aws", "--version")
+	return runRegisteredTool("aws")
 }
 
 func showAzureVersion(_ modules.ActionContext) (string, error) {
-	return runCommand("az", "version")
+	return runRegisteredTool("az")
 }
 
-func runCommand(name string, args ...string) (string, error) {
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
+func showToolVersion(ctx modules.ActionContext) (string, error) {
+	name := ctx.Params["tool"]
+	if name == "" && len(ctx.Positionals) > 0 {
+		name = ctx.Positionals[0]
+	}
+	if name == "" {
+		return "", modules.UsageError("missing tool name (one of %s)", strings.Join(toolversions.Names(), ", "))
+	}
+	spec, err := lookupTool(name)
+	if err != nil {
+		return "", err
+	}
+	if err := spec.Requirement().Run(); err != nil {
+		return "", modules.RequirementError("%w", err)
+	}
+	return runTool(spec)
+}
 
-	cmd := exec.Command(name, args...)
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("%s %s failed: %w (%s)", name, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+func lookupTool(name string) (toolversions.Spec, error) {
+	spec, ok := toolversions.Lookup(name)
+	if !ok {
+		return toolversions.Spec{}, modules.NotFoundError("unknown tool %q (one of %s)", name, strings.Join(toolversions.Names(), ", "))
 	}
+	return spec, nil
+}
 
-	out := strings.TrimSpace(stdout.String())
-	if out == "" {
-		out = strings.TrimSpace(stderr.String())
+// toolRequirement returns the registry check for name. A name missing from
+// the registry becomes a check that always fails instead of a panic at
+// menu build time.
+func toolRequirement(name string) requirements.Check {
+	spec, err := lookupTool(name)
+	if err != nil {
+		return requirements.Check{Name: name, Validate: func() error { return err }}
 	}
-	if out == "" {
-		out = "Command completed with no output."
+	return spec.Requirement()
+}
+
+func runRegisteredTool(name string) (string, error) {
+	spec, err := lookupTool(name)
+	if err != nil {
+		return "", err
 	}
-	return out, nil
+	return runTool(spec)
+}
+
+func runTool(spec toolversions.Spec) (string, error) {
+	return cliutil.Run(spec.Command, spec.Args...)
 }
@@ -0,0 +1,143 @@
+package cloudcli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/toolversions"
+)
+
+const toolVersionsFile = ".tool-versions"
+
+// findToolVersions reports the .tool-versions file in dir. Parent
+// directories are not searched, so a file higher up (for example in $HOME)
+// does not turn an unrelated run into a drift check.
+func findToolVersions(dir string) (string, bool) {
+	path := filepath.Join(dir, toolVersionsFile)
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return "", false
+	}
+	return path, true
+}
+
+// parseToolVersions reads the asdf format: one tool per line followed by one
+// or more acceptable versions, with # comments.
+func parseToolVersions(path string) (map[string][]string, []string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, modules.NotFoundError("tool versions file %s does not exist", path)
+		}
+		return nil, nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	versions := map[string][]string{}
+	order := make([]string, 0)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if _, ok := versions[fields[0]]; !ok {
+			order = append(order, fields[0])
+		}
+		versions[fields[0]] = fields[1:]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return versions, order, nil
+}
+
+// versionMatches accepts an exact match or a prefix on a version boundary,
+// so "1.22" accepts "1.22.3". "system" and "latest" accept anything.
+func versionMatches(installed string, expected []string) bool {
+	installed = strings.TrimPrefix(installed, "v")
+	for _, want := range expected {
+		want = strings.TrimPrefix(want, "v")
+		if want == "system" || strings.HasPrefix(want, "latest") || strings.HasPrefix(want, "ref:") || strings.HasPrefix(want, "path:") {
+			return true
+		}
+		if installed == want || strings.HasPrefix(installed, want+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func showVersions(ctx modules.ActionContext) (string, error) {
+	path := ctx.Params["tool-versions"]
+	if path == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get working directory: %w", err)
+		}
+		path, _ = findToolVersions(cwd)
+	}
+
+	expected := map[string][]string{}
+	untracked := make([]string, 0)
+	if path != "" {
+		versions, order, err := parseToolVersions(path)
+		if err != nil {
+			return "", err
+		}
+		for _, name := range order {
+			spec, ok := toolversions.Lookup(name)
+			if !ok {
+				untracked = append(untracked, name)
+				continue
+			}
+			expected[spec.Name] = versions[name]
+		}
+	}
+
+	results := toolversions.Check(toolversions.Registry)
+	var b strings.Builder
+	if path != "" {
+		fmt.Fprintf(&b, "Expected versions: %s\n\n", path)
+	}
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TOOL\tSTATUS\tVERSION\tEXPECTED")
+	drift := make([]string, 0)
+	for _, result := range results {
+		want, pinned := expected[result.Spec.Name]
+		status := "installed"
+		version := result.Version
+		switch {
+		case !result.Installed:
+			status = "missing"
+		case result.Err != nil:
+			status = "error"
+			version = result.Err.Error()
+		}
+		if pinned {
+			if result.Installed && result.Err == nil && versionMatches(result.Version, want) {
+				status = "ok"
+			} else {
+				status = "DRIFT (" + status + ")"
+				drift = append(drift, result.Spec.Name)
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Spec.Name, status, cliutil.Dash(version), cliutil.Dash(strings.Join(want, " ")))
+	}
+	_ = w.Flush()
+
+	if len(untracked) > 0 {
+		fmt.Fprintf(&b, "\nNot checked (no registry entry): %s\n", strings.Join(untracked, ", "))
+	}
+	out := strings.TrimRight(b.String(), "\n")
+	if len(drift) > 0 {
+		return out, fmt.Errorf("%d tool(s) do not match %s: %s", len(drift), path, strings.Join(drift, ", "))
+	}
+	return out, nil
+}
@@ -0,0 +1,69 @@
+package cloudcli
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go-devtools/internal/modules"
+)
+
+func TestFindToolVersionsOnlyInDir(t *testing.T) {
+	dir := filepath.Join("testdata", "tools")
+	if got, ok := findToolVersions(dir); !ok || got != filepath.Join(dir, toolVersionsFile) {
+		t.Errorf("findToolVersions(%q) = %q, %v", dir, got, ok)
+	}
+	if got, ok := findToolVersions(filepath.Join(dir, "nested")); ok {
+		t.Errorf("findToolVersions(nested) = %q, want no match from the parent directory", got)
+	}
+}
+
+func TestParseToolVersions(t *testing.T) {
+	versions, order, err := parseToolVersions(filepath.Join("testdata", "tools", toolVersionsFile))
+	if err != nil {
+		t.Fatalf("parseToolVersions() error = %v", err)
+	}
+	if want := []string{"terraform", "nodejs", "python", "rust"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+	if want := []string{"20.11", "18"}; !reflect.DeepEqual(versions["nodejs"], want) {
+		t.Errorf("nodejs = %v, want %v", versions["nodejs"], want)
+	}
+
+	if _, _, err := parseToolVersions(filepath.Join("testdata", "tools", "missing")); modules.KindOf(err) != modules.KindNotFound {
+		t.Errorf("parseToolVersions(missing) error = %v, want not found", err)
+	}
+}
+
+func TestVersionMatches(t *testing.T) {
+	tests := []struct {
+		installed string
+		expected  []string
+		want      bool
+	}{
+		{installed: "1.22.3", expected: []string{"1.22"}, want: true},
+		{installed: "1.22.3", expected: []string{"1.22.3"}, want: true},
+		{installed: "v20.11.1", expected: []string{"18", "20.11"}, want: true},
+		{installed: "1.220.0", expected: []string{"1.22"}, want: false},
+		{installed: "1.21.0", expected: []string{"1.22"}, want: false},
+		{installed: "3.12.1", expected: []string{"system"}, want: true},
+		{installed: "3.12.1", expected: []string{"latest:3"}, want: true},
+	}
+	for _, tt := range tests {
+		if got := versionMatches(tt.installed, tt.expected); got != tt.want {
+			t.Errorf("versionMatches(%q, %v) = %v, want %v", tt.installed, tt.expected, got, tt.want)
+		}
+	}
+}
+
+func TestUnknownToolIsAnError(t *testing.T) {
+	if _, err := lookupTool("rust"); modules.KindOf(err) != modules.KindNotFound {
+		t.Errorf("lookupTool(rust) error = %v, want not found", err)
+	}
+	if err := toolRequirement("rust").Run(); err == nil {
+		t.Error("toolRequirement(rust).Run() = nil, want an error")
+	}
+	if _, err := showToolVersion(modules.ActionContext{}); modules.KindOf(err) != modules.KindUsage {
+		t.Errorf("showToolVersion() error = %v, want usage error", err)
+	}
+}
@@ -0,0 +1,484 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"go-devtools/internal/modules"
+)
+
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+	formatTOML = "toml"
+)
+
+func normalizeFormat(name string) (string, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "json":
+		return formatJSON, nil
+	case "yaml", "yml":
+		return formatYAML, nil
+	case "toml":
+		return formatTOML, nil
+	}
+	return "", modules.UsageError("unknown format %q (use json, yaml or toml)", name)
+}
+
+// detectFormat uses --from, then the --file extension, then the content.
+// TOML is tried before YAML because most TOML documents are also valid YAML
+// strings.
+func detectFormat(ctx modules.ActionContext, data []byte) (string, error) {
+	if from := ctx.Params["from"]; from != "" {
+		return normalizeFormat(from)
+	}
+	if ext := filepath.Ext(ctx.Params["file"]); ext != "" {
+		if format, err := normalizeFormat(ext); err == nil {
+			return format, nil
+		}
+	}
+	if json.Valid(data) {
+		return formatJSON, nil
+	}
+	var probe map[string]any
+	if _, err := toml.Decode(string(data), &probe); err == nil && len(probe) > 0 {
+		return formatTOML, nil
+	}
+	return formatYAML, nil
+}
+
+// Documents are held as yaml.Node trees so that key order survives
+// conversions between JSON and YAML.
+func parseDocuments(format string, data []byte) ([]*yaml.Node, error) {
+	switch format {
+	case formatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		docs := make([]*yaml.Node, 0, 1)
+		for {
+			node, err := jsonNode(dec)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSON: %w", describeJSONError(data, err))
+			}
+			docs = append(docs, node)
+		}
+		return docs, nil
+	case formatTOML:
+		var value map[string]any
+		if _, err := toml.Decode(string(data), &value); err != nil {
+			return nil, fmt.Errorf("invalid TOML: %w", err)
+		}
+		var node yaml.Node
+		if err := node.Encode(value); err != nil {
+			return nil, err
+		}
+		return []*yaml.Node{&node}, nil
+	default:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		docs := make([]*yaml.Node, 0, 1)
+		for {
+			var doc yaml.Node
+			err := dec.Decode(&doc)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid YAML: %w", err)
+			}
+			if len(doc.Content) > 0 {
+				docs = append(docs, doc.Content[0])
+			}
+		}
+		return docs, nil
+	}
+}
+
+func jsonNode(dec *json.Decoder) (*yaml.Node, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch value := token.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if value == '{' {
+			node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		for dec.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)})
+			}
+			child, err := jsonNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(value.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(value)}, nil
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+}
+
+func describeJSONError(data []byte, err error) error {
+	var syntax *json.SyntaxError
+	if !errors.As(err, &syntax) {
+		return err
+	}
+	before := data[:min(int(syntax.Offset), len(data))]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return fmt.Errorf("line %d, column %d: %w", line, column, err)
+}
+
+func renderDocuments(format string, docs []*yaml.Node, indent int) ([]byte, error) {
+	switch format {
+	case formatJSON:
+		var compact bytes.Buffer
+		if len(docs) == 1 {
+			if err := writeJSON(&compact, docs[0]); err != nil {
+				return nil, err
+			}
+		} else {
+			seq := &yaml.Node{Kind: yaml.SequenceNode, Content: docs}
+			if err := writeJSON(&compact, seq); err != nil {
+				return nil, err
+			}
+		}
+		var out bytes.Buffer
+		if err := json.Indent(&out, compact.Bytes(), "", strings.Repeat(" ", indent)); err != nil {
+			return nil, err
+		}
+		return out.Bytes(), nil
+	case formatTOML:
+		if len(docs) != 1 || docs[0].Kind != yaml.MappingNode {
+			return nil, modules.UsageError("TOML needs a single document with a table (object) at the top level")
+		}
+		path, err := (&expansion{}).findNull(docs[0], "", false)
+		if err != nil {
+			return nil, err
+		}
+		if path != "" {
+			return nil, fmt.Errorf("TOML has no null value; remove or set %s", path)
+		}
+		var value map[string]any
+		if err := docs[0].Decode(&value); err != nil {
+			return nil, err
+		}
+		var out bytes.Buffer
+		enc := toml.NewEncoder(&out)
+		enc.Indent = ""
+		if err := enc.Encode(value); err != nil {
+			return nil, fmt.Errorf("cannot represent the document as TOML: %w", err)
+		}
+		return bytes.TrimRight(out.Bytes(), "\n"), nil
+	default:
+		var out bytes.Buffer
+		enc := yaml.NewEncoder(&out)
+		enc.SetIndent(indent)
+		for _, doc := range docs {
+			if err := enc.Encode(plainStyle(doc)); err != nil {
+				return nil, err
+			}
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return bytes.TrimRight(out.Bytes(), "\n"), nil
+	}
+}
+
+// findNull returns the dotted path of the first null value, which the TOML
+// encoder would otherwise drop silently.
+func (e *expansion) findNull(node *yaml.Node, path string, viaAlias bool) (string, error) {
+	if err := e.visit(viaAlias, 1); err != nil {
+		return "", err
+	}
+	if node.Kind == yaml.AliasNode {
+		return e.findNull(node.Alias, path, true)
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			child := strings.TrimPrefix(path+"."+node.Content[i].Value, ".")
+			found, err := e.findNull(node.Content[i+1], child, viaAlias)
+			if err != nil || found != "" {
+				return found, err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			found, err := e.findNull(item, fmt.Sprintf("%s[%d]", path, i), viaAlias)
+			if err != nil || found != "" {
+				return found, err
+			}
+		}
+	case yaml.ScalarNode:
+		if node.ShortTag() == "!!null" {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// plainStyle drops flow and quoting styles from JSON input so the YAML
+// output uses block style; the encoder still quotes strings that need it.
+func plainStyle(node *yaml.Node) *yaml.Node {
+	node.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle
+	for _, child := range node.Content {
+		plainStyle(child)
+	}
+	return node
+}
+
+// maxAliasNodes caps how many nodes may be reached through aliases and merge
+// keys. A few lines of nested anchors ("billion laughs") otherwise expand to
+// gigabytes of JSON.
+const maxAliasNodes = 100000
+
+// expansion counts the nodes a walk reaches through aliases.
+type expansion struct {
+	nodes int
+}
+
+func (e *expansion) visit(viaAlias bool, n int) error {
+	if !viaAlias {
+		return nil
+	}
+	e.nodes += n
+	if e.nodes > maxAliasNodes {
+		return fmt.Errorf("YAML aliases expand to more than %d nodes; refusing to convert", maxAliasNodes)
+	}
+	return nil
+}
+
+func writeJSON(b *bytes.Buffer, node *yaml.Node) error {
+	return (&expansion{}).writeJSON(b, node, false)
+}
+
+func (e *expansion) writeJSON(b *bytes.Buffer, node *yaml.Node, viaAlias bool) error {
+	if err := e.visit(viaAlias, 1); err != nil {
+		return err
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		return e.writeJSON(b, node.Content[0], viaAlias)
+	case yaml.AliasNode:
+		return e.writeJSON(b, node.Alias, true)
+	case yaml.SequenceNode:
+		b.WriteByte('[')
+		for i, child := range node.Content {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := e.writeJSON(b, child, viaAlias); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+		return nil
+	case yaml.MappingNode:
+		pairs, explicit, err := e.mappingPairs(node)
+		if err != nil {
+			return err
+		}
+		b.WriteByte('{')
+		for i, pair := range pairs {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeJSONString(b, pair[0].Value)
+			b.WriteByte(':')
+			if err := e.writeJSON(b, pair[1], viaAlias || i >= explicit); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+		return nil
+	default:
+		return writeJSONScalar(b, node)
+	}
+}
+
+// mappingPairs resolves YAML merge keys (<<: *base); explicit keys win over
+// merged ones. The first explicit pairs come from node itself, the rest were
+// merged in and count against the alias budget.
+func (e *expansion) mappingPairs(node *yaml.Node) ([][2]*yaml.Node, int, error) {
+	pairs := make([][2]*yaml.Node, 0, len(node.Content)/2)
+	merged := make([][2]*yaml.Node, 0)
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Kind != yaml.ScalarNode {
+			return nil, 0, fmt.Errorf("line %d: JSON only supports string keys", key.Line)
+		}
+		if key.Tag == "!!merge" || (key.Value == "<<" && key.Style == 0) {
+			sources := []*yaml.Node{value}
+			if resolved(value).Kind == yaml.SequenceNode {
+				sources = resolved(value).Content
+			}
+			for _, source := range sources {
+				inner, _, err := e.mappingPairs(resolved(source))
+				if err != nil {
+					return nil, 0, err
+				}
+				if err := e.visit(true, len(inner)); err != nil {
+					return nil, 0, err
+				}
+				merged = append(merged, inner...)
+			}
+			continue
+		}
+		seen[key.Value] = true
+		pairs = append(pairs, [2]*yaml.Node{key, value})
+	}
+	explicit := len(pairs)
+	for _, pair := range merged {
+		if !seen[pair[0].Value] {
+			seen[pair[0].Value] = true
+			pairs = append(pairs, pair)
+		}
+	}
+	return pairs, explicit, nil
+}
+
+func resolved(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.AliasNode {
+		return node.Alias
+	}
+	return node
+}
+
+func writeJSONScalar(b *bytes.Buffer, node *yaml.Node) error {
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	switch v := value.(type) {
+	case nil:
+		b.WriteString("null")
+	case string:
+		writeJSONString(b, v)
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return fmt.Errorf("line %d: %s cannot be represented in JSON", node.Line, node.Value)
+		}
+		b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	case time.Time:
+		writeJSONString(b, v.Format(time.RFC3339Nano))
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		b.Write(data)
+	}
+	return nil
+}
+
+func writeJSONString(b *bytes.Buffer, value string) {
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(value)
+	b.Truncate(b.Len() - 1)
+}
+
+func indentParam(ctx modules.ActionContext) (int, error) {
+	raw := ctx.Params["indent"]
+	if raw == "" {
+		return 2, nil
+	}
+	indent, err := strconv.Atoi(raw)
+	if err != nil || indent < 0 || indent > 8 {
+		return 0, modules.UsageError("--indent must be between 0 and 8")
+	}
+	return indent, nil
+}
+
+func convertTo(target string) func(modules.ActionContext) (string, error) {
+	return func(ctx modules.ActionContext) (string, error) {
+		indent, err := indentParam(ctx)
+		if err != nil {
+			return "", err
+		}
+		data, err := readInput(ctx)
+		if err != nil {
+			return "", err
+		}
+		format, err := detectFormat(ctx, data)
+		if err != nil {
+			return "", err
+		}
+		docs, err := parseDocuments(format, data)
+		if err != nil {
+			return "", err
+		}
+		if len(docs) == 0 {
+			return "", fmt.Errorf("input contains no %s document", format)
+		}
+		rendered, err := renderDocuments(target, docs, indent)
+		if err != nil {
+			return "", err
+		}
+		return output(ctx, rendered)
+	}
+}
+
+func jsonPretty(ctx modules.ActionContext) (string, error) {
+	indent, err := indentParam(ctx)
+	if err != nil {
+		return "", err
+	}
+	data, err := readInput(ctx)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", strings.Repeat(" ", indent)); err != nil {
+		return "", fmt.Errorf("invalid JSON: %w", describeJSONError(data, err))
+	}
+	return output(ctx, bytes.TrimRight(out.Bytes(), " \t\r\n"))
+}
+
+func jsonMinify(ctx modules.ActionContext) (string, error) {
+	data, err := readInput(ctx)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := json.Compact(&out, data); err != nil {
+		return "", fmt.Errorf("invalid JSON: %w", describeJSONError(data, err))
+	}
+	return output(ctx, out.Bytes())
+}
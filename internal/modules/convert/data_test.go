@@ -0,0 +1,83 @@
+package convert
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/modules"
+)
+
+func convertInput(target, input string) (string, error) {
+	return convertTo(target)(modules.ActionContext{Params: map[string]string{"input": input}})
+}
+
+func TestConvertKeepsKeyOrderAndResolvesMerges(t *testing.T) {
+	input := "base: &base\n  b: 1\n  a: true\nweb:\n  <<: *base\n  a: false\n  name: null\n"
+	out, err := convertInput("json", input)
+	if err != nil {
+		t.Fatalf("to-json error = %v", err)
+	}
+	want := `{
+  "base": {
+    "b": 1,
+    "a": true
+  },
+  "web": {
+    "a": false,
+    "name": null,
+    "b": 1
+  }
+}`
+	if out != want {
+		t.Errorf("to-json = %s, want %s", out, want)
+	}
+
+	out, err = convertInput("yaml", `{"z": 1, "a": [1, "two"]}`)
+	if err != nil {
+		t.Fatalf("to-yaml error = %v", err)
+	}
+	if want := "z: 1\na:\n  - 1\n  - two"; out != want {
+		t.Errorf("to-yaml = %q, want %q", out, want)
+	}
+}
+
+func TestConvertRejectsNullInTOML(t *testing.T) {
+	_, err := convertInput("toml", `{"server": {"port": null}}`)
+	if err == nil || !strings.Contains(err.Error(), "server.port") {
+		t.Errorf("to-toml error = %v, want it to name server.port", err)
+	}
+}
+
+func TestConvertCapsAliasExpansion(t *testing.T) {
+	// Nine levels of ten aliases each expand to a billion nodes.
+	var b strings.Builder
+	b.WriteString("l0: &l0 [lol]\n")
+	for i := 1; i <= 9; i++ {
+		refs := strings.TrimSuffix(strings.Repeat(fmt.Sprintf("*l%d, ", i-1), 10), ", ")
+		fmt.Fprintf(&b, "l%d: &l%d [%s]\n", i, i, refs)
+	}
+	laughs := b.String()
+
+	// Merge keys repeat the same work without growing the output.
+	b.Reset()
+	b.WriteString("m0: &m0 {a: 1, b: 2}\n")
+	for i := 1; i <= 9; i++ {
+		refs := strings.TrimSuffix(strings.Repeat(fmt.Sprintf("*m%d, ", i-1), 10), ", ")
+		fmt.Fprintf(&b, "m%d: &m%d {<<: [%s]}\n", i, i, refs)
+	}
+	merges := b.String()
+
+	for _, tc := range []struct{ name, target, input string }{
+		{"aliases to json", "json", laughs},
+		{"aliases to toml", "toml", laughs},
+		{"merge keys to json", "json", merges},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := convertInput(tc.target, tc.input)
+			if err == nil || !strings.Contains(err.Error(), "refusing to convert") {
+				t.Errorf("error = %v, want alias expansion refused", err)
+			}
+		})
+	}
+}
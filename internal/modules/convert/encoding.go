@@ -0,0 +1,122 @@
+package convert
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/modules"
+)
+
+func base64Encoding(ctx modules.ActionContext) *base64.Encoding {
+	encoding := base64.StdEncoding
+	if ctx.Params["url"] == "true" {
+		encoding = base64.URLEncoding
+	}
+	if ctx.Params["no-padding"] == "true" {
+		encoding = encoding.WithPadding(base64.NoPadding)
+	}
+	return encoding
+}
+
+func base64Encode(ctx modules.ActionContext) (string, error) {
+	data, err := readInput(ctx)
+	if err != nil {
+		return "", err
+	}
+	return output(ctx, []byte(base64Encoding(ctx).EncodeToString(data)))
+}
+
+// base64Decode accepts both alphabets, with or without padding, and ignores
+// line breaks so wrapped output from other tools decodes as is.
+func base64Decode(ctx modules.ActionContext) (string, error) {
+	data, err := readInput(ctx)
+	if err != nil {
+		return "", err
+	}
+	text := strings.Join(strings.Fields(string(data)), "")
+	encoding := base64.StdEncoding
+	if ctx.Params["url"] == "true" || strings.ContainsAny(text, "-_") {
+		encoding = base64.URLEncoding
+	}
+	decoded, err := encoding.WithPadding(base64.NoPadding).DecodeString(strings.TrimRight(text, "="))
+	if err != nil {
+		return "", fmt.Errorf("invalid base64 input: %w", err)
+	}
+	return output(ctx, decoded)
+}
+
+func hexEncode(ctx modules.ActionContext) (string, error) {
+	data, err := readInput(ctx)
+	if err != nil {
+		return "", err
+	}
+	encoded := hex.EncodeToString(data)
+	if ctx.Params["upper"] == "true" {
+		encoded = strings.ToUpper(encoded)
+	}
+	return output(ctx, []byte(encoded))
+}
+
+// hexDecode ignores a 0x prefix and the separators used by fingerprints and
+// hex dumps.
+func hexDecode(ctx modules.ActionContext) (string, error) {
+	text, err := readText(ctx)
+	if err != nil {
+		return "", err
+	}
+	text = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(text), "0x"), "0X")
+	text = strings.NewReplacer(" ", "", "\n", "", "\r", "", "\t", "", ":", "", "-", "").Replace(text)
+	decoded, err := hex.DecodeString(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid hex input: %w", err)
+	}
+	return output(ctx, decoded)
+}
+
+func urlMode(ctx modules.ActionContext) (string, error) {
+	mode := cliutil.FirstNonEmpty(ctx.Params["mode"], "query")
+	if mode != "query" && mode != "path" {
+		return "", modules.UsageError("--mode must be query or path")
+	}
+	return mode, nil
+}
+
+func urlEncode(ctx modules.ActionContext) (string, error) {
+	mode, err := urlMode(ctx)
+	if err != nil {
+		return "", err
+	}
+	text, err := readText(ctx)
+	if err != nil {
+		return "", err
+	}
+	if mode == "path" {
+		return output(ctx, []byte(url.PathEscape(text)))
+	}
+	return output(ctx, []byte(url.QueryEscape(text)))
+}
+
+func urlDecode(ctx modules.ActionContext) (string, error) {
+	mode, err := urlMode(ctx)
+	if err != nil {
+		return "", err
+	}
+	text, err := readText(ctx)
+	if err != nil {
+		return "", err
+	}
+	var decoded string
+	if mode == "path" {
+		decoded, err = url.PathUnescape(text)
+	} else {
+		decoded, err = url.QueryUnescape(text)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid URL-encoded input: %w", err)
+	}
+	return output(ctx, []byte(decoded))
+}
@@ -0,0 +1,77 @@
+package convert
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"go-devtools/internal/modules"
+)
+
+// readInput takes the value from --file (- for stdin), --input, the
+// positionals, or piped stdin, in that order.
+func readInput(ctx modules.ActionContext) ([]byte, error) {
+	if path := ctx.Params["file"]; path != "" {
+		if path == "-" {
+			return readStdin()
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, modules.NotFoundError("%s does not exist", path)
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return data, nil
+	}
+	if input, ok := ctx.Params["input"]; ok {
+		return []byte(input), nil
+	}
+	if len(ctx.Positionals) > 0 {
+		return []byte(strings.Join(ctx.Positionals, " ")), nil
+	}
+	if stdinPiped() {
+		return readStdin()
+	}
+	return nil, modules.UsageError("no input (pass a value, --file <path> or pipe data to stdin)")
+}
+
+// readText is readInput for line-oriented values, where the newline added
+// by echo or a trailing newline in a file is not part of the value.
+func readText(ctx modules.ActionContext) (string, error) {
+	data, err := readInput(ctx)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func readStdin() ([]byte, error) {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stdin: %w", err)
+	}
+	return data, nil
+}
+
+func stdinPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// output writes the result to --out when given. Binary results are only
+// printed to the terminal when they are valid UTF-8.
+func output(ctx modules.ActionContext, data []byte) (string, error) {
+	if path := ctx.Params["out"]; path != "" {
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return fmt.Sprintf("Wrote %d bytes to %s.", len(data), path), nil
+	}
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("result is binary (%d bytes); use --out <file> or hex-encode instead", len(data))
+	}
+	return string(data), nil
+}
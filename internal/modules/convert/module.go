@@ -0,0 +1,167 @@
+package convert
+
+import (
+	"fmt"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/requirements"
+)
+
+type Tool struct{}
+
+func New() modules.Tool {
+	return Tool{}
+}
+
+func (Tool) ID() string { return "convert" }
+
+func (Tool) Label() string { return "Convert" }
+
+func (Tool) Description() string { return "Encode, decode and convert data formats and timestamps" }
+
+func (Tool) Requirements() []requirements.Check { return nil }
+
+const inputUsage = "[value | --file <path|-> | stdin] [--out <file>]"
+
+func (Tool) Actions() []modules.Action {
+	return []modules.Action{
+		{
+			ID:          "base64-encode",
+			Label:       "Base64 encode",
+			Description: "Base64-encode input (standard alphabet, or URL-safe with --url true)",
+			Usage:       "devtools run convert base64-encode " + inputUsage + " [--url true] [--no-padding true]",
+			Run:         base64Encode,
+		},
+		{
+			ID:          "base64-decode",
+			Label:       "Base64 decode",
+			Description: "Decode standard or URL-safe base64, with or without padding",
+			Usage:       "devtools run convert base64-decode " + inputUsage,
+			Run:         base64Decode,
+		},
+		{
+			ID:          "hex-encode",
+			Label:       "Hex encode",
+			Description: "Hex-encode input",
+			Usage:       "devtools run convert hex-encode " + inputUsage + " [--upper true]",
+			Run:         hexEncode,
+		},
+		{
+			ID:          "hex-decode",
+			Label:       "Hex decode",
+			Description: "Decode hex; 0x prefixes, spaces and colons are ignored",
+			Usage:       "devtools run convert hex-decode " + inputUsage,
+			Run:         hexDecode,
+		},
+		{
+			ID:          "url-encode",
+			Label:       "URL encode",
+			Description: "Percent-encode a query value (spaces as +) or a path segment with --mode path",
+			Usage:       "devtools run convert url-encode " + inputUsage + " [--mode query|path]",
+			Run:         urlEncode,
+		},
+		{
+			ID:          "url-decode",
+			Label:       "URL decode",
+			Description: "Decode a percent-encoded query value or path segment",
+			Usage:       "devtools run convert url-decode " + inputUsage + " [--mode query|path]",
+			Run:         urlDecode,
+		},
+		{
+			ID:          "to-json",
+			Label:       "Convert to JSON",
+			Description: "Convert YAML or TOML to JSON; the input format is detected unless --from is set",
+			Usage:       "devtools run convert to-json " + inputUsage + " [--from yaml|toml] [--indent 2]",
+			Run:         convertTo(formatJSON),
+		},
+		{
+			ID:          "to-yaml",
+			Label:       "Convert to YAML",
+			Description: "Convert JSON or TOML to YAML",
+			Usage:       "devtools run convert to-yaml " + inputUsage + " [--from json|toml] [--indent 2]",
+			Run:         convertTo(formatYAML),
+		},
+		{
+			ID:          "to-toml",
+			Label:       "Convert to TOML",
+			Description: "Convert JSON or YAML to TOML",
+			Usage:       "devtools run convert to-toml " + inputUsage + " [--from json|yaml]",
+			Run:         convertTo(formatTOML),
+		},
+		{
+			ID:          "json-pretty",
+			Label:       "Pretty-print JSON",
+			Description: "Indent JSON, keeping key order; reports the line and column of syntax errors",
+			Usage:       "devtools run convert json-pretty " + inputUsage + " [--indent 2]",
+			Run:         jsonPretty,
+		},
+		{
+			ID:          "json-minify",
+			Label:       "Minify JSON",
+			Description: "Remove insignificant whitespace from JSON",
+			Usage:       "devtools run convert json-minify " + inputUsage,
+			Run:         jsonMinify,
+		},
+		{
+			ID:          "timestamp",
+			Label:       "Convert timestamp",
+			Description: "Convert between Unix timestamps (s/ms/us/ns) and RFC 3339 in UTC, local and other time zones",
+			Usage:       "devtools run convert timestamp [value|now] [--tz Europe/Berlin,America/New_York] [--unit s|ms|us|ns]",
+			Run:         convertTimestamp,
+		},
+	}
+}
+
+func (Tool) Menu() *menu.Menu {
+	return menu.NewBuilder("Convert").
+		Action("Timestamp", "Convert a Unix timestamp or date (empty for now)", func() (string, error) {
+			return textPrompt("Timestamp or date [now]: ", true, convertTimestamp)
+		}).
+		Action("Base64 encode", "Prompt for text", func() (string, error) {
+			return textPrompt("Text: ", false, base64Encode)
+		}).
+		Action("Base64 decode", "Prompt for base64", func() (string, error) {
+			return textPrompt("Base64: ", false, base64Decode)
+		}).
+		Action("URL encode", "Prompt for a query value", func() (string, error) {
+			return textPrompt("Text: ", false, urlEncode)
+		}).
+		Action("URL decode", "Prompt for an encoded value", func() (string, error) {
+			return textPrompt("Encoded text: ", false, urlDecode)
+		}).
+		Action("Pretty-print JSON file", "Prompt for a file", func() (string, error) {
+			return filePrompt(jsonPretty)
+		}).
+		Action("Convert file to JSON", "YAML or TOML file", func() (string, error) {
+			return filePrompt(convertTo(formatJSON))
+		}).
+		Action("Convert file to YAML", "JSON or TOML file", func() (string, error) {
+			return filePrompt(convertTo(formatYAML))
+		}).
+		WithBack().
+		Build()
+}
+
+func textPrompt(label string, allowEmpty bool, run func(modules.ActionContext) (string, error)) (string, error) {
+	text, err := cliutil.Prompt(label)
+	if err != nil {
+		return "", err
+	}
+	if text == "" && !allowEmpty {
+		return "", fmt.Errorf("input cannot be empty")
+	}
+	return run(modules.ActionContext{Params: map[string]string{"input": text}})
+}
+
+func filePrompt(run func(modules.ActionContext) (string, error)) (string, error) {
+	path, err := cliutil.Prompt("File: ")
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", fmt.Errorf("file cannot be empty")
+	}
+	return run(modules.ActionContext{Params: map[string]string{"file": path}})
+}
@@ -0,0 +1,161 @@
+package convert
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	// Embedded zone data keeps --tz working on minimal images without /usr/share/zoneinfo.
+	_ "time/tzdata"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/modules"
+)
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.UnixDate,
+	time.RubyDate,
+	time.ANSIC,
+}
+
+var unitScale = map[string]float64{"s": 1, "ms": 1e3, "us": 1e6, "ns": 1e9}
+
+func loadZones(raw string) ([]*time.Location, error) {
+	zones := make([]*time.Location, 0)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		zone, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, modules.UsageError("unknown time zone %q (use IANA names such as Europe/Berlin)", name)
+		}
+		zones = append(zones, zone)
+	}
+	return zones, nil
+}
+
+// parseTimestamp accepts Unix times in seconds, milliseconds, microseconds
+// or nanoseconds (guessed from the magnitude unless --unit is set) and the
+// common date layouts. Layouts without an offset are read in zone.
+func parseTimestamp(value, unit string, zone *time.Location) (time.Time, string, error) {
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		if unit == "" {
+			unit = guessUnit(number)
+		}
+		scale, ok := unitScale[unit]
+		if !ok {
+			return time.Time{}, "", modules.UsageError("--unit must be s, ms, us or ns")
+		}
+		// Integers are converted exactly; float64 loses precision for nanoseconds.
+		if integer, err := strconv.ParseInt(value, 10, 64); err == nil {
+			perSecond := int64(scale)
+			return time.Unix(integer/perSecond, (integer%perSecond)*(1e9/perSecond)), unit, nil
+		}
+		whole, frac := math.Modf(number / scale)
+		return time.Unix(int64(whole), int64(frac*1e9)), unit, nil
+	}
+	for _, layout := range timeLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, zone); err == nil {
+			return parsed, "", nil
+		}
+	}
+	return time.Time{}, "", modules.UsageError("cannot parse %q as a Unix timestamp or date (try RFC 3339, e.g. 2024-05-01T12:00:00Z)", value)
+}
+
+func guessUnit(number float64) string {
+	abs := math.Abs(number)
+	switch {
+	case abs < 1e11:
+		return "s"
+	case abs < 1e14:
+		return "ms"
+	case abs < 1e17:
+		return "us"
+	default:
+		return "ns"
+	}
+}
+
+func convertTimestamp(ctx modules.ActionContext) (string, error) {
+	zones, err := loadZones(ctx.Params["tz"])
+	if err != nil {
+		return "", err
+	}
+	inputZone := time.Local
+	if len(zones) > 0 {
+		inputZone = zones[0]
+	}
+
+	value := strings.TrimSpace(cliutil.FirstNonEmpty(ctx.Params["input"], strings.Join(ctx.Positionals, " ")))
+	if value == "" && (ctx.Params["file"] != "" || stdinPiped()) {
+		if value, err = readText(ctx); err != nil {
+			return "", err
+		}
+		value = strings.TrimSpace(value)
+	}
+
+	moment := time.Now()
+	unit := ""
+	if value != "" && value != "now" {
+		if moment, unit, err = parseTimestamp(value, ctx.Params["unit"], inputZone); err != nil {
+			return "", err
+		}
+	}
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	if unit != "" {
+		fmt.Fprintf(w, "Input\t%s (Unix %s)\n", value, unitName(unit))
+	}
+	fmt.Fprintf(w, "Unix seconds\t%d\n", moment.Unix())
+	fmt.Fprintf(w, "Unix millis\t%d\n", moment.UnixMilli())
+	fmt.Fprintf(w, "UTC\t%s\n", moment.UTC().Format(time.RFC3339Nano))
+	fmt.Fprintf(w, "Local (%s)\t%s\n", moment.Local().Format("MST"), moment.Local().Format(time.RFC3339Nano))
+	for _, zone := range zones {
+		fmt.Fprintf(w, "%s\t%s\n", zone, moment.In(zone).Format(time.RFC3339Nano))
+	}
+	fmt.Fprintf(w, "Relative\t%s\n", relative(time.Since(moment)))
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func unitName(unit string) string {
+	switch unit {
+	case "ms":
+		return "milliseconds"
+	case "us":
+		return "microseconds"
+	case "ns":
+		return "nanoseconds"
+	}
+	return "seconds"
+}
+
+func relative(d time.Duration) string {
+	suffix := "ago"
+	if d < 0 {
+		d, suffix = -d, "from now"
+	}
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%s %s", d.Round(time.Minute), suffix)
+	default:
+		return fmt.Sprintf("%d days %s", int(d.Hours()/24), suffix)
+	}
+}
@@ -0,0 +1,380 @@
+package dotenv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/envfile"
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/redact"
+	"go-devtools/internal/requirements"
+	"go-devtools/internal/safefile"
+)
+
+const exampleSuffix = ".example"
+
+type Tool struct{}
+
+func New() modules.Tool {
+	return Tool{}
+}
+
+func (Tool) ID() string { return "dotenv" }
+
+func (Tool) Label() string { return "Dotenv Files" }
+
+func (Tool) Description() string { return "Validate, diff, merge and run with .env files" }
+
+func (Tool) Requirements() []requirements.Check { return nil }
+
+func (Tool) Actions() []modules.Action {
+	return []modules.Action{
+		{
+			ID:          "show",
+			Label:       "Show env file",
+			Description: "Parse an env file and list its keys; secret-looking values are masked",
+			Usage:       "devtools run dotenv show [file] [--show-values true]",
+			Run:         showFile,
+		},
+		{
+			ID:          "validate",
+			Label:       "Validate against example",
+			Description: "Report missing, extra, empty and duplicate keys compared with .env.example",
+			Usage:       "devtools run dotenv validate [--file .env] [--example .env.example] [--strict true]",
+			Run:         validateFile,
+		},
+		{
+			ID:          "diff",
+			Label:       "Diff env files",
+			Description: "Show keys added, removed or changed between two env files",
+			Usage:       "devtools run dotenv diff <a> <b> [--show-values true]",
+			Run:         diffFiles,
+		},
+		{
+			ID:          "merge",
+			Label:       "Merge env files",
+			Description: "Merge env files left to right; later files win",
+			Usage:       "devtools run dotenv merge <base> <override>... [--out <file>]",
+			Run:         mergeFiles,
+		},
+		{
+			ID:          "run",
+			Label:       "Run with env file",
+			Description: "Run a command with an env file loaded; exported variables win unless --override true",
+			Usage:       "devtools run dotenv run [--file .env] [--override true] -- <command> [args...]",
+			Run:         runWithFile,
+		},
+	}
+}
+
+func (Tool) Menu() *menu.Menu {
+	return menu.NewBuilder("Dotenv Files").
+		Action("Validate .env", "Compare ./.env with ./.env.example", func() (string, error) {
+			return validateFile(modules.ActionContext{})
+		}).
+		Action("Show .env", "Keys in ./.env with secrets masked", func() (string, error) {
+			return showFile(modules.ActionContext{})
+		}).
+		Action("Diff env files", "Prompt for two files", diffPrompt).
+		Action("Run command with .env", "Prompt for a command", runPrompt).
+		WithBack().
+		Build()
+}
+
+func loadFile(path string) (*envfile.File, error) {
+	file, err := envfile.Load(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, modules.NotFoundError("env file %s does not exist", path)
+		}
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+	return file, nil
+}
+
+func maskValue(key, value string, show bool) string {
+	if show {
+		return value
+	}
+	return redact.Value(key, value)
+}
+
+func showFile(ctx modules.ActionContext) (string, error) {
+	path := cliutil.FirstNonEmpty(ctx.Params["file"], firstPositional(ctx), envfile.DefaultName)
+	file, err := loadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if len(file.Entries) == 0 {
+		return fmt.Sprintf("%s defines no variables.", path), nil
+	}
+
+	show := ctx.Params["show-values"] == "true"
+	values := file.Map()
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%d keys)\n\n", path, len(values))
+	for _, key := range file.Keys() {
+		fmt.Fprintln(&b, envfile.Format(key, maskValue(key, values[key], show)))
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func validateFile(ctx modules.ActionContext) (string, error) {
+	path := cliutil.FirstNonEmpty(ctx.Params["file"], firstPositional(ctx), envfile.DefaultName)
+	examplePath := ctx.Params["example"]
+	if examplePath == "" {
+		examplePath = filepath.Join(filepath.Dir(path), envfile.DefaultName+exampleSuffix)
+	}
+
+	file, err := loadFile(path)
+	if err != nil {
+		return "", err
+	}
+	example, err := loadFile(examplePath)
+	if err != nil {
+		return "", err
+	}
+
+	values := file.Map()
+	expected := example.Map()
+	missing := make([]string, 0)
+	for _, key := range example.Keys() {
+		if _, ok := values[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	extra := make([]string, 0)
+	empty := make([]string, 0)
+	for _, key := range file.Keys() {
+		if _, ok := expected[key]; !ok {
+			extra = append(extra, key)
+		}
+		if values[key] == "" {
+			empty = append(empty, key)
+		}
+	}
+	duplicates := file.Duplicates()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Validated %s against %s\n", path, examplePath)
+	writeList(&b, "Missing keys (in example, not set)", missing)
+	writeList(&b, "Empty values", empty)
+	writeList(&b, "Extra keys (not in example)", extra)
+	writeList(&b, "Duplicate keys (last one wins)", duplicates)
+
+	findings := len(missing) + len(empty) + len(extra) + len(duplicates)
+	if findings == 0 {
+		b.WriteString("\nAll keys match the example.")
+	}
+	out := strings.TrimRight(b.String(), "\n")
+	switch {
+	case len(missing) > 0:
+		return out, fmt.Errorf("%d required key(s) missing from %s", len(missing), path)
+	case findings > 0 && ctx.Params["strict"] == "true":
+		return out, fmt.Errorf("%d issue(s) found in %s", findings, path)
+	}
+	return out, nil
+}
+
+func writeList(b *strings.Builder, title string, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n%s:\n", title)
+	for _, key := range keys {
+		fmt.Fprintf(b, "  %s\n", key)
+	}
+}
+
+func diffFiles(ctx modules.ActionContext) (string, error) {
+	if len(ctx.Positionals) != 2 {
+		return "", modules.UsageError("diff needs exactly two files")
+	}
+	left, err := loadFile(ctx.Positionals[0])
+	if err != nil {
+		return "", err
+	}
+	right, err := loadFile(ctx.Positionals[1])
+	if err != nil {
+		return "", err
+	}
+	return renderDiff(left, right, ctx.Params["show-values"] == "true"), nil
+}
+
+func renderDiff(left, right *envfile.File, show bool) string {
+	a := left.Map()
+	b := right.Map()
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", left.Path, right.Path)
+	changes := 0
+	for _, key := range keys {
+		before, inA := a[key]
+		after, inB := b[key]
+		switch {
+		case !inB:
+			fmt.Fprintf(&out, "- %s\n", envfile.Format(key, maskValue(key, before, show)))
+		case !inA:
+			fmt.Fprintf(&out, "+ %s\n", envfile.Format(key, maskValue(key, after, show)))
+		case before != after:
+			if !show && redact.IsSecretKey(key) {
+				fmt.Fprintf(&out, "~ %s (value changed)\n", key)
+			} else {
+				fmt.Fprintf(&out, "~ %s: %q -> %q\n", key, maskValue(key, before, show), maskValue(key, after, show))
+			}
+		default:
+			continue
+		}
+		changes++
+	}
+	if changes == 0 {
+		out.WriteString("No differences.")
+	}
+	return strings.TrimRight(out.String(), "\n")
+}
+
+func mergeFiles(ctx modules.ActionContext) (string, error) {
+	if len(ctx.Positionals) < 2 {
+		return "", modules.UsageError("merge needs at least two files")
+	}
+
+	keys := make([]string, 0)
+	values := map[string]string{}
+	for _, path := range ctx.Positionals {
+		file, err := loadFile(path)
+		if err != nil {
+			return "", err
+		}
+		for _, entry := range file.Entries {
+			if _, ok := values[entry.Key]; !ok {
+				keys = append(keys, entry.Key)
+			}
+			values[entry.Key] = entry.Value
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# merged by devtools from %s\n", strings.Join(ctx.Positionals, ", "))
+	for _, key := range keys {
+		fmt.Fprintln(&b, envfile.Format(key, values[key]))
+	}
+
+	out := ctx.Params["out"]
+	if out == "" {
+		return strings.TrimRight(b.String(), "\n"), nil
+	}
+	backup := ""
+	if _, err := os.Stat(out); err == nil {
+		if backup, err = safefile.Backup(out); err != nil {
+			return "", err
+		}
+	}
+	if err := safefile.Write(out, []byte(b.String())); err != nil {
+		return "", err
+	}
+	msg := fmt.Sprintf("Merged %d keys from %d files into %s.", len(keys), len(ctx.Positionals), out)
+	if backup != "" {
+		msg += fmt.Sprintf(" Previous version saved as %s.", backup)
+	}
+	return msg, nil
+}
+
+func runWithFile(ctx modules.ActionContext) (string, error) {
+	return runCommand(ctx, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// runCommand runs the command with the file's variables added to the
+// environment. A non-zero exit status is passed on as devtools' own.
+func runCommand(ctx modules.ActionContext, stdin io.Reader, stdout, stderr io.Writer) (string, error) {
+	if len(ctx.Positionals) == 0 {
+		return "", modules.UsageError("missing command (put it after --)")
+	}
+	path := cliutil.FirstNonEmpty(ctx.Params["file"], envfile.DefaultName)
+	file, err := loadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	override := ctx.Params["override"] == "true"
+	env := os.Environ()
+	for _, entry := range file.Entries {
+		if _, exported := os.LookupEnv(entry.Key); exported && !override {
+			continue
+		}
+		env = append(env, entry.Key+"="+entry.Value)
+	}
+
+	cmd := exec.Command(ctx.Positionals[0], ctx.Positionals[1:]...)
+	cmd.Env = env
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			return "", modules.ExitStatusError(exitErr.ExitCode(), "%s exited with status %d", ctx.Positionals[0], exitErr.ExitCode())
+		}
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", modules.NotFoundError("command %q not found", ctx.Positionals[0])
+		}
+		return "", fmt.Errorf("failed to run %s: %w", ctx.Positionals[0], err)
+	}
+	return "", nil
+}
+
+func diffPrompt() (string, error) {
+	left, err := cliutil.Prompt("First file [.env]: ")
+	if err != nil {
+		return "", err
+	}
+	right, err := cliutil.Prompt("Second file [.env.example]: ")
+	if err != nil {
+		return "", err
+	}
+	return diffFiles(modules.ActionContext{Positionals: []string{
+		cliutil.FirstNonEmpty(left, envfile.DefaultName),
+		cliutil.FirstNonEmpty(right, envfile.DefaultName+exampleSuffix),
+	}})
+}
+
+func runPrompt() (string, error) {
+	line, err := cliutil.Prompt("Command: ")
+	if err != nil {
+		return "", err
+	}
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return "", fmt.Errorf("command cannot be empty")
+	}
+	// The TUI owns the terminal, so the command's output is captured and
+	// shown as the action result instead.
+	var out bytes.Buffer
+	_, err = runCommand(modules.ActionContext{Positionals: args}, nil, &out, &out)
+	return strings.TrimRight(out.String(), "\n"), err
+}
+
+func firstPositional(ctx modules.ActionContext) string {
+	if len(ctx.Positionals) > 0 {
+		return ctx.Positionals[0]
+	}
+	return ""
+}
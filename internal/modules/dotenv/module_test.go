@@ -0,0 +1,124 @@
+package dotenv
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/modules"
+)
+
+func TestValidateFile(t *testing.T) {
+	out, err := validateFile(modules.ActionContext{Params: map[string]string{"file": filepath.Join("testdata", ".env")}})
+	if err == nil || !strings.Contains(err.Error(), "1 required key(s) missing") {
+		t.Fatalf("validateFile() error = %v, want a missing-key error", err)
+	}
+	for _, want := range []string{
+		"Missing keys (in example, not set):\n  SENTRY_DSN",
+		"Extra keys (not in example):\n  LEGACY",
+		"Duplicate keys (last one wins):\n  DEBUG",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("validateFile() output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestShowAndDiffMaskSecrets(t *testing.T) {
+	out, err := showFile(modules.ActionContext{Positionals: []string{filepath.Join("testdata", ".env")}})
+	if err != nil {
+		t.Fatalf("showFile() error = %v", err)
+	}
+	if strings.Contains(out, "secret-token") || !strings.Contains(out, "API_TOKEN=[redacted]") {
+		t.Errorf("showFile() did not mask API_TOKEN:\n%s", out)
+	}
+
+	out, err = diffFiles(modules.ActionContext{Positionals: []string{filepath.Join("testdata", ".env.example"), filepath.Join("testdata", ".env")}})
+	if err != nil {
+		t.Fatalf("diffFiles() error = %v", err)
+	}
+	for _, want := range []string{"~ API_TOKEN (value changed)", "+ LEGACY=1", "- SENTRY_DSN=", `~ DEBUG: "false" -> "true"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("diffFiles() missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestMergeFilesBacksUpExistingOutput(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.env")
+	local := filepath.Join(dir, "local.env")
+	out := filepath.Join(dir, ".env")
+	for path, data := range map[string]string{base: "A=1\nB=2\n", local: "B=3\nC=with space\n", out: "OLD=1\n"} {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	msg, err := mergeFiles(modules.ActionContext{Positionals: []string{base, local}, Params: map[string]string{"out": out}})
+	if err != nil {
+		t.Fatalf("mergeFiles() error = %v", err)
+	}
+	if !strings.Contains(msg, "Previous version saved as") {
+		t.Errorf("mergeFiles() = %q, want a backup note", msg)
+	}
+	merged, _ := os.ReadFile(out)
+	if !strings.HasSuffix(string(merged), "A=1\nB=3\nC=\"with space\"\n") {
+		t.Errorf("merged file =\n%s", merged)
+	}
+	if backup, _ := os.ReadFile(out + ".bak"); string(backup) != "OLD=1\n" {
+		t.Errorf("backup = %q", backup)
+	}
+
+	if _, err := mergeFiles(modules.ActionContext{Positionals: []string{base}}); modules.KindOf(err) != modules.KindUsage {
+		t.Errorf("mergeFiles(one file) error = %v, want usage error", err)
+	}
+}
+
+func TestRunCommandPassesExitStatusAndCapturesOutput(t *testing.T) {
+	envPath := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(envPath, []byte("DOTENV_GREETING=hi\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		mode string
+		want string
+		code int
+	}{
+		{mode: "print", want: "greeting=hi\n", code: modules.ExitOK},
+		{mode: "fail", want: "failing\n", code: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			t.Setenv("DOTENV_HELPER", tt.mode)
+			var out bytes.Buffer
+			_, err := runCommand(modules.ActionContext{
+				Params:      map[string]string{"file": envPath},
+				Positionals: []string{os.Args[0], "-test.run=TestHelperProcess"},
+			}, nil, &out, &out)
+			if got := modules.ExitCode(err); got != tt.code {
+				t.Errorf("exit code = %d (%v), want %d", got, err, tt.code)
+			}
+			if out.String() != tt.want {
+				t.Errorf("captured output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestHelperProcess is the command started by TestRunCommandPassesExitStatusAndCapturesOutput.
+func TestHelperProcess(t *testing.T) {
+	switch os.Getenv("DOTENV_HELPER") {
+	case "print":
+		fmt.Printf("greeting=%s\n", os.Getenv("DOTENV_GREETING"))
+	case "fail":
+		fmt.Fprintln(os.Stderr, "failing")
+		os.Exit(3)
+	default:
+		return
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,7 @@
+//go:build !linux && !darwin && !freebsd
+
+package envinfo
+
+func diskUsage(string) (diskInfo, bool) {
+	return diskInfo{}, false
+}
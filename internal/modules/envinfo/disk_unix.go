@@ -0,0 +1,27 @@
+//go:build linux || darwin || freebsd
+
+package envinfo
+
+import (
+	"fmt"
+	"syscall"
+)
+
+func diskUsage(path string) (diskInfo, bool) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return diskInfo{}, false
+	}
+	// Field types differ between platforms (Bavail is signed on FreeBSD).
+	total := uint64(stat.Blocks) * uint64(stat.Bsize)
+	free := uint64(stat.Bavail) * uint64(stat.Bsize)
+	if total == 0 {
+		return diskInfo{}, false
+	}
+	return diskInfo{
+		Path:  path,
+		Total: humanBytes(total),
+		Free:  humanBytes(free),
+		Used:  fmt.Sprintf("%.0f%%", float64(total-free)/float64(total)*100),
+	}, true
+}
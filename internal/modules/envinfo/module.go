@@ -2,10 +2,10 @@ package envinfo
 
 import (
 	"fmt"
-	"os"
 	"runtime"
 	"strings"
 
+	"go-devtools/internal/cliutil"
 	"go-devtools/internal/menu"
 	"go-devtools/internal/modules"
 	"go-devtools/internal/requirements"
@@ -23,11 +23,11 @@ func (Tool) Label() string { return "Environment Info" }
 
 func (Tool) Description() string { return "Diagnostics module with requirement checks" }
 
-func (Tool) Requirements() []requirements.Check {
-	return []requirements.Check{
-		requirements.CommandExistsWithBrew("go", "go"),
-	}
-}
+// goCommand is checked by the Go runtime action only, so the reports and
+// PATH tools keep working on machines without a Go toolchain.
+var goCommand = requirements.CommandExistsWithBrew("go", "go")
+
+func (Tool) Requirements() []requirements.Check { return nil }
 
 func (Tool) Actions() []modules.Action {
 	return []modules.Action{
@@ -45,6 +45,48 @@ func (Tool) Actions() []modules.Action {
 			Usage:       "devtools run env-info path-entries",
 			Run:         showPathEntries,
 		},
+		{
+			ID:          "path-analyze",
+			Label:       "Analyze PATH",
+			Description: "Flag missing, duplicate, non-directory and world-writable entries and shadowed commands",
+			Usage:       "devtools run env-info path-analyze",
+			Run:         showPathAnalysis,
+		},
+		{
+			ID:          "which-all",
+			Label:       "Find every match for a command",
+			Description: "List every PATH match for a command in lookup order",
+			Usage:       "devtools run env-info which-all <command>",
+			Run:         showWhichAll,
+		},
+		{
+			ID:          "env-snapshot",
+			Label:       "Snapshot environment",
+			Description: "Save the current environment; secret-looking values are hashed",
+			Usage:       "devtools run env-info env-snapshot [name] [--out <file>]",
+			Run:         saveSnapshot,
+		},
+		{
+			ID:          "env-snapshots",
+			Label:       "List environment snapshots",
+			Description: "List saved environment snapshots",
+			Usage:       "devtools run env-info env-snapshots",
+			Run:         listSnapshots,
+		},
+		{
+			ID:          "env-diff",
+			Label:       "Diff environment",
+			Description: "Compare the current environment with a snapshot, or two snapshots",
+			Usage:       "devtools run env-info env-diff <snapshot|file> [other-snapshot|file]",
+			Run:         diffSnapshots,
+		},
+		{
+			ID:          "report",
+			Label:       "Diagnostic report",
+			Description: "Collect OS, hardware, shell, Go, git, proxy, tool and disk details with secrets redacted",
+			Usage:       "devtools run env-info report [--format markdown|json] [--out <file>]",
+			Run:         showReport,
+		},
 	}
 }
 
@@ -53,24 +95,74 @@ func (Tool) Menu() *menu.Menu {
 		Action("Show PATH entries", "Displays PATH split into lines", func() (string, error) {
 			return showPathEntries(modules.ActionContext{})
 		}).
+		Action("Analyze PATH", "Missing, duplicate and shadowing problems", func() (string, error) {
+			return showPathAnalysis(modules.ActionContext{})
+		}).
+		Action("Find every match for a command", "Prompt for a command name", whichAllPrompt).
+		WithBack().
+		Build()
+
+	snapshots := menu.NewBuilder("Environment Info / Snapshots").
+		Action("Snapshot environment", "Saves a timestamped snapshot", func() (string, error) {
+			return saveSnapshot(modules.ActionContext{})
+		}).
+		Action("List snapshots", "Saved snapshots, newest first", func() (string, error) {
+			return listSnapshots(modules.ActionContext{})
+		}).
+		Action("Diff with snapshot", "Prompt for a snapshot name", diffSnapshotPrompt).
 		WithBack().
 		Build()
 
 	return menu.NewBuilder("Environment Info Tool").
-		Action("Show Go runtime info", "Prints local runtime metadata", func() (string, error) {
-			return showGoRuntime(modules.ActionContext{})
+		Custom(menu.Item{
+			Label:       "Show Go runtime info",
+			Description: "Prints local runtime metadata",
+			Run: func() (string, error) {
+				return showGoRuntime(modules.ActionContext{})
+			},
+			Requirements: []requirements.Check{goCommand},
 		}).
 		SubMenu("PATH details", "Nested submenu example", paths).
+		SubMenu("Environment snapshots", "Save and diff environment variables", snapshots).
+		Action("Diagnostic report", "Markdown bundle for bug reports", func() (string, error) {
+			return showReport(modules.ActionContext{})
+		}).
+		Action("Save diagnostic report", "Writes "+defaultReportFile+" to the current directory", func() (string, error) {
+			return showReport(modules.ActionContext{Params: map[string]string{"out": defaultReportFile}})
+		}).
 		WithBack().
 		Build()
 }
 
 func showGoRuntime(_ modules.ActionContext) (string, error) {
+	if err := goCommand.Run(); err != nil {
+		return "", modules.RequirementError("%w", err)
+	}
 	return fmt.Sprintf("GOOS=%s GOARCH=%s GOVERSION=%s", runtime.GOOS, runtime.GOARCH, runtime.Version()), nil
 }
 
 func showPathEntries(_ modules.ActionContext) (string, error) {
-	path := os.Getenv("PATH")
-	entries := strings.Split(path, ":")
-	return strings.Join(entries, "\n"), nil
+	return strings.Join(pathList(), "\n"), nil
+}
+
+func whichAllPrompt() (string, error) {
+	name, err := cliutil.Prompt("Command: ")
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return "", fmt.Errorf("command cannot be empty")
+	}
+	return showWhichAll(modules.ActionContext{Positionals: []string{name}})
+}
+
+func diffSnapshotPrompt() (string, error) {
+	ref, err := cliutil.Prompt("Snapshot name or file: ")
+	if err != nil {
+		return "", err
+	}
+	if ref == "" {
+		return "", fmt.Errorf("snapshot cannot be empty")
+	}
+	return diffSnapshots(modules.ActionContext{Positionals: []string{ref}})
 }
@@ -0,0 +1,229 @@
+package envinfo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/modules"
+)
+
+type pathEntry struct {
+	Index    int
+	Raw      string
+	Resolved string
+	Problems []string
+	usable   bool
+}
+
+type shadowedCommand struct {
+	Name    string
+	Winner  string
+	Shadows []string
+}
+
+func pathList() []string {
+	return filepath.SplitList(os.Getenv("PATH"))
+}
+
+func analyzePath(raw []string) []pathEntry {
+	entries := make([]pathEntry, 0, len(raw))
+	seen := map[string]int{}
+	for i, dir := range raw {
+		entry := pathEntry{Index: i + 1, Raw: dir}
+		switch {
+		case dir == "":
+			entry.Problems = append(entry.Problems, "empty entry (means the current directory)")
+		case !filepath.IsAbs(dir):
+			entry.Problems = append(entry.Problems, "relative path (depends on the current directory)")
+		}
+
+		info, err := os.Stat(dir)
+		switch {
+		case dir == "":
+		case os.IsNotExist(err):
+			entry.Problems = append(entry.Problems, "does not exist")
+		case err != nil:
+			entry.Problems = append(entry.Problems, fmt.Sprintf("cannot be read: %v", err))
+		case !info.IsDir():
+			entry.Problems = append(entry.Problems, "not a directory")
+		default:
+			entry.usable = true
+			if runtime.GOOS != "windows" && info.Mode().Perm()&0o002 != 0 {
+				entry.Problems = append(entry.Problems, "world-writable (anyone can plant commands here)")
+			}
+		}
+
+		entry.Resolved = filepath.Clean(dir)
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			entry.Resolved = resolved
+		}
+		if first, ok := seen[entry.Resolved]; ok && dir != "" {
+			if filepath.Clean(raw[first-1]) == filepath.Clean(dir) {
+				entry.Problems = append(entry.Problems, fmt.Sprintf("duplicate of #%d", first))
+			} else {
+				entry.Problems = append(entry.Problems, fmt.Sprintf("same directory as #%d (%s) via symlink", first, raw[first-1]))
+			}
+			entry.usable = false
+		} else if dir != "" {
+			seen[entry.Resolved] = entry.Index
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// findShadowed lists commands available in more than one PATH directory.
+// Copies that resolve to the same file, such as /bin and /usr/bin on
+// merged-/usr systems, are not reported.
+func findShadowed(entries []pathEntry) []shadowedCommand {
+	type found struct {
+		path     string
+		resolved string
+	}
+	matches := map[string][]found{}
+	for _, entry := range entries {
+		if !entry.usable {
+			continue
+		}
+		files, err := os.ReadDir(entry.Raw)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			path := filepath.Join(entry.Raw, file.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			resolved, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				resolved = path
+			}
+			name := commandName(file.Name())
+			matches[name] = append(matches[name], found{path: path, resolved: resolved})
+		}
+	}
+
+	shadowed := make([]shadowedCommand, 0)
+	for name, list := range matches {
+		winner := list[0]
+		command := shadowedCommand{Name: name, Winner: winner.path}
+		for _, other := range list[1:] {
+			if other.resolved != winner.resolved {
+				command.Shadows = append(command.Shadows, other.path)
+			}
+		}
+		if len(command.Shadows) > 0 {
+			shadowed = append(shadowed, command)
+		}
+	}
+	sort.Slice(shadowed, func(i, j int) bool { return shadowed[i].Name < shadowed[j].Name })
+	return shadowed
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(path))
+		return ext == ".exe" || ext == ".bat" || ext == ".cmd" || ext == ".com"
+	}
+	return info.Mode().Perm()&0o111 != 0
+}
+
+func commandName(file string) string {
+	if runtime.GOOS == "windows" {
+		return strings.ToLower(strings.TrimSuffix(file, filepath.Ext(file)))
+	}
+	return file
+}
+
+func showPathAnalysis(_ modules.ActionContext) (string, error) {
+	entries := analyzePath(pathList())
+	if len(entries) == 0 {
+		return "PATH is empty.", nil
+	}
+
+	var b strings.Builder
+	problems := 0
+	for _, entry := range entries {
+		status := "ok"
+		if len(entry.Problems) > 0 {
+			status = strings.Join(entry.Problems, "; ")
+			problems++
+		}
+		fmt.Fprintf(&b, "%3d. %s  [%s]\n", entry.Index, cliutil.Dash(entry.Raw), status)
+	}
+
+	shadowed := findShadowed(entries)
+	if len(shadowed) > 0 {
+		b.WriteString("\nShadowed commands (the first one wins):\n")
+		for _, command := range shadowed {
+			fmt.Fprintf(&b, "  %s: %s\n", command.Name, command.Winner)
+			for _, path := range command.Shadows {
+				fmt.Fprintf(&b, "  %s  hides %s\n", strings.Repeat(" ", len(command.Name)), path)
+			}
+		}
+	}
+
+	fmt.Fprintf(&b, "\n%d entries, %d with problems, %d shadowed commands.", len(entries), problems, len(shadowed))
+	return b.String(), nil
+}
+
+func showWhichAll(ctx modules.ActionContext) (string, error) {
+	name := ctx.Params["command"]
+	if name == "" && len(ctx.Positionals) > 0 {
+		name = ctx.Positionals[0]
+	}
+	if name == "" {
+		return "", modules.UsageError("missing command name")
+	}
+	if strings.ContainsRune(name, os.PathSeparator) {
+		return "", modules.UsageError("%q is a path, not a command name", name)
+	}
+
+	var b strings.Builder
+	count := 0
+	for _, entry := range analyzePath(pathList()) {
+		if !entry.usable {
+			continue
+		}
+		for _, candidate := range commandCandidates(entry.Raw, name) {
+			if !isExecutable(candidate) {
+				continue
+			}
+			count++
+			marker := "   "
+			if count == 1 {
+				marker = " * "
+			}
+			line := fmt.Sprintf("%s%s", marker, candidate)
+			if resolved, err := filepath.EvalSymlinks(candidate); err == nil && resolved != candidate {
+				line += " -> " + resolved
+			}
+			fmt.Fprintf(&b, "%s  (PATH #%d)\n", line, entry.Index)
+		}
+	}
+	if count == 0 {
+		return "", modules.NotFoundError("%s not found in PATH", name)
+	}
+	b.WriteString("\n* = runs when you type " + name)
+	return b.String(), nil
+}
+
+func commandCandidates(dir, name string) []string {
+	if runtime.GOOS != "windows" || filepath.Ext(name) != "" {
+		return []string{filepath.Join(dir, name)}
+	}
+	candidates := make([]string, 0, 4)
+	for _, ext := range []string{".exe", ".cmd", ".bat", ".com"} {
+		candidates = append(candidates, filepath.Join(dir, name+ext))
+	}
+	return candidates
+}
@@ -0,0 +1,117 @@
+package envinfo
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/modules"
+)
+
+func writeExecutable(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAnalyzePath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits differ on windows")
+	}
+	root := t.TempDir()
+	first := filepath.Join(root, "first")
+	second := filepath.Join(root, "second")
+	open := filepath.Join(root, "open")
+	for _, dir := range []string{first, second, open} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chmod(open, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(root, "link")
+	if err := os.Symlink(first, link); err != nil {
+		t.Fatal(err)
+	}
+	file := writeExecutable(t, root, "file")
+
+	entries := analyzePath([]string{first, "", "relative", filepath.Join(root, "missing"), file, first, link, open, second})
+	want := []string{
+		"",
+		"empty entry",
+		"relative path",
+		"does not exist",
+		"not a directory",
+		"duplicate of #1",
+		"same directory as #1",
+		"world-writable",
+		"",
+	}
+	for i, entry := range entries {
+		got := strings.Join(entry.Problems, "; ")
+		if want[i] == "" && got != "" || !strings.Contains(got, want[i]) {
+			t.Errorf("entry #%d (%s) problems = %q, want %q", entry.Index, entry.Raw, got, want[i])
+		}
+	}
+}
+
+func TestFindShadowedAndWhichAll(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executable lookup differs on windows")
+	}
+	root := t.TempDir()
+	first := filepath.Join(root, "first")
+	second := filepath.Join(root, "second")
+	for _, dir := range []string{first, second} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	winner := writeExecutable(t, first, "tool")
+	hidden := writeExecutable(t, second, "tool")
+	if err := os.Symlink(winner, filepath.Join(second, "same")); err != nil {
+		t.Fatal(err)
+	}
+	writeExecutable(t, first, "only")
+	if err := os.Symlink(winner, filepath.Join(first, "alias")); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", first+string(os.PathListSeparator)+second)
+
+	shadowed := findShadowed(analyzePath(pathList()))
+	if len(shadowed) != 1 || shadowed[0].Name != "tool" || shadowed[0].Winner != winner || shadowed[0].Shadows[0] != hidden {
+		t.Errorf("findShadowed() = %+v, want tool in %s hiding %s", shadowed, first, hidden)
+	}
+
+	out, err := showWhichAll(modules.ActionContext{Positionals: []string{"tool"}})
+	if err != nil {
+		t.Fatalf("showWhichAll() error = %v", err)
+	}
+	if !strings.Contains(out, " * "+winner+"  (PATH #1)") || !strings.Contains(out, "   "+hidden+"  (PATH #2)") {
+		t.Errorf("showWhichAll() = %q", out)
+	}
+
+	tests := []struct {
+		name string
+		want modules.ErrorKind
+	}{
+		{name: "", want: modules.KindUsage},
+		{name: "bin/tool", want: modules.KindUsage},
+		{name: "absent", want: modules.KindNotFound},
+	}
+	for _, tt := range tests {
+		ctx := modules.ActionContext{}
+		if tt.name != "" {
+			ctx.Positionals = []string{tt.name}
+		}
+		if _, err := showWhichAll(ctx); modules.KindOf(err) != tt.want {
+			t.Errorf("showWhichAll(%q) error = %v, want kind %v", tt.name, err, tt.want)
+		}
+	}
+}
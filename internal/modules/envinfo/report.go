@@ -0,0 +1,381 @@
+package envinfo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-devtools/internal/modules"
+	"go-devtools/internal/redact"
+	"go-devtools/internal/toolversions"
+)
+
+const (
+	commandTimeout    = 5 * time.Second
+	defaultReportFile = "devtools-report.md"
+)
+
+var (
+	localeVars = []string{"LANG", "LANGUAGE", "LC_ALL", "LC_CTYPE", "TZ"}
+	proxyVars  = []string{"HTTP_PROXY", "HTTPS_PROXY", "ALL_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "all_proxy", "no_proxy"}
+	goEnvKeys  = []string{"GOVERSION", "GOOS", "GOARCH", "GOROOT", "GOPATH", "GOMODCACHE", "GOCACHE", "GOPROXY", "GOPRIVATE", "GONOSUMDB", "GOFLAGS", "GOAUTH", "CGO_ENABLED", "GOTOOLCHAIN"}
+	gitKeys    = []string{"user.name", "user.email", "init.defaultBranch", "core.editor", "credential.helper"}
+)
+
+type report struct {
+	GeneratedAt time.Time         `json:"generatedAt"`
+	System      systemInfo        `json:"system"`
+	Shell       map[string]string `json:"shell"`
+	Locale      map[string]string `json:"locale"`
+	Go          map[string]string `json:"go"`
+	Git         map[string]string `json:"git"`
+	Proxy       map[string]string `json:"proxy"`
+	Tools       []toolInfo        `json:"tools"`
+	Disks       []diskInfo        `json:"disks"`
+}
+
+type systemInfo struct {
+	OS              string `json:"os"`
+	Arch            string `json:"arch"`
+	Distribution    string `json:"distribution,omitempty"`
+	Kernel          string `json:"kernel,omitempty"`
+	Hostname        string `json:"hostname,omitempty"`
+	CPUs            int    `json:"cpus"`
+	CPUModel        string `json:"cpuModel,omitempty"`
+	MemoryTotal     string `json:"memoryTotal,omitempty"`
+	MemoryAvailable string `json:"memoryAvailable,omitempty"`
+}
+
+type toolInfo struct {
+	Name      string `json:"name"`
+	Installed bool   `json:"installed"`
+	Version   string `json:"version,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type diskInfo struct {
+	Path  string `json:"path"`
+	Total string `json:"total"`
+	Free  string `json:"free"`
+	Used  string `json:"used"`
+}
+
+func collectReport() report {
+	r := report{
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+		System:      collectSystem(),
+		Shell:       collectShell(),
+		Locale:      collectEnv(localeVars),
+		Go:          collectGoEnv(),
+		Git:         collectGit(),
+		Proxy:       collectEnv(proxyVars),
+	}
+	for _, result := range toolversions.Check(toolversions.Registry) {
+		info := toolInfo{Name: result.Spec.Name, Installed: result.Installed, Version: result.Version}
+		if result.Err != nil {
+			info.Error = result.Err.Error()
+		}
+		r.Tools = append(r.Tools, info)
+	}
+	for _, path := range diskPaths() {
+		if disk, ok := diskUsage(path); ok {
+			r.Disks = append(r.Disks, disk)
+		}
+	}
+	return r
+}
+
+func collectSystem() systemInfo {
+	info := systemInfo{
+		OS:   runtime.GOOS,
+		Arch: runtime.GOARCH,
+		CPUs: runtime.NumCPU(),
+	}
+	info.Hostname, _ = os.Hostname()
+
+	switch runtime.GOOS {
+	case "linux":
+		info.Distribution = osReleaseName()
+		info.Kernel = readTrimmed("/proc/sys/kernel/osrelease")
+		info.CPUModel = procField("/proc/cpuinfo", "model name")
+		info.MemoryTotal = meminfoBytes("MemTotal")
+		info.MemoryAvailable = meminfoBytes("MemAvailable")
+	case "darwin":
+		if version := commandOutput("sw_vers", "-productVersion"); version != "" {
+			info.Distribution = "macOS " + version
+		}
+		info.Kernel = commandOutput("uname", "-r")
+		info.CPUModel = commandOutput("sysctl", "-n", "machdep.cpu.brand_string")
+		if raw, err := strconv.ParseUint(commandOutput("sysctl", "-n", "hw.memsize"), 10, 64); err == nil {
+			info.MemoryTotal = humanBytes(raw)
+		}
+	default:
+		info.Kernel = commandOutput("uname", "-r")
+	}
+	return info
+}
+
+func collectShell() map[string]string {
+	values := collectEnv([]string{"SHELL", "TERM", "TERM_PROGRAM", "COLORTERM"})
+	if shell := os.Getenv("SHELL"); shell != "" {
+		if version := firstLine(commandOutput(shell, "--version")); version != "" {
+			values["version"] = version
+		}
+	}
+	return values
+}
+
+func collectEnv(names []string) map[string]string {
+	values := map[string]string{}
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			values[name] = redact.Value(name, value)
+		}
+	}
+	return values
+}
+
+func collectGoEnv() map[string]string {
+	values := map[string]string{}
+	raw := commandOutput("go", "env", "-json")
+	if raw == "" {
+		return values
+	}
+	var env map[string]string
+	if err := json.Unmarshal([]byte(raw), &env); err != nil {
+		return values
+	}
+	for _, key := range goEnvKeys {
+		if value := env[key]; value != "" {
+			values[key] = redact.Value(key, value)
+		}
+	}
+	return values
+}
+
+func collectGit() map[string]string {
+	values := map[string]string{}
+	for _, key := range gitKeys {
+		if value := commandOutput("git", "config", "--get", key); value != "" {
+			values[key] = redact.Value(key, value)
+		}
+	}
+	return values
+}
+
+func diskPaths() []string {
+	paths := make([]string, 0, 3)
+	seen := map[string]bool{}
+	add := func(path string) {
+		if path != "" && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		add(cwd)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		add(home)
+	}
+	add(os.TempDir())
+	return paths
+}
+
+func commandOutput(name string, args ...string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(stdout.String())
+}
+
+func readTrimmed(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func osReleaseName() string {
+	value := procField("/etc/os-release", "PRETTY_NAME")
+	return strings.Trim(value, `"`)
+}
+
+// procField returns the value of the first "key: value" or "key=value" line
+// in a /proc or /etc style file.
+func procField(path, key string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || strings.Contains(name, "=") {
+			name, value, ok = strings.Cut(line, "=")
+		}
+		if ok && strings.TrimSpace(name) == key {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+func meminfoBytes(key string) string {
+	fields := strings.Fields(procField("/proc/meminfo", key))
+	if len(fields) == 0 {
+		return ""
+	}
+	kb, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return ""
+	}
+	return humanBytes(kb * 1024)
+}
+
+func humanBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for value := n / unit; value >= unit; value /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func firstLine(value string) string {
+	line, _, _ := strings.Cut(value, "\n")
+	return strings.TrimSpace(line)
+}
+
+func (r report) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Environment report\n\nGenerated %s\n", r.GeneratedAt.Format(time.RFC3339))
+
+	s := r.System
+	writeTable(&b, "System", [][2]string{
+		{"OS", s.OS + "/" + s.Arch},
+		{"Distribution", s.Distribution},
+		{"Kernel", s.Kernel},
+		{"Hostname", s.Hostname},
+		{"CPUs", strconv.Itoa(s.CPUs)},
+		{"CPU model", s.CPUModel},
+		{"Memory total", s.MemoryTotal},
+		{"Memory available", s.MemoryAvailable},
+	})
+	writeTable(&b, "Shell", mapRows(r.Shell))
+	writeTable(&b, "Locale", mapRows(r.Locale))
+	writeTable(&b, "Go env", mapRows(r.Go))
+	writeTable(&b, "Git config", mapRows(r.Git))
+	writeTable(&b, "Proxy", mapRows(r.Proxy))
+
+	b.WriteString("\n## Tools\n\n| Tool | Version |\n| --- | --- |\n")
+	for _, tool := range r.Tools {
+		version := tool.Version
+		switch {
+		case !tool.Installed:
+			version = "not installed"
+		case tool.Error != "":
+			version = "error: " + tool.Error
+		}
+		fmt.Fprintf(&b, "| %s | %s |\n", tool.Name, escapeCell(version))
+	}
+
+	if len(r.Disks) > 0 {
+		b.WriteString("\n## Disk space\n\n| Path | Total | Free | Used |\n| --- | --- | --- | --- |\n")
+		for _, disk := range r.Disks {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", escapeCell(disk.Path), disk.Total, disk.Free, disk.Used)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func writeTable(b *strings.Builder, title string, rows [][2]string) {
+	fmt.Fprintf(b, "\n## %s\n\n", title)
+	written := 0
+	for _, row := range rows {
+		if row[1] == "" {
+			continue
+		}
+		if written == 0 {
+			b.WriteString("| Key | Value |\n| --- | --- |\n")
+		}
+		fmt.Fprintf(b, "| %s | %s |\n", row[0], escapeCell(row[1]))
+		written++
+	}
+	if written == 0 {
+		b.WriteString("_Nothing detected._\n")
+	}
+}
+
+func mapRows(values map[string]string) [][2]string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	rows := make([][2]string, 0, len(keys))
+	for _, key := range keys {
+		rows = append(rows, [2]string{key, values[key]})
+	}
+	return rows
+}
+
+func escapeCell(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
+
+func showReport(ctx modules.ActionContext) (string, error) {
+	format := strings.ToLower(ctx.Params["format"])
+	if format == "" {
+		format = "markdown"
+		if strings.EqualFold(filepath.Ext(ctx.Params["out"]), ".json") {
+			format = "json"
+		}
+	}
+
+	r := collectReport()
+	var out string
+	switch format {
+	case "markdown", "md":
+		out = r.markdown()
+	case "json":
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode report: %w", err)
+		}
+		out = string(data)
+	default:
+		return "", modules.UsageError("unknown format %q (use markdown or json)", format)
+	}
+
+	path := ctx.Params["out"]
+	if path == "" {
+		return out, nil
+	}
+	if err := os.WriteFile(path, []byte(out+"\n"), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write report: %w", err)
+	}
+	return fmt.Sprintf("Report written to %s (secrets redacted; review before sharing).", path), nil
+}
@@ -0,0 +1,385 @@
+package envinfo
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go-devtools/internal/appdir"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/redact"
+)
+
+const (
+	snapshotDir  = "env-snapshots"
+	hashedPrefix = "hmac-sha256:"
+	currentLabel = "current environment"
+	keyFile      = "env-snapshot.key"
+	keySize      = 32
+)
+
+// Variables such as "_" change with every command and only add noise.
+var volatileVars = map[string]bool{"_": true, "OLDPWD": true, "SHLVL": true}
+
+type snapshot struct {
+	Name      string            `json:"name"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Hostname  string            `json:"hostname,omitempty"`
+	Variables map[string]string `json:"variables"`
+}
+
+func currentSnapshot(name string) (snapshot, error) {
+	secret, err := snapshotKey()
+	if err != nil {
+		return snapshot{}, err
+	}
+	s := snapshot{Name: name, CreatedAt: time.Now().UTC().Truncate(time.Second), Variables: map[string]string{}}
+	s.Hostname, _ = os.Hostname()
+	for _, pair := range os.Environ() {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" || volatileVars[key] {
+			continue
+		}
+		s.Variables[key] = protectValue(secret, key, value)
+	}
+	return s, nil
+}
+
+// protectValue replaces secret-looking values with a keyed hash so snapshots
+// can be compared without storing the secret. URL passwords are hashed as a
+// whole value too. A plain hash of a short token could be brute-forced from a
+// leaked snapshot; the HMAC key never leaves this machine.
+func protectValue(secret []byte, key, value string) string {
+	if value == "" {
+		return value
+	}
+	if redact.IsSecretKey(key) || redact.URL(value) != value {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(value))
+		return hashedPrefix + hex.EncodeToString(mac.Sum(nil))[:16]
+	}
+	return value
+}
+
+// snapshotKey returns the per-install HMAC key, creating it with 0600
+// permissions on first use. Snapshots only compare equal under the key that
+// wrote them.
+func snapshotKey() ([]byte, error) {
+	path, err := appdir.Path(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(key) < keySize {
+			return nil, fmt.Errorf("snapshot key %s is corrupt (delete it to create a new one)", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read snapshot key: %w", err)
+	}
+
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate snapshot key: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		// Another devtools process created it first; use theirs.
+		return snapshotKey()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot key: %w", err)
+	}
+	if _, err := f.WriteString(hex.EncodeToString(key) + "\n"); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write snapshot key: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot key: %w", err)
+	}
+	return key, nil
+}
+
+func snapshotPath(name string) (string, error) {
+	dir, err := appdir.Path(snapshotDir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// resolveSnapshot accepts a file path or the name of a saved snapshot.
+func resolveSnapshot(ref string) (string, error) {
+	if _, err := os.Stat(ref); err == nil {
+		return ref, nil
+	}
+	if strings.ContainsAny(ref, `/\`) {
+		return "", modules.NotFoundError("snapshot file %s does not exist", ref)
+	}
+	path, err := snapshotPath(ref)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", modules.NotFoundError("unknown snapshot %q (see env-snapshots)", ref)
+	}
+	return path, nil
+}
+
+func loadSnapshot(ref string) (snapshot, error) {
+	path, err := resolveSnapshot(ref)
+	if err != nil {
+		return snapshot{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snapshot{}, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return snapshot{}, fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+	}
+	if s.Name == "" {
+		s.Name = path
+	}
+	return s, nil
+}
+
+func saveSnapshot(ctx modules.ActionContext) (string, error) {
+	name := ctx.Params["name"]
+	if name == "" && len(ctx.Positionals) > 0 {
+		name = ctx.Positionals[0]
+	}
+	if name == "" {
+		name = time.Now().Format("20060102-150405")
+	}
+	if strings.ContainsAny(name, `/\`) {
+		return "", modules.UsageError("snapshot name %q must not contain path separators (use --out for a file)", name)
+	}
+
+	path := ctx.Params["out"]
+	if path == "" {
+		var err error
+		if path, err = snapshotPath(name); err != nil {
+			return "", err
+		}
+	}
+
+	s, err := currentSnapshot(name)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	hashed := 0
+	for _, value := range s.Variables {
+		if strings.HasPrefix(value, hashedPrefix) {
+			hashed++
+		}
+	}
+	return fmt.Sprintf("Saved %d variables (%d secret-looking values hashed) to %s.", len(s.Variables), hashed, path), nil
+}
+
+func listSnapshots(_ modules.ActionContext) (string, error) {
+	dir, err := appdir.Path(snapshotDir)
+	if err != nil {
+		return "", err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "No snapshots saved yet (use env-snapshot).", nil
+	}
+
+	snapshots := make([]snapshot, 0, len(files))
+	for _, file := range files {
+		s, err := loadSnapshot(file)
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, s)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt) })
+
+	var b strings.Builder
+	for _, s := range snapshots {
+		fmt.Fprintf(&b, "%-24s %s  %d vars  %s\n", s.Name, s.CreatedAt.Local().Format("2006-01-02 15:04:05"), len(s.Variables), s.Hostname)
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func diffSnapshots(ctx modules.ActionContext) (string, error) {
+	if len(ctx.Positionals) == 0 || len(ctx.Positionals) > 2 {
+		return "", modules.UsageError("env-diff needs a snapshot to compare with the current environment, or two snapshots")
+	}
+	before, err := loadSnapshot(ctx.Positionals[0])
+	if err != nil {
+		return "", err
+	}
+	var after snapshot
+	if len(ctx.Positionals) == 2 {
+		after, err = loadSnapshot(ctx.Positionals[1])
+	} else {
+		after, err = currentSnapshot(currentLabel)
+	}
+	if err != nil {
+		return "", err
+	}
+	return renderEnvDiff(before, after), nil
+}
+
+func renderEnvDiff(before, after snapshot) string {
+	keys := make([]string, 0, len(before.Variables)+len(after.Variables))
+	for key := range before.Variables {
+		keys = append(keys, key)
+	}
+	for key := range after.Variables {
+		if _, ok := before.Variables[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", describeSnapshot(before), describeSnapshot(after))
+	changes := 0
+	for _, key := range keys {
+		old, inBefore := before.Variables[key]
+		value, inAfter := after.Variables[key]
+		switch {
+		case volatileVars[key]:
+			continue
+		case !inAfter:
+			fmt.Fprintf(&b, "- %s=%s\n", key, old)
+		case !inBefore:
+			fmt.Fprintf(&b, "+ %s=%s\n", key, value)
+		case old != value:
+			writeChange(&b, key, old, value)
+		default:
+			continue
+		}
+		changes++
+	}
+	if changes == 0 {
+		b.WriteString("No differences.")
+	} else {
+		fmt.Fprintf(&b, "\n%d variable(s) differ.", changes)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// writeChange shows list-valued variables such as PATH entry by entry, since
+// the usual difference is one directory added, removed or moved.
+func writeChange(b *strings.Builder, key, old, value string) {
+	sep := string(os.PathListSeparator)
+	listValued := strings.HasSuffix(key, "PATH") || strings.HasSuffix(key, "DIRS")
+	if !listValued || strings.HasPrefix(old, hashedPrefix) || !strings.Contains(old+value, sep) {
+		fmt.Fprintf(b, "~ %s: %q -> %q\n", key, old, value)
+		return
+	}
+
+	oldEntries := strings.Split(old, sep)
+	newEntries := strings.Split(value, sep)
+	fmt.Fprintf(b, "~ %s:\n", key)
+	oldSet := indexEntries(oldEntries)
+	newSet := indexEntries(newEntries)
+	for _, entry := range oldEntries {
+		if _, ok := newSet[entry]; !ok {
+			fmt.Fprintf(b, "    - %s\n", entry)
+		}
+	}
+	stable := longestCommonSubsequence(commonEntries(oldEntries, newSet), commonEntries(newEntries, oldSet))
+	for i, entry := range newEntries {
+		j, ok := oldSet[entry]
+		switch {
+		case !ok:
+			fmt.Fprintf(b, "    + %s (position %d)\n", entry, i+1)
+		case !stable[entry]:
+			fmt.Fprintf(b, "    ~ %s moved %d -> %d\n", entry, j+1, i+1)
+		}
+	}
+}
+
+func indexEntries(entries []string) map[string]int {
+	index := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		if _, ok := index[entry]; !ok {
+			index[entry] = i
+		}
+	}
+	return index
+}
+
+func commonEntries(entries []string, other map[string]int) []string {
+	common := make([]string, 0, len(entries))
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		if _, ok := other[entry]; ok && !seen[entry] {
+			seen[entry] = true
+			common = append(common, entry)
+		}
+	}
+	return common
+}
+
+// longestCommonSubsequence returns the entries that kept their relative
+// order; everything else in both lists was moved.
+func longestCommonSubsequence(a, b []string) map[string]bool {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	stable := map[string]bool{}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			stable[a[i]] = true
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return stable
+}
+
+func describeSnapshot(s snapshot) string {
+	if s.Name == currentLabel {
+		return currentLabel
+	}
+	return fmt.Sprintf("%s (%s)", s.Name, s.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+}
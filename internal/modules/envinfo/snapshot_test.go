@@ -0,0 +1,108 @@
+package envinfo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/devtoolstest"
+	"go-devtools/internal/modules"
+)
+
+func TestSnapshotKey(t *testing.T) {
+	home := devtoolstest.Isolate(t)
+
+	first, err := snapshotKey()
+	if err != nil {
+		t.Fatalf("snapshotKey() error = %v", err)
+	}
+	info, err := os.Stat(filepath.Join(home, keyFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("key file permissions = %o, want 600", perm)
+	}
+	second, err := snapshotKey()
+	if err != nil || string(first) != string(second) || len(first) != keySize {
+		t.Errorf("snapshotKey() not stable: %x vs %x (err %v)", first, second, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(home, keyFile), []byte("not hex"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := snapshotKey(); err == nil || !strings.Contains(err.Error(), "corrupt") {
+		t.Errorf("snapshotKey() with a corrupt file error = %v", err)
+	}
+}
+
+func TestProtectValue(t *testing.T) {
+	key := []byte(strings.Repeat("k", keySize))
+	other := []byte(strings.Repeat("o", keySize))
+
+	hashed := protectValue(key, "GITHUB_TOKEN", "1234")
+	plain := sha256.Sum256([]byte("1234"))
+	if !strings.HasPrefix(hashed, hashedPrefix) || strings.Contains(hashed, hex.EncodeToString(plain[:])[:16]) {
+		t.Errorf("protectValue(GITHUB_TOKEN) = %q, want a keyed digest", hashed)
+	}
+	if hashed != protectValue(key, "GITHUB_TOKEN", "1234") {
+		t.Error("protectValue() is not deterministic for one key")
+	}
+	if hashed == protectValue(other, "GITHUB_TOKEN", "1234") {
+		t.Error("protectValue() ignores the key")
+	}
+	if got := protectValue(key, "HTTPS_PROXY", "http://u:p@proxy:3128"); !strings.HasPrefix(got, hashedPrefix) {
+		t.Errorf("protectValue(proxy with password) = %q", got)
+	}
+	if got := protectValue(key, "EDITOR", "vim"); got != "vim" {
+		t.Errorf("protectValue(EDITOR) = %q, want vim", got)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	devtoolstest.Isolate(t)
+	t.Setenv("DEVTOOLS_TEST_TOKEN", "before")
+	t.Setenv("DEVTOOLS_TEST_EDITOR", "vim")
+
+	if _, err := saveSnapshot(modules.ActionContext{Positionals: []string{"base"}}); err != nil {
+		t.Fatalf("saveSnapshot() error = %v", err)
+	}
+	out, err := diffSnapshots(modules.ActionContext{Positionals: []string{"base"}})
+	if err != nil || !strings.Contains(out, "No differences.") {
+		t.Fatalf("diffSnapshots() right after saving = %q, %v", out, err)
+	}
+
+	t.Setenv("DEVTOOLS_TEST_TOKEN", "after")
+	t.Setenv("DEVTOOLS_TEST_EDITOR", "nano")
+	out, err = diffSnapshots(modules.ActionContext{Positionals: []string{"base"}})
+	if err != nil {
+		t.Fatalf("diffSnapshots() error = %v", err)
+	}
+	if strings.Contains(out, "before") || strings.Contains(out, "after") {
+		t.Errorf("diff leaks the secret value:\n%s", out)
+	}
+	for _, want := range []string{`~ DEVTOOLS_TEST_EDITOR: "vim" -> "nano"`, "~ DEVTOOLS_TEST_TOKEN: \"" + hashedPrefix, "2 variable(s) differ."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("diff missing %q:\n%s", want, out)
+		}
+	}
+
+	if _, err := diffSnapshots(modules.ActionContext{Positionals: []string{"missing"}}); modules.KindOf(err) != modules.KindNotFound {
+		t.Errorf("diffSnapshots(missing) error = %v, want not found", err)
+	}
+}
+
+func TestRenderEnvDiffComparesPathEntries(t *testing.T) {
+	sep := string(os.PathListSeparator)
+	before := snapshot{Name: currentLabel, Variables: map[string]string{"PATH": strings.Join([]string{"/a", "/b", "/c", "/d", "/e"}, sep)}}
+	after := snapshot{Name: currentLabel, Variables: map[string]string{"PATH": strings.Join([]string{"/e", "/a", "/c", "/d", "/f"}, sep)}}
+	out := renderEnvDiff(before, after)
+	for _, want := range []string{"    - /b", "    + /f (position 5)", "    ~ /e moved 5 -> 1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("renderEnvDiff() missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+)
+
+type ErrorKind int
+
+const (
+	KindActionFailed ErrorKind = iota
+	KindUsage
+	KindNotFound
+	KindRequirementFailed
+	KindTimeout
+	KindCancelled
+)
+
+const (
+	ExitOK                = 0
+	ExitActionFailed      = 1
+	ExitUsage             = 2
+	ExitNotFound          = 3
+	ExitRequirementFailed = 4
+	ExitTimeout           = 5
+	ExitCancelled         = 130
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindUsage:
+		return "usage error"
+	case KindNotFound:
+		return "not found"
+	case KindRequirementFailed:
+		return "requirement failed"
+	case KindTimeout:
+		return "timeout"
+	case KindCancelled:
+		return "cancelled"
+	default:
+		return "action failed"
+	}
+}
+
+func (k ErrorKind) ExitCode() int {
+	switch k {
+	case KindUsage:
+		return ExitUsage
+	case KindNotFound:
+		return ExitNotFound
+	case KindRequirementFailed:
+		return ExitRequirementFailed
+	case KindTimeout:
+		return ExitTimeout
+	case KindCancelled:
+		return ExitCancelled
+	default:
+		return ExitActionFailed
+	}
+}
+
+type Error struct {
+	Kind ErrorKind
+	Err  error
+	// Code overrides the kind's exit code when non-zero.
+	Code int
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func newError(kind ErrorKind, format string, args ...any) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// ExitStatusError fails the action with a specific exit code, for example
+// to pass on the status of a child process.
+func ExitStatusError(code int, format string, args ...any) error {
+	return &Error{Kind: KindActionFailed, Err: fmt.Errorf(format, args...), Code: code}
+}
+
+func UsageError(format string, args ...any) error {
+	return newError(KindUsage, format, args...)
+}
+
+func NotFoundError(format string, args ...any) error {
+	return newError(KindNotFound, format, args...)
+}
+
+func RequirementError(format string, args ...any) error {
+	return newError(KindRequirementFailed, format, args...)
+}
+
+// CancelledError reports that the user declined to go on, for example at a
+// confirmation prompt.
+func CancelledError(format string, args ...any) error {
+	return newError(KindCancelled, format, args...)
+}
+
+func ActionError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var typed *Error
+	if errors.As(err, &typed) {
+		return err
+	}
+
+	kind := KindActionFailed
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		kind = KindCancelled
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded):
+		kind = KindTimeout
+	case errors.As(err, &netErr) && netErr.Timeout():
+		kind = KindTimeout
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+func KindOf(err error) ErrorKind {
+	var typed *Error
+	if errors.As(err, &typed) {
+		return typed.Kind
+	}
+	return KindActionFailed
+}
+
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var typed *Error
+	if errors.As(err, &typed) && typed.Code != 0 {
+		return typed.Code
+	}
+	return KindOf(err).ExitCode()
+}
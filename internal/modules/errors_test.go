@@ -0,0 +1,67 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestActionErrorKindAndExitCode(t *testing.T) {
+	usage := UsageError("bad flag %q", "--x")
+	tests := []struct {
+		name string
+		err  error
+		kind ErrorKind
+		code int
+	}{
+		{"nil", nil, KindActionFailed, ExitOK},
+		{"plain", errors.New("boom"), KindActionFailed, ExitActionFailed},
+		{"usage", usage, KindUsage, ExitUsage},
+		{"wrapped usage", fmt.Errorf("run: %w", usage), KindUsage, ExitUsage},
+		{"not found", NotFoundError("unknown module %q", "x"), KindNotFound, ExitNotFound},
+		{"requirement", RequirementError("missing %s", "go"), KindRequirementFailed, ExitRequirementFailed},
+		{"cancelled", CancelledError("declined"), KindCancelled, ExitCancelled},
+		{"exit status", ExitStatusError(7, "child exited with status %d", 7), KindActionFailed, 7},
+		{"context canceled", fmt.Errorf("fetch: %w", context.Canceled), KindCancelled, ExitCancelled},
+		{"context deadline", context.DeadlineExceeded, KindTimeout, ExitTimeout},
+		{"os deadline", os.ErrDeadlineExceeded, KindTimeout, ExitTimeout},
+		{"net timeout", fmt.Errorf("dial: %w", timeoutError{}), KindTimeout, ExitTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ActionError(tt.err)
+			if (err == nil) != (tt.err == nil) {
+				t.Fatalf("ActionError(%v) = %v", tt.err, err)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("ActionError(%v) does not wrap the original error", tt.err)
+			}
+			if err != nil {
+				if got := KindOf(err); got != tt.kind {
+					t.Errorf("KindOf = %v, want %v", got, tt.kind)
+				}
+			}
+			if got := ExitCode(err); got != tt.code {
+				t.Errorf("ExitCode = %d, want %d", got, tt.code)
+			}
+		})
+	}
+}
+
+func TestActionErrorKeepsTypedErrors(t *testing.T) {
+	usage := UsageError("bad")
+	if got := ActionError(usage); got != usage {
+		t.Errorf("ActionError re-wrapped a typed error: %#v", got)
+	}
+	if got := KindOf(errors.New("untyped")); got != KindActionFailed {
+		t.Errorf("KindOf(untyped) = %v, want %v", got, KindActionFailed)
+	}
+}
@@ -0,0 +1,72 @@
+package fake
+
+var firstNames = []string{
+	"Aaliyah", "Aiden", "Amara", "Andre", "Anika", "Arjun", "Beatriz", "Benjamin", "Camille", "Carlos",
+	"Chloe", "Daniel", "Dmitri", "Elena", "Emeka", "Emma", "Farah", "Felix", "Grace", "Hana",
+	"Hugo", "Ines", "Isaac", "Jamal", "Julia", "Kai", "Keiko", "Lars", "Layla", "Leo",
+	"Lucia", "Malik", "Maya", "Mateo", "Mei", "Nadia", "Noah", "Olivia", "Omar", "Priya",
+	"Rafael", "Rosa", "Samuel", "Sara", "Sofia", "Tariq", "Thea", "Victor", "Yara", "Zoe",
+}
+
+var lastNames = []string{
+	"Adeyemi", "Alvarez", "Andersen", "Bauer", "Becker", "Brooks", "Chen", "Costa", "Dubois", "Evans",
+	"Fernandes", "Fischer", "Garcia", "Gupta", "Hansen", "Hughes", "Ivanova", "Jensen", "Kim", "Kowalski",
+	"Larsen", "Lopez", "Martin", "Moreau", "Mueller", "Murphy", "Nakamura", "Nguyen", "Novak", "Okafor",
+	"Olsen", "Patel", "Petrov", "Quinn", "Reyes", "Rossi", "Santos", "Schmidt", "Silva", "Singh",
+	"Suzuki", "Tanaka", "Thompson", "Walker", "Weber", "Williams", "Wilson", "Yilmaz", "Young", "Zhang",
+}
+
+var streetNames = []string{
+	"Maple", "Oak", "Cedar", "Pine", "Elm", "Willow", "Birch", "Lake", "Hill", "River",
+	"Sunset", "Park", "Meadow", "Forest", "Spring", "Harbor", "Orchard", "Ridge", "Valley", "Highland",
+}
+
+var streetSuffixes = []string{"St", "Ave", "Rd", "Blvd", "Ln", "Dr", "Way", "Ct", "Pl", "Terrace"}
+
+type place struct {
+	City, State, Country, Prefix string
+}
+
+// Postcodes are built from Prefix plus random digits so they look local
+// without pointing at a specific real address.
+var places = []place{
+	{"Springfield", "IL", "United States", "627"},
+	{"Portland", "OR", "United States", "972"},
+	{"Austin", "TX", "United States", "787"},
+	{"Denver", "CO", "United States", "802"},
+	{"Madison", "WI", "United States", "537"},
+	{"Raleigh", "NC", "United States", "276"},
+	{"Boise", "ID", "United States", "837"},
+	{"Tucson", "AZ", "United States", "857"},
+	{"Toronto", "ON", "Canada", "M5"},
+	{"Vancouver", "BC", "Canada", "V6"},
+	{"Manchester", "", "United Kingdom", "M1"},
+	{"Bristol", "", "United Kingdom", "BS1"},
+	{"Lyon", "", "France", "690"},
+	{"Hamburg", "", "Germany", "204"},
+	{"Utrecht", "", "Netherlands", "35"},
+	{"Melbourne", "VIC", "Australia", "30"},
+}
+
+var companyWords = []string{
+	"Acme", "Apex", "Blue Harbor", "Brightline", "Cobalt", "Crescent", "Evergreen", "Fathom", "Granite", "Helix",
+	"Ironwood", "Juniper", "Keystone", "Lumen", "Meridian", "Northwind", "Orbit", "Pinnacle", "Quartz", "Redwood",
+	"Silverline", "Summit", "Tidal", "Vertex", "Westfield",
+}
+
+var companySuffixes = []string{"Inc", "LLC", "Ltd", "Group", "Labs", "Systems", "Partners", "Co"}
+
+var jobTitles = []string{
+	"Software Engineer", "Product Manager", "Data Analyst", "Designer", "Support Specialist",
+	"Account Executive", "QA Engineer", "DevOps Engineer", "Marketing Manager", "Office Manager",
+	"Engineering Manager", "Technical Writer", "Recruiter", "Sales Associate", "Security Analyst",
+}
+
+// Reserved by RFC 2606 so generated addresses can never reach a real inbox.
+var emailDomains = []string{"example.com", "example.org", "example.net"}
+
+var loremWords = []string{
+	"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do",
+	"eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna", "aliqua", "enim",
+	"minim", "veniam", "quis", "nostrud", "exercitation", "ullamco", "laboris", "nisi", "aliquip", "commodo",
+}
@@ -0,0 +1,207 @@
+package fake
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/requirements"
+)
+
+// stderr receives the note with a generated seed, which must not mix with
+// the records on stdout.
+var stderr io.Writer = os.Stderr
+
+const (
+	defaultCount = 10
+	maxCount     = 100000
+)
+
+type Tool struct{}
+
+func New() modules.Tool {
+	return Tool{}
+}
+
+func (Tool) ID() string { return "fake" }
+
+func (Tool) Label() string { return "Fake Data" }
+
+func (Tool) Description() string { return "Seeded fake records as JSON, CSV or SQL" }
+
+func (Tool) Requirements() []requirements.Check { return nil }
+
+func (Tool) Actions() []modules.Action {
+	return []modules.Action{
+		{
+			ID:          "records",
+			Label:       "Fake records",
+			Description: "Records built from a preset or a column:type field list",
+			Usage:       "devtools run fake records [user|company|address | --fields id:seq,name,email,age:int:18-90] [--count 10] [--seed 42] [--format json|ndjson|csv|sql] [--table users] [--dialect ansi|mysql] [--out <file>]",
+			Run:         fakeRecords,
+		},
+		{
+			ID:          "types",
+			Label:       "Field types",
+			Description: "Field types and presets available to records",
+			Usage:       "devtools run fake types",
+			Run:         listTypes,
+		},
+	}
+}
+
+func (Tool) Menu() *menu.Menu {
+	return menu.NewBuilder("Fake Data").
+		Action("Users (JSON)", "10 user records", func() (string, error) {
+			return menuRecords(map[string]string{"preset": "user"})
+		}).
+		Action("Users (SQL)", "10 INSERT rows for a users table", func() (string, error) {
+			return menuRecords(map[string]string{"preset": "user", "format": "sql", "table": "users"})
+		}).
+		Action("Companies (CSV)", "10 company records", func() (string, error) {
+			return menuRecords(map[string]string{"preset": "company", "format": "csv"})
+		}).
+		Action("Custom fields", "Prompt for fields, count, seed and format", customPrompt).
+		Action("Field types", "Field types and presets", func() (string, error) {
+			return listTypes(modules.ActionContext{})
+		}).
+		WithBack().
+		Build()
+}
+
+// schema resolves --fields, --preset or a positional that is either a
+// preset name or a field list.
+func schema(ctx modules.ActionContext) ([]field, error) {
+	spec := ctx.Params["fields"]
+	preset := ctx.Params["preset"]
+	if spec == "" && preset == "" && len(ctx.Positionals) > 0 {
+		if _, ok := presets[ctx.Positionals[0]]; ok {
+			preset = ctx.Positionals[0]
+		} else {
+			spec = strings.Join(ctx.Positionals, ",")
+		}
+	}
+	if spec == "" && preset != "" {
+		var ok bool
+		if spec, ok = presets[preset]; !ok {
+			return nil, modules.UsageError("unknown preset %q (use %s)", preset, strings.Join(presetNames(), ", "))
+		}
+	}
+	return parseFields(spec)
+}
+
+func fakeRecords(ctx modules.ActionContext) (string, error) {
+	fields, err := schema(ctx)
+	if err != nil {
+		return "", err
+	}
+	count, err := intParam(ctx, "count", defaultCount, 1, maxCount)
+	if err != nil {
+		return "", err
+	}
+	seed, random := time.Now().UnixNano(), true
+	if raw := ctx.Params["seed"]; raw != "" {
+		random = false
+		if seed, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return "", modules.UsageError("--seed must be an integer")
+		}
+	}
+
+	g := newGenerator(seed)
+	rows := make([][]any, count)
+	for i := range rows {
+		rows[i] = g.row(i, fields)
+	}
+	data, err := render(strings.ToLower(ctx.Params["format"]), ctx.Params["table"], ctx.Params["dialect"], fields, rows)
+	if err != nil {
+		return "", err
+	}
+	if path := ctx.Params["out"]; path != "" {
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return fmt.Sprintf("Wrote %d records to %s (seed %d).", count, path, seed), nil
+	}
+	if random {
+		fmt.Fprintf(stderr, "Seed: %d (pass --seed %d to reproduce these records)\n", seed, seed)
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+// menuRecords picks the seed up front and shows it below the records, since
+// the TUI does not show stderr.
+func menuRecords(params map[string]string) (string, error) {
+	if params["seed"] == "" {
+		params["seed"] = strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	out, err := fakeRecords(modules.ActionContext{Params: params})
+	if err != nil {
+		return "", err
+	}
+	return out + "\n\nSeed: " + params["seed"], nil
+}
+
+func listTypes(modules.ActionContext) (string, error) {
+	names := make([]string, 0, len(fieldTypes))
+	for name := range fieldTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tDESCRIPTION")
+	for _, name := range names {
+		fmt.Fprintf(w, "%s\t%s\n", name, fieldTypes[name].Description)
+	}
+	fmt.Fprintln(w, "\nPRESET\tFIELDS")
+	for _, name := range presetNames() {
+		fmt.Fprintf(w, "%s\t%s\n", name, presets[name])
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func customPrompt() (string, error) {
+	params := map[string]string{}
+	for _, question := range []struct{ key, label string }{
+		{"fields", "Fields (e.g. id:seq,name,email,age:int:18-90): "},
+		{"count", fmt.Sprintf("Count [%d]: ", defaultCount)},
+		{"seed", "Seed (blank for random): "},
+		{"format", "Format (json/ndjson/csv/sql) [json]: "},
+	} {
+		answer, err := cliutil.Prompt(question.label)
+		if err != nil {
+			return "", err
+		}
+		params[question.key] = answer
+	}
+	if params["format"] == "sql" {
+		table, err := cliutil.Prompt("Table [records]: ")
+		if err != nil {
+			return "", err
+		}
+		params["table"] = table
+	}
+	return menuRecords(params)
+}
+
+func intParam(ctx modules.ActionContext, name string, fallback, minimum, maximum int) (int, error) {
+	raw := ctx.Params[name]
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < minimum || value > maximum {
+		return 0, modules.UsageError("--%s must be a number between %d and %d", name, minimum, maximum)
+	}
+	return value, nil
+}
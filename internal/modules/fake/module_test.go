@@ -0,0 +1,117 @@
+package fake
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/modules"
+)
+
+func TestRecordsAreReproducibleFromSeed(t *testing.T) {
+	params := map[string]string{"count": "20", "seed": "42"}
+	first, err := fakeRecords(modules.ActionContext{Params: params, Positionals: []string{"user"}})
+	if err != nil {
+		t.Fatalf("records error = %v", err)
+	}
+	second, err := fakeRecords(modules.ActionContext{Params: params, Positionals: []string{"user"}})
+	if err != nil {
+		t.Fatalf("records error = %v", err)
+	}
+	if first != second {
+		t.Error("the same seed produced different records")
+	}
+}
+
+func TestCustomFieldsAsCSV(t *testing.T) {
+	out, err := fakeRecords(modules.ActionContext{Params: map[string]string{
+		"fields": "id:seq,name,email,age:int:18-90,active:bool",
+		"count":  "50",
+		"seed":   "7",
+		"format": "csv",
+	}})
+	if err != nil {
+		t.Fatalf("records error = %v", err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not CSV: %v", err)
+	}
+	if len(rows) != 51 || strings.Join(rows[0], ",") != "id,name,email,age,active" {
+		t.Fatalf("got %d rows with header %v", len(rows), rows[0])
+	}
+	emails := map[string]bool{}
+	for _, row := range rows[1:] {
+		if emails[row[2]] {
+			t.Errorf("duplicate email %s", row[2])
+		}
+		emails[row[2]] = true
+		if !strings.Contains(row[2], "@example.") {
+			t.Errorf("email %s is not at a reserved domain", row[2])
+		}
+	}
+
+	if _, err := fakeRecords(modules.ActionContext{Params: map[string]string{"fields": "age:nope"}}); modules.KindOf(err) != modules.KindUsage {
+		t.Errorf("unknown type error = %v, want usage error", err)
+	}
+}
+
+func TestSQLQuotesIdentifiers(t *testing.T) {
+	params := map[string]string{"fields": "id:seq,user:first_name", "count": "2", "seed": "1", "format": "sql", "table": "order"}
+	out, err := fakeRecords(modules.ActionContext{Params: params})
+	if err != nil {
+		t.Fatalf("records error = %v", err)
+	}
+	if !strings.HasPrefix(out, `INSERT INTO "order" ("id", "user") VALUES`) {
+		t.Errorf("ansi output = %q", out)
+	}
+
+	params["dialect"] = "mysql"
+	out, err = fakeRecords(modules.ActionContext{Params: params})
+	if err != nil {
+		t.Fatalf("records error = %v", err)
+	}
+	if !strings.HasPrefix(out, "INSERT INTO `order` (`id`, `user`) VALUES") {
+		t.Errorf("mysql output = %q", out)
+	}
+
+	params["dialect"] = "oracle"
+	if _, err := fakeRecords(modules.ActionContext{Params: params}); modules.KindOf(err) != modules.KindUsage {
+		t.Errorf("unknown dialect error = %v, want usage error", err)
+	}
+}
+
+func TestGeneratedSeedIsReported(t *testing.T) {
+	var note bytes.Buffer
+	stderr = &note
+	t.Cleanup(func() { stderr = os.Stderr })
+
+	first, err := fakeRecords(modules.ActionContext{Positionals: []string{"user"}})
+	if err != nil {
+		t.Fatalf("records error = %v", err)
+	}
+	match := regexp.MustCompile(`^Seed: (-?\d+) `).FindStringSubmatch(note.String())
+	if match == nil {
+		t.Fatalf("stderr = %q, want the generated seed", note.String())
+	}
+	replay, err := fakeRecords(modules.ActionContext{Params: map[string]string{"seed": match[1]}, Positionals: []string{"user"}})
+	if err != nil || replay != first {
+		t.Errorf("--seed %s did not reproduce the records (%v)", match[1], err)
+	}
+
+	note.Reset()
+	if _, err := fakeRecords(modules.ActionContext{Params: map[string]string{"seed": "1"}, Positionals: []string{"user"}}); err != nil {
+		t.Fatal(err)
+	}
+	if note.Len() != 0 {
+		t.Errorf("stderr = %q with an explicit seed, want nothing", note.String())
+	}
+
+	out, err := menuRecords(map[string]string{"preset": "company", "format": "csv"})
+	if err != nil || !regexp.MustCompile(`\n\nSeed: -?\d+$`).MatchString(out) {
+		t.Errorf("menu output = %q, %v, want a seed line", out, err)
+	}
+}
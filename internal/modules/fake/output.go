@@ -0,0 +1,159 @@
+package fake
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/modules"
+)
+
+func render(format, table, dialect string, fields []field, rows [][]any) ([]byte, error) {
+	switch format {
+	case "", "json":
+		return renderJSON(fields, rows, true)
+	case "ndjson", "jsonl":
+		return renderJSON(fields, rows, false)
+	case "csv":
+		return renderCSV(fields, rows)
+	case "sql":
+		return renderSQL(table, dialect, fields, rows)
+	}
+	return nil, modules.UsageError("--format must be json, ndjson, csv or sql")
+}
+
+// renderJSON writes objects by hand so keys keep the schema order instead of
+// the alphabetical order of a marshalled map.
+func renderJSON(fields []field, rows [][]any, array bool) ([]byte, error) {
+	var b bytes.Buffer
+	if array {
+		b.WriteString("[")
+	}
+	for i, row := range rows {
+		if array {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString("\n  ")
+		}
+		b.WriteString("{")
+		for j, f := range fields {
+			if j > 0 {
+				b.WriteString(",")
+			}
+			if array {
+				b.WriteString("\n    ")
+			}
+			key, _ := json.Marshal(f.Name)
+			value, err := json.Marshal(row[j])
+			if err != nil {
+				return nil, err
+			}
+			b.Write(key)
+			b.WriteString(":")
+			if array {
+				b.WriteString(" ")
+			}
+			b.Write(value)
+		}
+		if array {
+			b.WriteString("\n  ")
+		}
+		b.WriteString("}")
+		if !array {
+			b.WriteString("\n")
+		}
+	}
+	if array {
+		if len(rows) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("]\n")
+	}
+	return b.Bytes(), nil
+}
+
+func renderCSV(fields []field, rows [][]any) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	header := make([]string, len(fields))
+	for i, f := range fields {
+		header[i] = f.Name
+	}
+	w.Write(header)
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = fmt.Sprint(value)
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	return b.Bytes(), w.Error()
+}
+
+// renderSQL emits one multi-row INSERT per 500 rows, which every common
+// database accepts without hitting statement size limits. Identifiers are
+// quoted so reserved words such as order or user work as names.
+func renderSQL(table, dialect string, fields []field, rows [][]any) ([]byte, error) {
+	table = cliutil.FirstNonEmpty(table, "records")
+	if !identifierPattern.MatchString(table) {
+		return nil, modules.UsageError("invalid --table %q (letters, digits and _ only)", table)
+	}
+	quote, err := identifierQuote(dialect)
+	if err != nil {
+		return nil, err
+	}
+	table = quote + table + quote
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = quote + f.Name + quote
+	}
+	var b bytes.Buffer
+	for start := 0; start < len(rows); start += 500 {
+		end := min(start+500, len(rows))
+		fmt.Fprintf(&b, "INSERT INTO %s (%s) VALUES\n", table, strings.Join(columns, ", "))
+		for i, row := range rows[start:end] {
+			values := make([]string, len(row))
+			for j, value := range row {
+				values[j] = sqlLiteral(value)
+			}
+			separator := ","
+			if start+i == end-1 {
+				separator = ";"
+			}
+			fmt.Fprintf(&b, "  (%s)%s\n", strings.Join(values, ", "), separator)
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// identifierQuote returns the standard double quote, or the backtick MySQL
+// uses unless ANSI_QUOTES is set. Names are limited to identifierPattern, so
+// they never contain the quote character.
+func identifierQuote(dialect string) (string, error) {
+	switch strings.ToLower(dialect) {
+	case "", "ansi", "postgres", "sqlite":
+		return `"`, nil
+	case "mysql", "mariadb":
+		return "`", nil
+	}
+	return "", modules.UsageError("--dialect must be ansi, postgres, sqlite or mysql")
+}
+
+func sqlLiteral(value any) string {
+	switch v := value.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+	return "'" + strings.ReplaceAll(fmt.Sprint(value), "'", "''") + "'"
+}
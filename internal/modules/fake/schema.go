@@ -0,0 +1,286 @@
+package fake
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-devtools/internal/modules"
+)
+
+var presets = map[string]string{
+	"user":    "id:uuid,first_name,last_name,email,username,phone,birthdate,created_at:datetime",
+	"company": "id:uuid,name:company,domain,phone,address,city,country",
+	"address": "street,city,state,postcode,country",
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Dates come from fixed windows rather than time.Now so a seed always
+// reproduces the same records.
+var (
+	dateFrom      = time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	dateTo        = time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC)
+	birthdateFrom = time.Date(1955, 1, 1, 0, 0, 0, 0, time.UTC)
+	birthdateTo   = time.Date(2006, 12, 31, 0, 0, 0, 0, time.UTC)
+)
+
+type field struct {
+	Name string
+	Type string
+	Min  int64
+	Max  int64
+}
+
+type fieldType struct {
+	Description string
+	Value       func(r *record, f field) any
+}
+
+var fieldTypes = map[string]fieldType{
+	"seq":        {"Row number starting at 1", func(r *record, _ field) any { return int64(r.index + 1) }},
+	"uuid":       {"Random UUID v4", func(r *record, _ field) any { return r.uuid() }},
+	"first_name": {"Given name", func(r *record, _ field) any { return r.person().First }},
+	"last_name":  {"Family name", func(r *record, _ field) any { return r.person().Last }},
+	"name":       {"Full name", func(r *record, _ field) any { p := r.person(); return p.First + " " + p.Last }},
+	"username":   {"Unique login derived from the name", func(r *record, _ field) any { return r.person().Username }},
+	"email":      {"Unique address at an example.* domain, derived from the name", func(r *record, _ field) any { return r.person().Email }},
+	"phone":      {"Fictional +1 555-01xx number", func(r *record, _ field) any { return r.phone() }},
+	"street":     {"Street address", func(r *record, _ field) any { return r.location().Street }},
+	"city":       {"City", func(r *record, _ field) any { return r.location().City }},
+	"state":      {"State or province (may be empty)", func(r *record, _ field) any { return r.location().State }},
+	"postcode":   {"Postal code matching the city", func(r *record, _ field) any { return r.location().Postcode }},
+	"country":    {"Country", func(r *record, _ field) any { return r.location().Country }},
+	"address":    {"Single-line full address", func(r *record, _ field) any { return r.location().String() }},
+	"company":    {"Company name", func(r *record, _ field) any { return r.company().Name }},
+	"domain":     {"Company domain under .example", func(r *record, _ field) any { return r.company().Domain }},
+	"job_title":  {"Job title", func(r *record, _ field) any { return pick(r.rng, jobTitles) }},
+	"date":       {"Date between 2019 and 2025 (YYYY-MM-DD)", func(r *record, _ field) any { return r.between(dateFrom, dateTo).Format("2006-01-02") }},
+	"datetime":   {"UTC timestamp between 2019 and 2025 (RFC 3339)", func(r *record, _ field) any { return r.between(dateFrom, dateTo).Format(time.RFC3339) }},
+	"birthdate":  {"Date of birth for an adult (YYYY-MM-DD)", func(r *record, _ field) any { return r.between(birthdateFrom, birthdateTo).Format("2006-01-02") }},
+	"int":        {"Integer, int:MIN-MAX (default 1-1000)", func(r *record, f field) any { return f.Min + r.rng.Int63n(f.Max-f.Min+1) }},
+	"bool":       {"true or false", func(r *record, _ field) any { return r.rng.Intn(2) == 1 }},
+	"word":       {"Lorem ipsum word", func(r *record, _ field) any { return pick(r.rng, loremWords) }},
+	"sentence":   {"Lorem ipsum sentence", func(r *record, _ field) any { return r.sentence() }},
+}
+
+// parseFields reads "column:type[:arg]" entries; a bare type names its own
+// column, and "int:18-90" is shorthand for a column named int with a range.
+func parseFields(spec string) ([]field, error) {
+	var fields []field
+	seen := map[string]bool{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if _, ok := fieldTypes[parts[0]]; ok && (len(parts) == 1 || parts[0] == "int" && len(parts) == 2 && !isFieldType(parts[1])) {
+			parts = append([]string{parts[0]}, parts...)
+		}
+		if len(parts) > 3 {
+			return nil, modules.UsageError("invalid field %q (use column:type[:arg])", entry)
+		}
+		f := field{Name: parts[0], Type: strings.ToLower(parts[1]), Min: 1, Max: 1000}
+		if !identifierPattern.MatchString(f.Name) {
+			return nil, modules.UsageError("invalid column name %q (letters, digits and _ only)", f.Name)
+		}
+		if seen[f.Name] {
+			return nil, modules.UsageError("duplicate column %q", f.Name)
+		}
+		seen[f.Name] = true
+		if _, ok := fieldTypes[f.Type]; !ok {
+			return nil, modules.UsageError("unknown field type %q (see fake types)", f.Type)
+		}
+		if len(parts) == 3 {
+			if f.Type != "int" {
+				return nil, modules.UsageError("field %q: only int takes an argument", entry)
+			}
+			if err := parseRange(parts[2], &f); err != nil {
+				return nil, err
+			}
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, modules.UsageError("no fields given (use --fields or --preset %s)", strings.Join(presetNames(), "|"))
+	}
+	return fields, nil
+}
+
+func isFieldType(name string) bool {
+	_, ok := fieldTypes[strings.ToLower(name)]
+	return ok
+}
+
+func parseRange(raw string, f *field) error {
+	// Split on the first "-" after position 0 so negative minimums work.
+	i := strings.Index(raw[min(1, len(raw)):], "-")
+	if i < 0 {
+		return modules.UsageError("int range must look like MIN-MAX, got %q", raw)
+	}
+	i++
+	lo, errLo := strconv.ParseInt(raw[:i], 10, 64)
+	hi, errHi := strconv.ParseInt(raw[i+1:], 10, 64)
+	if errLo != nil || errHi != nil || lo > hi || hi-lo+1 <= 0 {
+		return modules.UsageError("int range must look like MIN-MAX, got %q", raw)
+	}
+	f.Min, f.Max = lo, hi
+	return nil
+}
+
+func presetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type person struct {
+	First, Last, Username, Email string
+}
+
+type location struct {
+	Street, City, State, Postcode, Country string
+}
+
+func (l location) String() string {
+	parts := []string{l.Street, l.City}
+	if l.State != "" {
+		parts = append(parts, l.State+" "+l.Postcode)
+	} else {
+		parts = append(parts, l.Postcode)
+	}
+	return strings.Join(append(parts, l.Country), ", ")
+}
+
+type company struct {
+	Name, Domain string
+}
+
+// generator keeps the state that must survive across records: the seeded
+// source and the usernames and emails already handed out.
+type generator struct {
+	rng   *rand.Rand
+	taken map[string]bool
+}
+
+func newGenerator(seed int64) *generator {
+	return &generator{rng: rand.New(rand.NewSource(seed)), taken: map[string]bool{}}
+}
+
+// record lazily creates one person, location and company per row so that
+// e.g. the email matches the name and the postcode matches the city.
+type record struct {
+	*generator
+	index    int
+	who      *person
+	where    *location
+	employer *company
+}
+
+func (g *generator) row(index int, fields []field) []any {
+	r := &record{generator: g, index: index}
+	values := make([]any, len(fields))
+	for i, f := range fields {
+		values[i] = fieldTypes[f.Type].Value(r, f)
+	}
+	return values
+}
+
+func (g *generator) unique(base string) string {
+	candidate := base
+	for n := 2; g.taken[candidate]; n++ {
+		candidate = base + strconv.Itoa(n)
+	}
+	g.taken[candidate] = true
+	return candidate
+}
+
+func (r *record) person() person {
+	if r.who == nil {
+		first, last := pick(r.rng, firstNames), pick(r.rng, lastNames)
+		base := strings.ToLower(first + "." + last)
+		r.who = &person{
+			First:    first,
+			Last:     last,
+			Username: r.unique(strings.ToLower(first + last[:1])),
+			Email:    r.unique(base) + "@" + pick(r.rng, emailDomains),
+		}
+	}
+	return *r.who
+}
+
+func (r *record) location() location {
+	if r.where == nil {
+		p := places[r.rng.Intn(len(places))]
+		r.where = &location{
+			Street:   fmt.Sprintf("%d %s %s", 1+r.rng.Intn(9899), pick(r.rng, streetNames), pick(r.rng, streetSuffixes)),
+			City:     p.City,
+			State:    p.State,
+			Postcode: r.postcode(p.Prefix),
+			Country:  p.Country,
+		}
+	}
+	return *r.where
+}
+
+// postcode pads numeric prefixes to five digits and gives alphanumeric
+// (UK and Canadian) prefixes a "9XX" style inward part.
+func (r *record) postcode(prefix string) string {
+	if _, err := strconv.Atoi(prefix); err == nil {
+		for len(prefix) < 5 {
+			prefix += strconv.Itoa(r.rng.Intn(10))
+		}
+		return prefix
+	}
+	const letters = "ABDEFGHJLNPQRSTUWXYZ"
+	return fmt.Sprintf("%s %d%c%c", prefix, r.rng.Intn(10), letters[r.rng.Intn(len(letters))], letters[r.rng.Intn(len(letters))])
+}
+
+func (r *record) company() company {
+	if r.employer == nil {
+		word := pick(r.rng, companyWords)
+		r.employer = &company{
+			Name:   word + " " + pick(r.rng, companySuffixes),
+			Domain: strings.ToLower(strings.ReplaceAll(word, " ", "")) + ".example",
+		}
+	}
+	return *r.employer
+}
+
+// phone uses the 555-0100 to 555-0199 range reserved for fiction.
+func (r *record) phone() string {
+	return fmt.Sprintf("+1-%d-555-01%02d", 201+r.rng.Intn(788), r.rng.Intn(100))
+}
+
+func (r *record) uuid() string {
+	var b [16]byte
+	r.rng.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+func (r *record) between(from, to time.Time) time.Time {
+	return from.Add(time.Duration(r.rng.Int63n(int64(to.Sub(from)/time.Second))) * time.Second)
+}
+
+func (r *record) sentence() string {
+	words := make([]string, 4+r.rng.Intn(8))
+	for i := range words {
+		words[i] = pick(r.rng, loremWords)
+	}
+	sentence := strings.Join(words, " ")
+	return strings.ToUpper(sentence[:1]) + sentence[1:] + "."
+}
+
+func pick(rng *rand.Rand, values []string) string {
+	return values[rng.Intn(len(values))]
+}
@@ -0,0 +1,151 @@
+package generate
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	crockford       = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	nanoidAlphabet  = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	defaultNanoSize = 21
+)
+
+func randomBytes(n int) ([]byte, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return buf, nil
+}
+
+// randomString picks each character uniformly from alphabet.
+func randomString(alphabet []rune, length int) (string, error) {
+	max := big.NewInt(int64(len(alphabet)))
+	out := make([]rune, length)
+	for i := range out {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to read random bytes: %w", err)
+		}
+		out[i] = alphabet[n.Int64()]
+	}
+	return string(out), nil
+}
+
+func formatUUID(b []byte) string {
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+func uuidV4() (string, error) {
+	b, err := randomBytes(16)
+	if err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return formatUUID(b), nil
+}
+
+// timeOrdered produces UUIDv7 values and ULIDs. Within one millisecond the
+// random part is incremented instead of redrawn, so bulk output sorts in
+// generation order.
+type timeOrdered struct {
+	lastMs uint64
+	random []byte
+}
+
+func (g *timeOrdered) next(size int) (uint64, []byte, error) {
+	ms := uint64(time.Now().UnixMilli())
+	if ms <= g.lastMs && g.random != nil {
+		ms = g.lastMs
+		if !increment(g.random) {
+			ms++
+			g.random = nil
+		}
+	}
+	if ms != g.lastMs || g.random == nil {
+		random, err := randomBytes(size)
+		if err != nil {
+			return 0, nil, err
+		}
+		// Leave headroom so increments rarely overflow.
+		random[0] &= 0x7f
+		g.random = random
+	}
+	g.lastMs = ms
+	return ms, append([]byte(nil), g.random...), nil
+}
+
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *timeOrdered) uuidV7() (string, error) {
+	// 74 random bits: 12 in rand_a and 62 in rand_b, spread over 10 bytes
+	// with the version and variant bits overwritten.
+	ms, random, err := g.next(10)
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, 16)
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], ms)
+	copy(b[0:6], ts[2:8])
+	copy(b[6:], random)
+	b[6] = b[6]&0x0f | 0x70
+	b[8] = b[8]&0x3f | 0x80
+	return formatUUID(b), nil
+}
+
+func (g *timeOrdered) ulid() (string, error) {
+	ms, random, err := g.next(10)
+	if err != nil {
+		return "", err
+	}
+	var b [16]byte
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], ms)
+	copy(b[0:6], ts[2:8])
+	copy(b[6:], random)
+	return encodeCrockford(b), nil
+}
+
+// encodeCrockford encodes 128 bits as 26 characters, 5 bits at a time from
+// the least significant end, as the ULID spec requires.
+func encodeCrockford(b [16]byte) string {
+	value := new(big.Int).SetBytes(b[:])
+	mask := big.NewInt(31)
+	out := make([]byte, 26)
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockford[new(big.Int).And(value, mask).Int64()]
+		value.Rsh(value, 5)
+	}
+	return string(out)
+}
+
+func nanoid(alphabet string, size int) (string, error) {
+	return randomString([]rune(alphabet), size)
+}
+
+func uniqueRunes(s string) bool {
+	seen := map[rune]bool{}
+	for _, r := range s {
+		if seen[r] {
+			return false
+		}
+		seen[r] = true
+	}
+	return true
+}
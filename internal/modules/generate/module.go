@@ -0,0 +1,256 @@
+package generate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/requirements"
+)
+
+const maxCount = 10000
+
+type Tool struct{}
+
+func New() modules.Tool {
+	return Tool{}
+}
+
+func (Tool) ID() string { return "generate" }
+
+func (Tool) Label() string { return "Generate" }
+
+func (Tool) Description() string { return "UUIDs, ULIDs, nanoids, passwords, API keys and secrets" }
+
+func (Tool) Requirements() []requirements.Check { return nil }
+
+const bulkUsage = "[--count 1] [--format text|json]"
+
+func (Tool) Actions() []modules.Action {
+	return []modules.Action{
+		{
+			ID:          "uuid",
+			Label:       "UUID",
+			Description: "Random (v4) or time-ordered (v7) UUIDs",
+			Usage:       "devtools run generate uuid [--version 4|7] [--upper true] " + bulkUsage,
+			Run:         generateUUID,
+		},
+		{
+			ID:          "ulid",
+			Label:       "ULID",
+			Description: "Lexicographically sortable ULIDs; bulk output stays in order",
+			Usage:       "devtools run generate ulid " + bulkUsage,
+			Run:         generateULID,
+		},
+		{
+			ID:          "nanoid",
+			Label:       "Nano ID",
+			Description: "URL-safe nanoids with an optional custom alphabet",
+			Usage:       "devtools run generate nanoid [--length 21] [--alphabet <chars>] " + bulkUsage,
+			Run:         generateNanoid,
+		},
+		{
+			ID:           "password",
+			Label:        "Password",
+			Description:  "Random password containing at least one character of each class",
+			Usage:        "devtools run generate password [--length 20] [--classes lower,upper,digits,symbols] [--exclude-ambiguous true] [--exclude <chars>] " + bulkUsage,
+			SecretOutput: true,
+			Run:          generatePassword,
+		},
+		{
+			ID:           "api-key",
+			Label:        "API key",
+			Description:  "Prefixed base62 API key with a CRC32 checksum suffix",
+			Usage:        "devtools run generate api-key [--prefix sk_test] [--length 32] " + bulkUsage,
+			SecretOutput: true,
+			Run:          generateAPIKey,
+		},
+		{
+			ID:          "check-api-key",
+			Label:       "Check API key",
+			Description: "Verify the checksum of a key created by api-key",
+			Usage:       "devtools run generate check-api-key <key>",
+			Sensitive:   true,
+			Run:         checkAPIKey,
+		},
+		{
+			ID:           "secret",
+			Label:        "Secret",
+			Description:  "N random bytes encoded as hex or base64",
+			Usage:        "devtools run generate secret [--bytes 32] [--encoding hex|base64|base64url] " + bulkUsage,
+			SecretOutput: true,
+			Run:          generateSecret,
+		},
+	}
+}
+
+func (Tool) Menu() *menu.Menu {
+	return menu.NewBuilder("Generate").
+		Action("UUID v4", "Random UUID", func() (string, error) {
+			return generateUUID(modules.ActionContext{})
+		}).
+		Action("UUID v7", "Time-ordered UUID", func() (string, error) {
+			return generateUUID(modules.ActionContext{Params: map[string]string{"version": "7"}})
+		}).
+		Action("ULID", "Sortable identifier", func() (string, error) {
+			return generateULID(modules.ActionContext{})
+		}).
+		Action("Nano ID", "21-character URL-safe ID", func() (string, error) {
+			return generateNanoid(modules.ActionContext{})
+		}).
+		Action("Password", "20 characters, all classes, no ambiguous characters", func() (string, error) {
+			return generatePassword(modules.ActionContext{Params: map[string]string{"exclude-ambiguous": "true"}})
+		}).
+		Action("API key", "Prompt for a prefix", apiKeyPrompt).
+		Action("Secret", "32 random bytes as hex", func() (string, error) {
+			return generateSecret(modules.ActionContext{})
+		}).
+		WithBack().
+		Build()
+}
+
+// generateMany runs next --count times and renders the values as lines or
+// as a JSON array.
+func generateMany(ctx modules.ActionContext, next func() (string, error)) (string, error) {
+	count, err := intParam(ctx, "count", 1, 1, maxCount)
+	if err != nil {
+		return "", err
+	}
+	format := cliutil.FirstNonEmpty(ctx.Params["format"], "text")
+	if format != "text" && format != "json" {
+		return "", modules.UsageError("--format must be text or json")
+	}
+
+	values := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		value, err := next()
+		if err != nil {
+			return "", err
+		}
+		values = append(values, value)
+	}
+	if format == "json" {
+		data, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+	return strings.Join(values, "\n"), nil
+}
+
+func intParam(ctx modules.ActionContext, name string, fallback, minimum, maximum int) (int, error) {
+	raw := ctx.Params[name]
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < minimum || value > maximum {
+		return 0, modules.UsageError("--%s must be a number between %d and %d", name, minimum, maximum)
+	}
+	return value, nil
+}
+
+func generateUUID(ctx modules.ActionContext) (string, error) {
+	version := cliutil.FirstNonEmpty(ctx.Params["version"], "4")
+	upper := ctx.Params["upper"] == "true"
+	var next func() (string, error)
+	switch version {
+	case "4":
+		next = uuidV4
+	case "7":
+		next = (&timeOrdered{}).uuidV7
+	default:
+		return "", modules.UsageError("--version must be 4 or 7")
+	}
+	return generateMany(ctx, func() (string, error) {
+		id, err := next()
+		if upper {
+			id = strings.ToUpper(id)
+		}
+		return id, err
+	})
+}
+
+func generateULID(ctx modules.ActionContext) (string, error) {
+	return generateMany(ctx, (&timeOrdered{}).ulid)
+}
+
+func generateNanoid(ctx modules.ActionContext) (string, error) {
+	size, err := intParam(ctx, "length", defaultNanoSize, 2, 256)
+	if err != nil {
+		return "", err
+	}
+	alphabet := cliutil.FirstNonEmpty(ctx.Params["alphabet"], nanoidAlphabet)
+	if len([]rune(alphabet)) < 2 || !uniqueRunes(alphabet) {
+		return "", modules.UsageError("--alphabet needs at least two distinct characters and no repeats")
+	}
+	return generateMany(ctx, func() (string, error) { return nanoid(alphabet, size) })
+}
+
+func generatePassword(ctx modules.ActionContext) (string, error) {
+	length, err := intParam(ctx, "length", defaultPasswordLength, 4, 1024)
+	if err != nil {
+		return "", err
+	}
+	classes, err := parseClasses(ctx.Params["classes"])
+	if err != nil {
+		return "", err
+	}
+	policy := passwordPolicy{Length: length, Classes: classes, Exclude: ctx.Params["exclude"]}
+	if ctx.Params["exclude-ambiguous"] == "true" {
+		policy.Exclude += ambiguousChars
+	}
+	return generateMany(ctx, func() (string, error) { return password(policy) })
+}
+
+func generateAPIKey(ctx modules.ActionContext) (string, error) {
+	prefix := ctx.Params["prefix"]
+	if prefix != "" && !prefixPattern.MatchString(prefix) {
+		return "", modules.UsageError("--prefix must start with a letter and contain only letters, digits and single underscores")
+	}
+	length, err := intParam(ctx, "length", defaultAPIKeyLength, 16, 256)
+	if err != nil {
+		return "", err
+	}
+	return generateMany(ctx, func() (string, error) { return apiKey(prefix, length) })
+}
+
+func checkAPIKey(ctx modules.ActionContext) (string, error) {
+	key := ctx.Params["key"]
+	if key == "" && len(ctx.Positionals) > 0 {
+		key = ctx.Positionals[0]
+	}
+	if key == "" {
+		return "", modules.UsageError("missing API key")
+	}
+	prefix, ok := verifyAPIKey(strings.TrimSpace(key))
+	if !ok {
+		return "", fmt.Errorf("checksum does not match; the key is mistyped, truncated or was not created by api-key")
+	}
+	if prefix == "" {
+		return "Checksum OK.", nil
+	}
+	return fmt.Sprintf("Checksum OK (prefix %s).", prefix), nil
+}
+
+func generateSecret(ctx modules.ActionContext) (string, error) {
+	size, err := intParam(ctx, "bytes", defaultSecretBytes, 1, 4096)
+	if err != nil {
+		return "", err
+	}
+	encoding := ctx.Params["encoding"]
+	return generateMany(ctx, func() (string, error) { return secret(size, encoding) })
+}
+
+func apiKeyPrompt() (string, error) {
+	prefix, err := cliutil.Prompt("Prefix (e.g. sk_test, empty for none): ")
+	if err != nil {
+		return "", err
+	}
+	return generateAPIKey(modules.ActionContext{Params: map[string]string{"prefix": prefix}})
+}
@@ -0,0 +1,78 @@
+package generate
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/modules"
+)
+
+func TestTimeOrderedValuesSortInGenerationOrder(t *testing.T) {
+	for _, action := range []func(modules.ActionContext) (string, error){generateULID, generateUUID} {
+		params := map[string]string{"count": "500", "version": "7"}
+		out, err := action(modules.ActionContext{Params: params})
+		if err != nil {
+			t.Fatalf("generate error = %v", err)
+		}
+		values := strings.Split(out, "\n")
+		if !sort.StringsAreSorted(values) {
+			t.Errorf("values are not in generation order: %v", values[:5])
+		}
+	}
+}
+
+func TestPasswordContainsEveryClass(t *testing.T) {
+	out, err := generatePassword(modules.ActionContext{Params: map[string]string{
+		"length": "4", "count": "200", "exclude-ambiguous": "true",
+	}})
+	if err != nil {
+		t.Fatalf("password error = %v", err)
+	}
+	for _, pw := range strings.Split(out, "\n") {
+		for _, set := range []string{lowerChars, upperChars, digitChars, symbolChars} {
+			if !strings.ContainsAny(pw, set) {
+				t.Errorf("password %q has no character from %q", pw, set)
+			}
+		}
+		if strings.ContainsAny(pw, ambiguousChars) {
+			t.Errorf("password %q contains an ambiguous character", pw)
+		}
+	}
+
+	_, err = generatePassword(modules.ActionContext{Params: map[string]string{"classes": "digits", "exclude": digitChars}})
+	if modules.KindOf(err) != modules.KindUsage {
+		t.Errorf("fully excluded class error = %v, want usage error", err)
+	}
+}
+
+func TestAPIKeyChecksum(t *testing.T) {
+	key, err := generateAPIKey(modules.ActionContext{Params: map[string]string{"prefix": "sk_test"}})
+	if err != nil {
+		t.Fatalf("api-key error = %v", err)
+	}
+	out, err := checkAPIKey(modules.ActionContext{Positionals: []string{key}})
+	if err != nil || out != "Checksum OK (prefix sk_test)." {
+		t.Errorf("check-api-key(%q) = %q, %v", key, out, err)
+	}
+
+	mistyped := key[:len(key)-1] + string(key[len(key)-2])
+	if key[len(key)-1] == key[len(key)-2] {
+		mistyped = key[:len(key)-1] + "!"
+	}
+	if _, err := checkAPIKey(modules.ActionContext{Positionals: []string{mistyped}}); err == nil {
+		t.Errorf("check-api-key(%q) accepted a mistyped key", mistyped)
+	}
+	if _, err := checkAPIKey(modules.ActionContext{Positionals: []string{key[:len(key)-3]}}); err == nil {
+		t.Error("check-api-key accepted a truncated key")
+	}
+}
+
+func TestSecretsAreKeptOutOfHistory(t *testing.T) {
+	secret := map[string]bool{"password": true, "api-key": true, "secret": true}
+	for _, action := range (Tool{}).Actions() {
+		if action.SecretOutput != secret[action.ID] {
+			t.Errorf("%s: SecretOutput = %v, want %v", action.ID, action.SecretOutput, secret[action.ID])
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package generate
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"go-devtools/internal/modules"
+)
+
+const (
+	lowerChars     = "abcdefghijklmnopqrstuvwxyz"
+	upperChars     = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars     = "0123456789"
+	symbolChars    = "!#$%&*+-=?@^_~"
+	ambiguousChars = "Il1O0o"
+	base62Chars    = digitChars + upperChars + lowerChars
+
+	defaultPasswordLength = 20
+	defaultAPIKeyLength   = 32
+	defaultSecretBytes    = 32
+	checksumLength        = 6
+)
+
+var prefixPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(_[A-Za-z0-9]+)*$`)
+
+type passwordPolicy struct {
+	Length  int
+	Classes []string
+	Exclude string
+}
+
+func parseClasses(raw string) ([]string, error) {
+	if raw == "" {
+		return []string{"lower", "upper", "digits", "symbols"}, nil
+	}
+	classes := make([]string, 0, 4)
+	for _, name := range strings.Split(raw, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "lower", "upper", "digits", "symbols":
+			classes = append(classes, name)
+		default:
+			return nil, modules.UsageError("unknown character class %q (use lower, upper, digits, symbols)", name)
+		}
+	}
+	return classes, nil
+}
+
+func classChars(name string) string {
+	switch name {
+	case "lower":
+		return lowerChars
+	case "upper":
+		return upperChars
+	case "digits":
+		return digitChars
+	default:
+		return symbolChars
+	}
+}
+
+func without(chars, exclude string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(exclude, r) {
+			return -1
+		}
+		return r
+	}, chars)
+}
+
+// password draws one character from every class first so each class is
+// guaranteed to appear, fills the rest from all classes and shuffles.
+func password(policy passwordPolicy) (string, error) {
+	if policy.Length < len(policy.Classes) {
+		return "", modules.UsageError("--length must be at least %d to include every class", len(policy.Classes))
+	}
+	var all strings.Builder
+	chars := make([]rune, 0, policy.Length)
+	for _, class := range policy.Classes {
+		set := without(classChars(class), policy.Exclude)
+		if set == "" {
+			return "", modules.UsageError("every %s character is excluded", class)
+		}
+		all.WriteString(set)
+		first, err := randomString([]rune(set), 1)
+		if err != nil {
+			return "", err
+		}
+		chars = append(chars, []rune(first)...)
+	}
+	rest, err := randomString([]rune(all.String()), policy.Length-len(chars))
+	if err != nil {
+		return "", err
+	}
+	chars = append(chars, []rune(rest)...)
+	for i := len(chars) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", fmt.Errorf("failed to read random bytes: %w", err)
+		}
+		chars[i], chars[j.Int64()] = chars[j.Int64()], chars[i]
+	}
+	return string(chars), nil
+}
+
+// apiKey follows the GitHub token layout: prefix, underscore, random base62
+// body and a 6-character base62 CRC32 of the body, so typos and truncated
+// copies can be detected offline.
+func apiKey(prefix string, length int) (string, error) {
+	body, err := randomString([]rune(base62Chars), length)
+	if err != nil {
+		return "", err
+	}
+	key := body + checksum(body)
+	if prefix != "" {
+		key = prefix + "_" + key
+	}
+	return key, nil
+}
+
+func checksum(body string) string {
+	sum := crc32.ChecksumIEEE([]byte(body))
+	out := make([]byte, checksumLength)
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = base62Chars[sum%62]
+		sum /= 62
+	}
+	return string(out)
+}
+
+func verifyAPIKey(key string) (string, bool) {
+	body := key
+	prefix := ""
+	if i := strings.LastIndex(key, "_"); i >= 0 {
+		prefix, body = key[:i], key[i+1:]
+	}
+	if len(body) <= checksumLength {
+		return prefix, false
+	}
+	random, sum := body[:len(body)-checksumLength], body[len(body)-checksumLength:]
+	return prefix, checksum(random) == sum
+}
+
+func secret(size int, encoding string) (string, error) {
+	b, err := randomBytes(size)
+	if err != nil {
+		return "", err
+	}
+	switch encoding {
+	case "", "hex":
+		return hex.EncodeToString(b), nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString(b), nil
+	case "base64url":
+		return base64.RawURLEncoding.EncodeToString(b), nil
+	default:
+		return "", modules.UsageError("--encoding must be hex, base64 or base64url")
+	}
+}
@@ -0,0 +1,166 @@
+package hash
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/requirements"
+)
+
+type Tool struct{}
+
+func New() modules.Tool {
+	return Tool{}
+}
+
+func (Tool) ID() string { return "hash" }
+
+func (Tool) Label() string { return "Hash" }
+
+func (Tool) Description() string { return "Digests, HMACs and password hashes" }
+
+func (Tool) Requirements() []requirements.Check { return nil }
+
+func (Tool) Actions() []modules.Action {
+	return []modules.Action{
+		{
+			ID:          "digest",
+			Label:       "Digest",
+			Description: "MD5, SHA-1, SHA-256, SHA-512 or BLAKE2 of a value, file or stdin",
+			Usage:       "devtools run hash digest [value | --file <path|-> | stdin] [--algo sha256|md5|sha1|sha512|blake2b|blake2s|all] [--encoding hex|base64|base64url]",
+			Run:         digest,
+		},
+		{
+			ID:          "hmac",
+			Label:       "HMAC",
+			Description: "HMAC of a value, file or stdin; --expect compares in constant time",
+			Usage:       "devtools run hash hmac [value | --file <path|-> | stdin] --key <secret> | --key-file <path> [--algo sha256] [--encoding hex|base64] [--expect <mac>]",
+			Run:         hmacDigest,
+		},
+		{
+			ID:          "password",
+			Label:       "Hash password",
+			Description: "Hash a password with bcrypt, scrypt or argon2id (PHC string format)",
+			Usage:       "devtools run hash password [password | stdin] [--scheme bcrypt|scrypt|argon2id] [--cost 12] [--ln 15 --r 8 --p 1] [--memory 19456 --time 2 --threads 1]",
+			Sensitive:   true,
+			Run:         passwordHash,
+		},
+		{
+			ID:          "verify",
+			Label:       "Verify password",
+			Description: "Check a password against a bcrypt, scrypt or argon2id hash",
+			Usage:       "devtools run hash verify [password | stdin] --hash <hash>",
+			Sensitive:   true,
+			Run:         passwordVerify,
+		},
+	}
+}
+
+func (Tool) Menu() *menu.Menu {
+	return menu.NewBuilder("Hash").
+		Action("Hash text", "All digests of a prompted value", func() (string, error) {
+			text, err := cliutil.Prompt("Text: ")
+			if err != nil {
+				return "", err
+			}
+			return digest(modules.ActionContext{Params: map[string]string{"input": text, "algo": "all"}})
+		}).
+		Action("Hash file", "All digests of a file", func() (string, error) {
+			path, err := cliutil.Prompt("File: ")
+			if err != nil {
+				return "", err
+			}
+			if path == "" {
+				return "", fmt.Errorf("file cannot be empty")
+			}
+			return digest(modules.ActionContext{Params: map[string]string{"file": path, "algo": "all"}})
+		}).
+		Action("Hash password", "bcrypt, scrypt or argon2id with default costs", passwordPrompt).
+		Action("Verify password", "Prompt for a password and a hash", verifyPrompt).
+		WithBack().
+		Build()
+}
+
+// readPassword takes the password from --password, the first positional or
+// the first line of piped stdin, which keeps it out of shell history.
+func readPassword(ctx modules.ActionContext) (string, error) {
+	if password := ctx.Params["password"]; password != "" {
+		return password, nil
+	}
+	if len(ctx.Positionals) > 0 {
+		return ctx.Positionals[0], nil
+	}
+	if stdinPiped() {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("failed to read stdin: %w", err)
+		}
+		if line = strings.TrimRight(line, "\r\n"); line != "" {
+			return line, nil
+		}
+	}
+	return "", modules.UsageError("missing password (pass it as an argument or on stdin)")
+}
+
+func passwordHash(ctx modules.ActionContext) (string, error) {
+	password, err := readPassword(ctx)
+	if err != nil {
+		return "", err
+	}
+	return hashPassword(strings.ToLower(ctx.Params["scheme"]), password, ctx.Params)
+}
+
+func passwordVerify(ctx modules.ActionContext) (string, error) {
+	encoded := ctx.Params["hash"]
+	if encoded == "" && len(ctx.Positionals) > 1 {
+		encoded = ctx.Positionals[1]
+	}
+	if encoded == "" {
+		return "", modules.UsageError("missing hash")
+	}
+	password, err := readPassword(ctx)
+	if err != nil {
+		return "", err
+	}
+	scheme, ok, err := verifyPassword(password, strings.TrimSpace(encoded))
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("password does not match the %s hash", scheme)
+	}
+	return fmt.Sprintf("Password matches (%s).", scheme), nil
+}
+
+func passwordPrompt() (string, error) {
+	scheme, err := cliutil.Prompt("Scheme (bcrypt/scrypt/argon2id) [bcrypt]: ")
+	if err != nil {
+		return "", err
+	}
+	password, err := cliutil.Prompt("Password (input is visible): ")
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", fmt.Errorf("password cannot be empty")
+	}
+	return hashPassword(strings.ToLower(scheme), password, nil)
+}
+
+func verifyPrompt() (string, error) {
+	password, err := cliutil.Prompt("Password (input is visible): ")
+	if err != nil {
+		return "", err
+	}
+	encoded, err := cliutil.Prompt("Hash: ")
+	if err != nil {
+		return "", err
+	}
+	return passwordVerify(modules.ActionContext{Params: map[string]string{"password": password, "hash": encoded}})
+}
@@ -0,0 +1,265 @@
+package hash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+
+	"go-devtools/internal/modules"
+)
+
+const (
+	saltBytes = 16
+	keyBytes  = 32
+
+	// OWASP password storage recommendations.
+	defaultBcryptCost     = 12
+	defaultScryptLogN     = 15
+	defaultScryptR        = 8
+	defaultScryptP        = 1
+	defaultArgon2Memory   = 19456
+	defaultArgon2Time     = 2
+	defaultArgon2Threads  = 1
+	maxArgon2MemoryKiB    = 4 * 1024 * 1024
+	maxArgon2TimeOrThread = 64
+	maxScryptLogN         = 24
+	maxScryptRP           = 1024
+	maxScryptMemory       = 1 << 30
+
+	// Stored hashes shorter than this are truncated or forged; a short key
+	// also makes a match meaningless.
+	minKeyBytes = 16
+)
+
+// Salts and keys in the PHC string format are unpadded standard base64.
+var phcEncoding = base64.RawStdEncoding
+
+type scryptParams struct {
+	LogN, R, P int
+}
+
+type argon2Params struct {
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+}
+
+func hashPassword(scheme, password string, params map[string]string) (string, error) {
+	switch scheme {
+	case "", "bcrypt":
+		cost, err := intParam(params, "cost", defaultBcryptCost, bcrypt.MinCost, bcrypt.MaxCost)
+		if err != nil {
+			return "", err
+		}
+		hashed, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+		if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+			return "", modules.UsageError("bcrypt only uses the first 72 bytes of a password; use argon2id for longer ones")
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to hash password: %w", err)
+		}
+		return string(hashed), nil
+	case "scrypt":
+		p, err := scryptParamsFrom(params)
+		if err != nil {
+			return "", err
+		}
+		salt, err := newSalt()
+		if err != nil {
+			return "", err
+		}
+		key, err := scrypt.Key([]byte(password), salt, 1<<p.LogN, p.R, p.P, keyBytes)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash password: %w", err)
+		}
+		return fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s", p.LogN, p.R, p.P, phcEncoding.EncodeToString(salt), phcEncoding.EncodeToString(key)), nil
+	case "argon2id", "argon2":
+		p, err := argon2ParamsFrom(params)
+		if err != nil {
+			return "", err
+		}
+		salt, err := newSalt()
+		if err != nil {
+			return "", err
+		}
+		key := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, keyBytes)
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, p.Memory, p.Time, p.Threads, phcEncoding.EncodeToString(salt), phcEncoding.EncodeToString(key)), nil
+	}
+	return "", modules.UsageError("unknown scheme %q (use bcrypt, scrypt or argon2id)", scheme)
+}
+
+func newSalt() ([]byte, error) {
+	salt := make([]byte, saltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return salt, nil
+}
+
+func scryptParamsFrom(params map[string]string) (scryptParams, error) {
+	logN, err := intParam(params, "ln", defaultScryptLogN, 10, 24)
+	if err != nil {
+		return scryptParams{}, err
+	}
+	r, err := intParam(params, "r", defaultScryptR, 1, 64)
+	if err != nil {
+		return scryptParams{}, err
+	}
+	p, err := intParam(params, "p", defaultScryptP, 1, 64)
+	if err != nil {
+		return scryptParams{}, err
+	}
+	parsed := scryptParams{LogN: logN, R: r, P: p}
+	if err := parsed.check(); err != nil {
+		return scryptParams{}, modules.UsageError("%v", err)
+	}
+	return parsed, nil
+}
+
+// check bounds the work a hash can demand: memory is 128·r·N bytes and CPU
+// time grows with N·r·p.
+func (p scryptParams) check() error {
+	if p.LogN < 1 || p.LogN > maxScryptLogN {
+		return fmt.Errorf("scrypt ln must be between 1 and %d", maxScryptLogN)
+	}
+	if p.R < 1 || p.P < 1 || p.R*p.P > maxScryptRP {
+		return fmt.Errorf("scrypt r and p must be at least 1 with r*p at most %d", maxScryptRP)
+	}
+	if 128*p.R<<p.LogN > maxScryptMemory {
+		return fmt.Errorf("scrypt parameters need more than %d MiB of memory", maxScryptMemory>>20)
+	}
+	return nil
+}
+
+func argon2ParamsFrom(params map[string]string) (argon2Params, error) {
+	memory, err := intParam(params, "memory", defaultArgon2Memory, 1024, maxArgon2MemoryKiB)
+	if err != nil {
+		return argon2Params{}, err
+	}
+	time, err := intParam(params, "time", defaultArgon2Time, 1, maxArgon2TimeOrThread)
+	if err != nil {
+		return argon2Params{}, err
+	}
+	threads, err := intParam(params, "threads", defaultArgon2Threads, 1, maxArgon2TimeOrThread)
+	if err != nil {
+		return argon2Params{}, err
+	}
+	return argon2Params{Memory: uint32(memory), Time: uint32(time), Threads: uint8(threads)}, nil
+}
+
+// verifyPassword detects the scheme from the hash prefix.
+func verifyPassword(password, encoded string) (string, bool, error) {
+	switch {
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return "bcrypt", false, nil
+		}
+		if err != nil {
+			return "bcrypt", false, fmt.Errorf("invalid bcrypt hash: %w", err)
+		}
+		return "bcrypt", true, nil
+	case strings.HasPrefix(encoded, "$scrypt$"):
+		ok, err := verifyScrypt(password, encoded)
+		return "scrypt", ok, err
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		ok, err := verifyArgon2(password, encoded)
+		return "argon2id", ok, err
+	}
+	return "", false, modules.UsageError("unrecognised hash format (expected $2a$/$2b$/$2y$, $scrypt$ or $argon2id$)")
+}
+
+// phcFields splits "$id$[v=..$]params$salt$hash" and parses the k=v params.
+func phcFields(encoded string, parts int) ([]string, map[string]int, error) {
+	fields := strings.Split(encoded, "$")
+	if len(fields) != parts {
+		return nil, nil, fmt.Errorf("malformed hash: expected %d $-separated fields", parts-1)
+	}
+	values := map[string]int{}
+	for _, pair := range strings.Split(fields[parts-3], ",") {
+		key, raw, ok := strings.Cut(pair, "=")
+		value, err := strconv.Atoi(raw)
+		if !ok || err != nil {
+			return nil, nil, fmt.Errorf("malformed hash parameter %q", pair)
+		}
+		values[key] = value
+	}
+	return fields, values, nil
+}
+
+func decodeSaltAndKey(fields []string) ([]byte, []byte, error) {
+	salt, err := phcEncoding.DecodeString(fields[len(fields)-2])
+	if err != nil {
+		return nil, nil, fmt.Errorf("malformed salt: %w", err)
+	}
+	key, err := phcEncoding.DecodeString(fields[len(fields)-1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("malformed hash value: %w", err)
+	}
+	if len(salt) == 0 {
+		return nil, nil, fmt.Errorf("malformed hash: empty salt")
+	}
+	if len(key) < minKeyBytes {
+		return nil, nil, fmt.Errorf("malformed hash: hash value is %d bytes, want at least %d", len(key), minKeyBytes)
+	}
+	return salt, key, nil
+}
+
+func verifyScrypt(password, encoded string) (bool, error) {
+	fields, params, err := phcFields(encoded, 5)
+	if err != nil {
+		return false, err
+	}
+	salt, want, err := decodeSaltAndKey(fields)
+	if err != nil {
+		return false, err
+	}
+	p := scryptParams{LogN: params["ln"], R: params["r"], P: params["p"]}
+	if err := p.check(); err != nil {
+		return false, fmt.Errorf("malformed hash: %w", err)
+	}
+	got, err := scrypt.Key([]byte(password), salt, 1<<p.LogN, p.R, p.P, len(want))
+	if err != nil {
+		return false, fmt.Errorf("invalid scrypt parameters: %w", err)
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
+
+func verifyArgon2(password, encoded string) (bool, error) {
+	fields, params, err := phcFields(encoded, 6)
+	if err != nil {
+		return false, err
+	}
+	if fields[2] != fmt.Sprintf("v=%d", argon2.Version) {
+		return false, fmt.Errorf("unsupported argon2 version %s", fields[2])
+	}
+	salt, want, err := decodeSaltAndKey(fields)
+	if err != nil {
+		return false, err
+	}
+	if params["m"] < 8 || params["m"] > maxArgon2MemoryKiB || params["t"] < 1 || params["t"] > maxArgon2TimeOrThread || params["p"] < 1 || params["p"] > 255 {
+		return false, fmt.Errorf("malformed hash: argon2 parameters out of range")
+	}
+	got := argon2.IDKey([]byte(password), salt, uint32(params["t"]), uint32(params["m"]), uint8(params["p"]), uint32(len(want)))
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
+
+func intParam(params map[string]string, name string, fallback, minimum, maximum int) (int, error) {
+	raw := params[name]
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < minimum || value > maximum {
+		return 0, modules.UsageError("--%s must be a number between %d and %d", name, minimum, maximum)
+	}
+	return value, nil
+}
@@ -0,0 +1,44 @@
+package hash
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashAndVerifyPassword(t *testing.T) {
+	fast := map[string]string{"cost": "4", "ln": "10", "memory": "1024", "time": "1"}
+	for _, scheme := range []string{"bcrypt", "scrypt", "argon2id"} {
+		encoded, err := hashPassword(scheme, "test-user-pw", fast)
+		if err != nil {
+			t.Fatalf("%s: hash error = %v", scheme, err)
+		}
+		if got, ok, err := verifyPassword("test-user-pw", encoded); err != nil || !ok || got != scheme {
+			t.Errorf("%s: verify = %q, %v, %v", scheme, got, ok, err)
+		}
+		if _, ok, err := verifyPassword("wrong", encoded); err != nil || ok {
+			t.Errorf("%s: wrong password = %v, %v", scheme, ok, err)
+		}
+	}
+}
+
+func TestVerifyRejectsHostileHashes(t *testing.T) {
+	// 16-byte salt and 32-byte key, both unpadded base64.
+	const salt = "c2FsdHNhbHRzYWx0c2FsdA"
+	const key = "a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U"
+	for _, tc := range []struct{ name, hash, want string }{
+		{"empty salt", "$scrypt$ln=10,r=8,p=1$$" + key, "empty salt"},
+		{"short key", "$scrypt$ln=10,r=8,p=1$" + salt + "$a2V5", "want at least 16"},
+		{"scrypt ln", "$scrypt$ln=30,r=8,p=1$" + salt + "$" + key, "ln must be between"},
+		{"scrypt r*p", "$scrypt$ln=10,r=1024,p=1024$" + salt + "$" + key, "r*p at most"},
+		{"scrypt memory", "$scrypt$ln=24,r=64,p=1$" + salt + "$" + key, "memory"},
+		{"argon2 time", "$argon2id$v=19$m=1024,t=100000,p=1$" + salt + "$" + key, "out of range"},
+		{"argon2 short key", "$argon2id$v=19$m=1024,t=1,p=1$" + salt + "$", "want at least 16"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := verifyPassword("pw", tc.hash)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("error = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
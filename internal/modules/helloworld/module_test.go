@@ -0,0 +1,50 @@
+package helloworld
+
+import (
+	"testing"
+	"time"
+
+	"go-devtools/internal/devtoolstest"
+	"go-devtools/internal/modules"
+)
+
+func TestActions(t *testing.T) {
+	out, err := devtoolstest.RunAction(t, New(), "greet", nil)
+	if err != nil || out != "Hello from the Hello Tool module." {
+		t.Errorf("greet = %q, %v", out, err)
+	}
+
+	out, err = devtoolstest.RunAction(t, New(), "timestamp", nil)
+	if err != nil {
+		t.Fatalf("timestamp error = %v", err)
+	}
+	if _, err := time.Parse(time.RFC3339, out); err != nil {
+		t.Errorf("timestamp = %q, not RFC3339: %v", out, err)
+	}
+}
+
+func TestMenu(t *testing.T) {
+	devtoolstest.Isolate(t)
+	term, err := devtoolstest.RunTool(t, New(), devtoolstest.KeyEnter, devtoolstest.KeyEnter, devtoolstest.KeyQuit)
+	if err != nil {
+		t.Fatalf("RunTool() error = %v", err)
+	}
+	devtoolstest.AssertGolden(t, "greeting", term.LastFrame())
+}
+
+func TestMenuUtilities(t *testing.T) {
+	devtoolstest.Isolate(t)
+	term, err := devtoolstest.RunTool(t, New(),
+		devtoolstest.KeyEnter, devtoolstest.KeyDown, devtoolstest.KeyEnter, devtoolstest.KeyEnter, devtoolstest.KeyQuit)
+	if err != nil {
+		t.Fatalf("RunTool() error = %v", err)
+	}
+	devtoolstest.AssertFrameContains(t, term.LastFrame(), "Menu: Hello Tool / Utilities", "Depth: 3/")
+}
+
+func TestCLI(t *testing.T) {
+	result := devtoolstest.RunCLI(t, []modules.Tool{New()}, "run", "hello-tool", "greet")
+	if result.ExitCode != 0 || result.Stdout != "Hello from the Hello Tool module.\n" {
+		t.Errorf("RunCLI() = %+v", result)
+	}
+}
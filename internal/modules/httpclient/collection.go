@@ -0,0 +1,135 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go-devtools/internal/appdir"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/safefile"
+)
+
+const (
+	envCollection      = "DEVTOOLS_HTTP_COLLECTION"
+	projectCollection  = "devtools-requests.json"
+	fallbackCollection = "http-requests.json"
+)
+
+// secretHeaders are replaced by placeholders when a request is saved, like the
+// auth flags, so tokens never end up in a shared collection file.
+var secretHeaders = map[string]string{
+	"authorization": "authorization",
+	"cookie":        "cookie",
+	"x-api-key":     "apiKey",
+}
+
+type collection struct {
+	Environments map[string]map[string]string `json:"environments,omitempty"`
+	Requests     map[string]requestSpec       `json:"requests"`
+}
+
+func collectionPath(ctx modules.ActionContext) (string, error) {
+	if path := ctx.Params["collection"]; path != "" {
+		return path, nil
+	}
+	if path := os.Getenv(envCollection); path != "" {
+		return path, nil
+	}
+	if _, err := os.Stat(projectCollection); err == nil {
+		return projectCollection, nil
+	}
+	return appdir.Path(fallbackCollection)
+}
+
+func loadCollection(path string) (collection, error) {
+	coll := collection{Requests: map[string]requestSpec{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return coll, nil
+		}
+		return coll, fmt.Errorf("failed to read collection: %w", err)
+	}
+	if err := json.Unmarshal(data, &coll); err != nil {
+		return coll, fmt.Errorf("failed to parse collection %s: %w", path, err)
+	}
+	if coll.Requests == nil {
+		coll.Requests = map[string]requestSpec{}
+	}
+	return coll, nil
+}
+
+func saveCollection(path string, coll collection) error {
+	data, err := json.MarshalIndent(coll, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode collection: %w", err)
+	}
+	if err := safefile.Write(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write collection: %w", err)
+	}
+	return nil
+}
+
+func (c collection) variables(env string, overrides map[string]string) (map[string]string, error) {
+	vars := map[string]string{}
+	if env != "" {
+		values, ok := c.Environments[env]
+		if !ok {
+			return nil, modules.NotFoundError("unknown environment %q (available: %v)", env, sortedNames(c.Environments))
+		}
+		for key, value := range values {
+			vars[key] = value
+		}
+	}
+	for key, value := range overrides {
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+func sortedNames[T any](values map[string]T) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// withPlaceholders replaces literal credentials in spec with {{name}}
+// placeholders and returns the names it introduced. Values that already
+// reference a variable are kept as they are.
+func withPlaceholders(spec requestSpec) (requestSpec, []string) {
+	var names []string
+	placeholder := func(value, name string) string {
+		if value == "" || variablePattern.MatchString(value) {
+			return value
+		}
+		names = append(names, name)
+		return "{{" + name + "}}"
+	}
+
+	if spec.Auth != nil {
+		auth := *spec.Auth
+		auth.Bearer = placeholder(auth.Bearer, "token")
+		if user, pass, ok := strings.Cut(auth.Basic, ":"); ok {
+			auth.Basic = user + ":" + placeholder(pass, "password")
+		}
+		spec.Auth = &auth
+	}
+	if len(spec.Headers) > 0 {
+		headers := make(map[string]string, len(spec.Headers))
+		for key, value := range spec.Headers {
+			if name, ok := secretHeaders[strings.ToLower(key)]; ok {
+				value = placeholder(value, name)
+			}
+			headers[key] = value
+		}
+		spec.Headers = headers
+	}
+	sort.Strings(names)
+	return spec, names
+}
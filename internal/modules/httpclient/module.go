@@ -0,0 +1,263 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/requirements"
+)
+
+const defaultTimeout = 30 * time.Second
+
+type Tool struct{}
+
+func New() modules.Tool {
+	return Tool{}
+}
+
+func (Tool) ID() string { return "http-client" }
+
+func (Tool) Label() string { return "HTTP Client" }
+
+func (Tool) Description() string { return "Send HTTP requests and manage saved request collections" }
+
+func (Tool) Requirements() []requirements.Check { return nil }
+
+func (Tool) Actions() []modules.Action {
+	return []modules.Action{
+		{
+			ID:          "request",
+			Label:       "Send request",
+			Description: "Send an HTTP request and pretty-print the response",
+			Usage:       "devtools run http-client request <url> [--method POST] [--header.<Name> v] [--query.<name> v] [--json '{...}'|@file] [--form.<name> v] [--body text|@file] [--basic-auth user:pass|--bearer-token t] [--timeout 30s] [--verbose true] [--raw true]",
+			Run:         runRequest,
+		},
+		{
+			ID:          "send",
+			Label:       "Send saved request",
+			Description: "Send a named request from the collection file",
+			Usage:       "devtools run http-client send <name> [--env <environment>] [--var.<name> v] [--collection <file>] [request flags to override]",
+			Run:         runSaved,
+		},
+		{
+			ID:          "list",
+			Label:       "List saved requests",
+			Description: "List requests and environments in the collection file",
+			Usage:       "devtools run http-client list [--collection <file>]",
+			Run:         runList,
+		},
+		{
+			ID:          "save",
+			Label:       "Save request",
+			Description: "Save a request definition into the collection file",
+			Usage:       "devtools run http-client save <name> <url> [request flags] [--collection <file>]",
+			Run:         runSave,
+		},
+	}
+}
+
+func (Tool) Menu() *menu.Menu {
+	saved := menu.NewBuilder("HTTP Client / Saved requests").
+		Action("List saved requests", "Show collection contents", func() (string, error) {
+			return runList(modules.ActionContext{})
+		}).
+		Action("Send saved request", "Prompt for request name and environment", sendSavedPrompt).
+		WithBack().
+		Build()
+
+	return menu.NewBuilder("HTTP Client").
+		Action("Send request", "Prompt for method and URL", sendRequestPrompt).
+		SubMenu("Saved requests", "Collection of named requests", saved).
+		WithBack().
+		Build()
+}
+
+func runRequest(ctx modules.ActionContext) (string, error) {
+	spec, err := specFromParams(requestSpec{}, ctx, 0)
+	if err != nil {
+		return "", err
+	}
+	spec, err = spec.expand(variableOverrides(ctx))
+	if err != nil {
+		return "", err
+	}
+	return execute(spec, ctx)
+}
+
+func runSaved(ctx modules.ActionContext) (string, error) {
+	name := firstPositional(ctx, "name")
+	if name == "" {
+		return "", modules.UsageError("missing request name (use --name or first positional argument)")
+	}
+
+	path, err := collectionPath(ctx)
+	if err != nil {
+		return "", err
+	}
+	coll, err := loadCollection(path)
+	if err != nil {
+		return "", err
+	}
+	spec, ok := coll.Requests[name]
+	if !ok {
+		return "", modules.NotFoundError("request %q not found in %s", name, path)
+	}
+
+	spec, err = specFromParams(spec, ctx, 1)
+	if err != nil {
+		return "", err
+	}
+	vars, err := coll.variables(ctx.Params["env"], variableOverrides(ctx))
+	if err != nil {
+		return "", err
+	}
+	spec, err = spec.expand(vars)
+	if err != nil {
+		return "", err
+	}
+	return execute(spec, ctx)
+}
+
+func runList(ctx modules.ActionContext) (string, error) {
+	path, err := collectionPath(ctx)
+	if err != nil {
+		return "", err
+	}
+	coll, err := loadCollection(path)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Collection: %s\n", path)
+	if len(coll.Requests) == 0 {
+		b.WriteString("\nNo saved requests. Add one with: devtools run http-client save <name> <url>")
+		return b.String(), nil
+	}
+	b.WriteString("\nRequests:\n")
+	for _, name := range sortedNames(coll.Requests) {
+		spec := coll.Requests[name]
+		method := strings.ToUpper(spec.Method)
+		if method == "" {
+			method = http.MethodGet
+		}
+		fmt.Fprintf(&b, "  %-20s %-6s %s\n", name, method, spec.URL)
+	}
+	if len(coll.Environments) > 0 {
+		fmt.Fprintf(&b, "\nEnvironments: %s\n", strings.Join(sortedNames(coll.Environments), ", "))
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func runSave(ctx modules.ActionContext) (string, error) {
+	name := firstPositional(ctx, "name")
+	if name == "" {
+		return "", modules.UsageError("missing request name (use --name or first positional argument)")
+	}
+	spec, err := specFromParams(requestSpec{}, ctx, 1)
+	if err != nil {
+		return "", err
+	}
+	if spec.URL == "" {
+		return "", modules.UsageError("missing url (use --url or second positional argument)")
+	}
+	if len(spec.JSON) > 0 && !json.Valid(spec.JSON) {
+		return "", modules.UsageError(`--json must be valid JSON to be saved; quote placeholders, e.g. "{{id}}"`)
+	}
+	spec, placeholders := withPlaceholders(spec)
+
+	path, err := collectionPath(ctx)
+	if err != nil {
+		return "", err
+	}
+	coll, err := loadCollection(path)
+	if err != nil {
+		return "", err
+	}
+	_, replaced := coll.Requests[name]
+	coll.Requests[name] = spec
+	if err := saveCollection(path, coll); err != nil {
+		return "", err
+	}
+
+	verb := "Saved"
+	if replaced {
+		verb = "Updated"
+	}
+	out := fmt.Sprintf("%s request %q in %s.", verb, name, path)
+	if len(placeholders) > 0 {
+		out += fmt.Sprintf("\nCredentials were replaced by placeholders: {{%s}}. Provide them with --var.<name>, an --env block or environment variables.", strings.Join(placeholders, "}}, {{"))
+	}
+	return out, nil
+}
+
+func execute(spec requestSpec, ctx modules.ActionContext) (string, error) {
+	req, err := spec.build()
+	if err != nil {
+		return "", err
+	}
+
+	timeout := defaultTimeout
+	if raw := ctx.Params["timeout"]; raw != "" {
+		timeout, err = time.ParseDuration(raw)
+		if err != nil {
+			return "", modules.UsageError("invalid timeout %q", raw)
+		}
+	}
+
+	resp, err := send(&http.Client{Timeout: timeout}, req)
+	if err != nil {
+		return "", err
+	}
+	return formatResponse(req, resp, ctx.Params["verbose"] == "true", ctx.Params["raw"] == "true"), nil
+}
+
+func variableOverrides(ctx modules.ActionContext) map[string]string {
+	vars := map[string]string{}
+	for key, value := range ctx.Params {
+		if strings.HasPrefix(key, "var.") {
+			vars[strings.TrimPrefix(key, "var.")] = value
+		}
+	}
+	return vars
+}
+
+func firstPositional(ctx modules.ActionContext, key string) string {
+	if value := ctx.Params[key]; value != "" {
+		return value
+	}
+	if len(ctx.Positionals) > 0 {
+		return ctx.Positionals[0]
+	}
+	return ""
+}
+
+func sendRequestPrompt() (string, error) {
+	method, err := cliutil.Prompt("Method [GET]: ")
+	if err != nil {
+		return "", err
+	}
+	target, err := cliutil.Prompt("URL: ")
+	if err != nil {
+		return "", err
+	}
+	return runRequest(modules.ActionContext{Params: map[string]string{"method": method, "url": target}})
+}
+
+func sendSavedPrompt() (string, error) {
+	name, err := cliutil.Prompt("Request name: ")
+	if err != nil {
+		return "", err
+	}
+	env, err := cliutil.Prompt("Environment (optional): ")
+	if err != nil {
+		return "", err
+	}
+	return runSaved(modules.ActionContext{Params: map[string]string{"name": name, "env": env}})
+}
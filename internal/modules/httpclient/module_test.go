@@ -0,0 +1,110 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/devtoolstest"
+	"go-devtools/internal/modules"
+)
+
+func TestSaveReplacesCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requests.json")
+	out, err := devtoolstest.RunAction(t, New(), "save", map[string]string{
+		"collection":           path,
+		"bearer-token":         "s3cret",
+		"header.Cookie":        "session=abc",
+		"header.Accept":        "application/json",
+		"header.Authorization": "Bearer {{token}}",
+	}, "get-user", "https://api.example.test/users")
+	if err != nil {
+		t.Fatalf("save error = %v", err)
+	}
+	if !strings.Contains(out, "{{cookie}}, {{token}}") {
+		t.Errorf("save output does not list placeholders:\n%s", out)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cret") || strings.Contains(string(data), "session=abc") {
+		t.Fatalf("collection contains plaintext credentials:\n%s", data)
+	}
+	coll, err := loadCollection(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := coll.Requests["get-user"]
+	if spec.Auth.Bearer != "{{token}}" || spec.Headers["Cookie"] != "{{cookie}}" || spec.Headers["Authorization"] != "Bearer {{token}}" || spec.Headers["Accept"] != "application/json" {
+		t.Errorf("saved spec = %+v", spec)
+	}
+}
+
+func TestWithPlaceholdersBasicAuth(t *testing.T) {
+	spec, names := withPlaceholders(requestSpec{Auth: &authSpec{Basic: "alice:hunter2"}})
+	if spec.Auth.Basic != "alice:{{password}}" || len(names) != 1 {
+		t.Errorf("withPlaceholders() = %+v, %v", spec.Auth, names)
+	}
+}
+
+func TestJSONValidatedAfterExpansion(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+	}))
+	defer server.Close()
+
+	params := map[string]string{"json": `{"id": {{id}}}`, "var.id": "42"}
+	if _, err := devtoolstest.RunAction(t, New(), "request", params, server.URL); err != nil {
+		t.Fatalf("request error = %v", err)
+	}
+	if !json.Valid([]byte(received)) || !strings.Contains(received, "42") {
+		t.Errorf("server received %q", received)
+	}
+
+	params["var.id"] = "not json"
+	if _, err := devtoolstest.RunAction(t, New(), "request", params, server.URL); modules.KindOf(err) != modules.KindUsage {
+		t.Errorf("invalid expanded JSON error = %v, want usage error", err)
+	}
+	if _, err := devtoolstest.RunAction(t, New(), "request", map[string]string{"json": "{"}, server.URL); modules.KindOf(err) != modules.KindUsage {
+		t.Errorf("invalid JSON error = %v, want usage error", err)
+	}
+}
+
+func TestResponseTruncation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("a", maxBodyBytes+10)))
+	}))
+	defer server.Close()
+
+	out, err := devtoolstest.RunAction(t, New(), "request", nil, server.URL)
+	if err != nil {
+		t.Fatalf("request error = %v", err)
+	}
+	if !strings.Contains(out, "truncated to the first 1048576 bytes") || !strings.Contains(out, "was truncated") {
+		t.Errorf("truncation not reported:\n%s", out[:200])
+	}
+}
+
+func TestCLIRequestURLWithQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "a="+r.URL.Query().Get("a"))
+	}))
+	defer server.Close()
+
+	result := devtoolstest.RunCLI(t, []modules.Tool{New()}, "http-client", "request", server.URL+"/x?a=b")
+	if result.Err != nil {
+		t.Fatalf("request error = %v\n%s", result.Err, result.Stderr)
+	}
+	if !strings.HasSuffix(result.Stdout, "\n\na=b\n") {
+		t.Errorf("stdout = %q, want the query echoed back", result.Stdout)
+	}
+}
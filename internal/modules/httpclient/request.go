@@ -0,0 +1,323 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go-devtools/internal/modules"
+)
+
+const maxBodyBytes = 1 << 20
+
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+type authSpec struct {
+	Basic  string `json:"basic,omitempty"`
+	Bearer string `json:"bearer,omitempty"`
+}
+
+type requestSpec struct {
+	Method  string            `json:"method,omitempty"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Query   map[string]string `json:"query,omitempty"`
+	JSON    json.RawMessage   `json:"json,omitempty"`
+	Form    map[string]string `json:"form,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	Auth    *authSpec         `json:"auth,omitempty"`
+}
+
+type response struct {
+	Status    string
+	Proto     string
+	Header    http.Header
+	Body      []byte
+	Truncated bool
+	Duration  time.Duration
+}
+
+func specFromParams(spec requestSpec, ctx modules.ActionContext, urlPosition int) (requestSpec, error) {
+	if method := ctx.Params["method"]; method != "" {
+		spec.Method = method
+	}
+	if target := ctx.Params["url"]; target != "" {
+		spec.URL = target
+	} else if urlPosition >= 0 && urlPosition < len(ctx.Positionals) {
+		spec.URL = ctx.Positionals[urlPosition]
+	}
+
+	for key, value := range ctx.Params {
+		switch {
+		case strings.HasPrefix(key, "header."):
+			spec.Headers = setValue(spec.Headers, strings.TrimPrefix(key, "header."), value)
+		case strings.HasPrefix(key, "query."):
+			spec.Query = setValue(spec.Query, strings.TrimPrefix(key, "query."), value)
+		case strings.HasPrefix(key, "form."):
+			spec.Form = setValue(spec.Form, strings.TrimPrefix(key, "form."), value)
+		}
+	}
+
+	if raw, ok := ctx.Params["json"]; ok {
+		data, err := readValue(raw)
+		if err != nil {
+			return spec, err
+		}
+		// Placeholders are checked again after expansion in build.
+		if !variablePattern.MatchString(data) && !json.Valid([]byte(data)) {
+			return spec, modules.UsageError("--json is not valid JSON")
+		}
+		spec.JSON = json.RawMessage(data)
+	}
+	if raw, ok := ctx.Params["body"]; ok {
+		data, err := readValue(raw)
+		if err != nil {
+			return spec, err
+		}
+		spec.Body = data
+	}
+	if basic := ctx.Params["basic-auth"]; basic != "" {
+		spec.Auth = &authSpec{Basic: basic}
+	}
+	if bearer := ctx.Params["bearer-token"]; bearer != "" {
+		spec.Auth = &authSpec{Bearer: bearer}
+	}
+	return spec, nil
+}
+
+func setValue(values map[string]string, key, value string) map[string]string {
+	if values == nil {
+		values = map[string]string{}
+	}
+	values[key] = value
+	return values
+}
+
+func readValue(raw string) (string, error) {
+	if !strings.HasPrefix(raw, "@") {
+		return raw, nil
+	}
+	path := strings.TrimPrefix(raw, "@")
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return string(data), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
+func (s requestSpec) expand(vars map[string]string) (requestSpec, error) {
+	missing := map[string]bool{}
+	sub := func(text string) string {
+		return variablePattern.ReplaceAllStringFunc(text, func(match string) string {
+			name := variablePattern.FindStringSubmatch(match)[1]
+			if value, ok := vars[name]; ok {
+				return value
+			}
+			if value, ok := os.LookupEnv(strings.TrimPrefix(name, "env.")); ok {
+				return value
+			}
+			missing[name] = true
+			return match
+		})
+	}
+	subMap := func(values map[string]string) map[string]string {
+		if values == nil {
+			return nil
+		}
+		out := make(map[string]string, len(values))
+		for key, value := range values {
+			out[sub(key)] = sub(value)
+		}
+		return out
+	}
+
+	out := requestSpec{
+		Method:  sub(s.Method),
+		URL:     sub(s.URL),
+		Headers: subMap(s.Headers),
+		Query:   subMap(s.Query),
+		Form:    subMap(s.Form),
+		Body:    sub(s.Body),
+	}
+	if len(s.JSON) > 0 {
+		out.JSON = json.RawMessage(sub(string(s.JSON)))
+	}
+	if s.Auth != nil {
+		out.Auth = &authSpec{Basic: sub(s.Auth.Basic), Bearer: sub(s.Auth.Bearer)}
+	}
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return out, modules.UsageError("undefined variables: %s (use --env, --var.<name> or environment variables)", strings.Join(names, ", "))
+	}
+	return out, nil
+}
+
+func (s requestSpec) build() (*http.Request, error) {
+	if s.URL == "" {
+		return nil, modules.UsageError("missing url (use --url or first positional argument)")
+	}
+	target, err := url.Parse(s.URL)
+	if err != nil || target.Host == "" {
+		return nil, modules.UsageError("invalid url %q", s.URL)
+	}
+	if len(s.Query) > 0 {
+		query := target.Query()
+		for key, value := range s.Query {
+			query.Set(key, value)
+		}
+		target.RawQuery = query.Encode()
+	}
+
+	if len(s.JSON) > 0 && !json.Valid(s.JSON) {
+		return nil, modules.UsageError("json body is not valid JSON after variable expansion")
+	}
+
+	method := strings.ToUpper(s.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	contentType := ""
+	switch {
+	case len(s.JSON) > 0:
+		body = bytes.NewReader(s.JSON)
+		contentType = "application/json"
+	case len(s.Form) > 0:
+		form := url.Values{}
+		for key, value := range s.Form {
+			form.Set(key, value)
+		}
+		body = strings.NewReader(form.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	case s.Body != "":
+		body = strings.NewReader(s.Body)
+	}
+
+	req, err := http.NewRequest(method, target.String(), body)
+	if err != nil {
+		return nil, modules.UsageError("invalid request: %v", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("User-Agent", "go-devtools/http-client")
+	for key, value := range s.Headers {
+		req.Header.Set(key, value)
+	}
+	if s.Auth != nil {
+		switch {
+		case s.Auth.Bearer != "":
+			req.Header.Set("Authorization", "Bearer "+s.Auth.Bearer)
+		case s.Auth.Basic != "":
+			user, pass, _ := strings.Cut(s.Auth.Basic, ":")
+			req.SetBasicAuth(user, pass)
+		}
+	}
+	return req, nil
+}
+
+func send(client *http.Client, req *http.Request) (response, error) {
+	started := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return response{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes+1))
+	if err != nil {
+		return response{}, fmt.Errorf("failed to read response: %w", err)
+	}
+	truncated := len(body) > maxBodyBytes
+	if truncated {
+		body = body[:maxBodyBytes]
+	}
+	return response{
+		Status:    resp.Status,
+		Proto:     resp.Proto,
+		Header:    resp.Header,
+		Body:      body,
+		Truncated: truncated,
+		Duration:  time.Since(started),
+	}, nil
+}
+
+func formatResponse(req *http.Request, resp response, verbose, raw bool) string {
+	var b strings.Builder
+	if verbose {
+		fmt.Fprintf(&b, "> %s %s\n", req.Method, req.URL.String())
+		for _, key := range sortedHeaderKeys(req.Header) {
+			value := strings.Join(req.Header[key], ", ")
+			if key == "Authorization" {
+				value = "[redacted]"
+			}
+			fmt.Fprintf(&b, "> %s: %s\n", key, value)
+		}
+		b.WriteString("\n")
+	}
+
+	size := fmt.Sprintf("%d bytes", len(resp.Body))
+	if resp.Truncated {
+		size = fmt.Sprintf("truncated to the first %d bytes", maxBodyBytes)
+	}
+	fmt.Fprintf(&b, "%s %s  (%s, %s)\n", resp.Proto, resp.Status, resp.Duration.Round(time.Millisecond), size)
+	if verbose {
+		for _, key := range sortedHeaderKeys(resp.Header) {
+			fmt.Fprintf(&b, "< %s: %s\n", key, strings.Join(resp.Header[key], ", "))
+		}
+	}
+
+	if len(resp.Body) > 0 {
+		b.WriteString("\n")
+		b.WriteString(formatBody(resp.Body, resp.Header.Get("Content-Type"), raw || resp.Truncated))
+	}
+	if resp.Truncated {
+		fmt.Fprintf(&b, "\n\n(response body exceeded %d bytes and was truncated)", maxBodyBytes)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func formatBody(body []byte, contentType string, raw bool) string {
+	if raw {
+		return string(body)
+	}
+	trimmed := bytes.TrimSpace(body)
+	looksJSON := strings.Contains(contentType, "json") || (len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '['))
+	if looksJSON {
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, trimmed, "", "  "); err == nil {
+			return pretty.String()
+		}
+	}
+	return string(body)
+}
+
+func sortedHeaderKeys(header http.Header) []string {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,344 @@
+package kube
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"go-devtools/internal/modules"
+	"go-devtools/internal/safefile"
+)
+
+const (
+	envKubeconfig  = "KUBECONFIG"
+	envProdPattern = "DEVTOOLS_KUBE_PROD_PATTERN"
+)
+
+var defaultProdPattern = regexp.MustCompile(`(?i)(^|[^a-z])(prod|production|prd|live)([^a-z]|$)`)
+
+type kubeconfigFile struct {
+	CurrentContext string         `yaml:"current-context"`
+	Clusters       []namedCluster `yaml:"clusters"`
+	Contexts       []namedContext `yaml:"contexts"`
+	Users          []namedUser    `yaml:"users"`
+}
+
+type namedCluster struct {
+	Name    string `yaml:"name"`
+	Cluster struct {
+		Server                string `yaml:"server"`
+		InsecureSkipTLSVerify bool   `yaml:"insecure-skip-tls-verify"`
+	} `yaml:"cluster"`
+}
+
+type namedContext struct {
+	Name    string `yaml:"name"`
+	Context struct {
+		Cluster   string `yaml:"cluster"`
+		User      string `yaml:"user"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"context"`
+}
+
+type namedUser struct {
+	Name string `yaml:"name"`
+}
+
+type loadedFile struct {
+	Path   string
+	Config kubeconfigFile
+	doc    yaml.Node
+}
+
+type kubeCluster struct {
+	Name     string
+	Server   string
+	Insecure bool
+	File     string
+}
+
+type kubeContext struct {
+	Name      string
+	Cluster   string
+	User      string
+	Namespace string
+	File      string
+}
+
+// kubeconfig is the merged view of every file on the KUBECONFIG path. As with
+// kubectl, the first file to define a name wins and the first non-empty
+// current-context is the active one.
+type kubeconfig struct {
+	Files              []*loadedFile
+	Clusters           []kubeCluster
+	Contexts           []kubeContext
+	Users              []string
+	CurrentContext     string
+	CurrentContextFile string
+	prod               *regexp.Regexp
+}
+
+func kubeconfigPaths(ctx modules.ActionContext) ([]string, error) {
+	if path := ctx.Params["kubeconfig"]; path != "" {
+		return []string{path}, nil
+	}
+	if env := os.Getenv(envKubeconfig); env != "" {
+		seen := map[string]bool{}
+		paths := make([]string, 0)
+		for _, path := range filepath.SplitList(env) {
+			if path == "" || seen[path] {
+				continue
+			}
+			seen[path] = true
+			paths = append(paths, path)
+		}
+		if len(paths) > 0 {
+			return paths, nil
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return []string{filepath.Join(home, ".kube", "config")}, nil
+}
+
+func prodPattern() (*regexp.Regexp, error) {
+	raw := os.Getenv(envProdPattern)
+	if raw == "" {
+		return defaultProdPattern, nil
+	}
+	pattern, err := regexp.Compile(raw)
+	if err != nil {
+		return nil, modules.UsageError("invalid %s %q: %v", envProdPattern, raw, err)
+	}
+	return pattern, nil
+}
+
+func loadKubeconfig(ctx modules.ActionContext) (*kubeconfig, error) {
+	paths, err := kubeconfigPaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prod, err := prodPattern()
+	if err != nil {
+		return nil, err
+	}
+
+	config := &kubeconfig{prod: prod}
+	clusters := map[string]bool{}
+	contexts := map[string]bool{}
+	users := map[string]bool{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		file := &loadedFile{Path: path}
+		if err := yaml.Unmarshal(data, &file.doc); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if err := file.doc.Decode(&file.Config); err != nil && file.doc.Kind != 0 {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		config.Files = append(config.Files, file)
+
+		if config.CurrentContext == "" && file.Config.CurrentContext != "" {
+			config.CurrentContext = file.Config.CurrentContext
+			config.CurrentContextFile = path
+		}
+		for _, c := range file.Config.Clusters {
+			if clusters[c.Name] {
+				continue
+			}
+			clusters[c.Name] = true
+			config.Clusters = append(config.Clusters, kubeCluster{
+				Name:     c.Name,
+				Server:   c.Cluster.Server,
+				Insecure: c.Cluster.InsecureSkipTLSVerify,
+				File:     path,
+			})
+		}
+		for _, c := range file.Config.Contexts {
+			if contexts[c.Name] {
+				continue
+			}
+			contexts[c.Name] = true
+			config.Contexts = append(config.Contexts, kubeContext{
+				Name:      c.Name,
+				Cluster:   c.Context.Cluster,
+				User:      c.Context.User,
+				Namespace: c.Context.Namespace,
+				File:      path,
+			})
+		}
+		for _, u := range file.Config.Users {
+			if !users[u.Name] {
+				users[u.Name] = true
+				config.Users = append(config.Users, u.Name)
+			}
+		}
+	}
+
+	if len(config.Files) == 0 {
+		return nil, modules.NotFoundError("no kubeconfig found (looked in %s)", strings.Join(paths, ", "))
+	}
+	return config, nil
+}
+
+func (k *kubeconfig) context(name string) (kubeContext, bool) {
+	for _, c := range k.Contexts {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return kubeContext{}, false
+}
+
+func (k *kubeconfig) cluster(name string) (kubeCluster, bool) {
+	for _, c := range k.Clusters {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return kubeCluster{}, false
+}
+
+func (k *kubeconfig) file(path string) *loadedFile {
+	for _, f := range k.Files {
+		if f.Path == path {
+			return f
+		}
+	}
+	return nil
+}
+
+func (k *kubeconfig) isProduction(c kubeContext) bool {
+	if k.prod.MatchString(c.Name) || k.prod.MatchString(c.Cluster) {
+		return true
+	}
+	cluster, ok := k.cluster(c.Cluster)
+	return ok && k.prod.MatchString(cluster.Server)
+}
+
+// useContext writes current-context to the file that currently sets it, or to
+// the first file on the path when none does, which is what kubectl does.
+func (k *kubeconfig) useContext(name string) (string, error) {
+	if _, ok := k.context(name); !ok {
+		return "", modules.NotFoundError("unknown context %q", name)
+	}
+	path := k.CurrentContextFile
+	if path == "" {
+		path = k.Files[0].Path
+	}
+	file := k.file(path)
+	root, err := file.mapping()
+	if err != nil {
+		return "", err
+	}
+	setScalar(root, "current-context", name)
+	backup, err := file.save()
+	if err != nil {
+		return "", err
+	}
+	k.CurrentContext = name
+	k.CurrentContextFile = path
+	return backup, nil
+}
+
+func (k *kubeconfig) setNamespace(contextName, namespace string) (string, error) {
+	c, ok := k.context(contextName)
+	if !ok {
+		return "", modules.NotFoundError("unknown context %q", contextName)
+	}
+	file := k.file(c.File)
+	root, err := file.mapping()
+	if err != nil {
+		return "", err
+	}
+
+	entries := mappingValue(root, "contexts")
+	if entries == nil || entries.Kind != yaml.SequenceNode {
+		return "", fmt.Errorf("%s has no contexts list", file.Path)
+	}
+	for _, entry := range entries.Content {
+		if entry.Kind != yaml.MappingNode {
+			continue
+		}
+		if nameNode := mappingValue(entry, "name"); nameNode == nil || nameNode.Value != contextName {
+			continue
+		}
+		body := mappingValue(entry, "context")
+		if body == nil || body.Kind != yaml.MappingNode {
+			body = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			setNode(entry, "context", body)
+		}
+		setScalar(body, "namespace", namespace)
+		return file.save()
+	}
+	return "", fmt.Errorf("context %q not found in %s", contextName, file.Path)
+}
+
+func (f *loadedFile) mapping() (*yaml.Node, error) {
+	if f.doc.Kind == 0 {
+		f.doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if f.doc.Kind != yaml.DocumentNode || len(f.doc.Content) == 0 || f.doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s is not a kubeconfig mapping", f.Path)
+	}
+	return f.doc.Content[0], nil
+}
+
+// save backs up the original file and atomically replaces it.
+func (f *loadedFile) save() (string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&f.doc); err != nil {
+		return "", fmt.Errorf("failed to encode %s: %w", f.Path, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode %s: %w", f.Path, err)
+	}
+
+	backup, err := safefile.Backup(f.Path)
+	if err != nil {
+		return "", err
+	}
+	if err := safefile.Write(f.Path, buf.Bytes()); err != nil {
+		return "", err
+	}
+	return backup, nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setNode(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+func setScalar(node *yaml.Node, key, value string) {
+	setNode(node, key, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value})
+}
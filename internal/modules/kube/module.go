@@ -0,0 +1,324 @@
+package kube
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/requirements"
+)
+
+type Tool struct{}
+
+func New() modules.Tool {
+	return Tool{}
+}
+
+func (Tool) ID() string { return "kube" }
+
+func (Tool) Label() string { return "Kubernetes Contexts" }
+
+func (Tool) Description() string { return "Inspect and switch kubeconfig contexts" }
+
+func (Tool) Requirements() []requirements.Check { return nil }
+
+func (Tool) Actions() []modules.Action {
+	return []modules.Action{
+		{
+			ID:          "current",
+			Label:       "Show current context",
+			Description: "Show the current context, cluster, user and namespace",
+			Usage:       "devtools run kube current [--kubeconfig <file>]",
+			Run:         showCurrent,
+		},
+		{
+			ID:          "contexts",
+			Label:       "List contexts",
+			Description: "List contexts from all files on KUBECONFIG",
+			Usage:       "devtools run kube contexts [--kubeconfig <file>]",
+			Run:         listContexts,
+		},
+		{
+			ID:          "clusters",
+			Label:       "List clusters",
+			Description: "List clusters and their API servers",
+			Usage:       "devtools run kube clusters [--kubeconfig <file>]",
+			Run:         listClusters,
+		},
+		{
+			ID:          "use-context",
+			Label:       "Switch context",
+			Description: "Set current-context in the kubeconfig (a .bak copy is kept)",
+			Usage:       "devtools run kube use-context <name> [--kubeconfig <file>]",
+			Run:         useContextAction,
+		},
+		{
+			ID:          "set-namespace",
+			Label:       "Set namespace",
+			Description: "Set the namespace of a context (default: current context)",
+			Usage:       "devtools run kube set-namespace <namespace> [--context <name>] [--kubeconfig <file>]",
+			Run:         setNamespaceAction,
+		},
+		{
+			ID:          "namespaces",
+			Label:       "List cluster namespaces",
+			Description: "Runs kubectl get namespaces for a context",
+			Usage:       "devtools run kube namespaces [--context <name>] [--kubeconfig <file>]",
+			Run:         listNamespaces,
+		},
+	}
+}
+
+func (Tool) Menu() *menu.Menu {
+	contexts := &contextMenu{}
+	switchMenu := menu.New("Kubernetes / Switch context", []menu.Item{menu.BackItem("Back")})
+	switchMenu.Leading = contexts.Items
+	switchMenu.OnOpen = contexts.Reset
+
+	kubectlMenu := menu.NewBuilder("Kubernetes / kubectl").
+		Action("List cluster namespaces", "Runs kubectl get namespaces", func() (string, error) {
+			return listNamespaces(modules.ActionContext{})
+		}).
+		Action("Show cluster info", "Runs kubectl cluster-info", func() (string, error) {
+			return runKubectl(modules.ActionContext{}, "cluster-info")
+		}).
+		WithBack().
+		Build()
+
+	return menu.NewBuilder("Kubernetes Contexts").
+		Action("Show current context", "Context, cluster, user and namespace", func() (string, error) {
+			return showCurrent(modules.ActionContext{})
+		}).
+		SubMenu("Switch context", "Production-looking contexts are red and ask for confirmation", switchMenu).
+		Action("Set namespace", "Prompt for a namespace for the current context", setNamespacePrompt).
+		Action("List contexts", "Contexts from all files on KUBECONFIG", func() (string, error) {
+			return listContexts(modules.ActionContext{})
+		}).
+		Action("List clusters", "Clusters and API servers", func() (string, error) {
+			return listClusters(modules.ActionContext{})
+		}).
+		SubMenu("kubectl", "Requires kubectl command", kubectlMenu, requirements.CommandExistsWithBrew("kubectl", "kubernetes-cli")).
+		WithBack().
+		Build()
+}
+
+// contextMenu caches the context items while the Switch context menu is
+// open, since Leading runs on every render. Opening the menu or switching
+// context reloads the kubeconfig.
+type contextMenu struct {
+	items []menu.Item
+}
+
+func (c *contextMenu) Reset() {
+	c.items = nil
+}
+
+func (c *contextMenu) Items() []menu.Item {
+	if c.items == nil {
+		c.items = contextItems(c.Reset)
+	}
+	return c.items
+}
+
+func contextItems(onSwitch func()) []menu.Item {
+	config, err := loadKubeconfig(modules.ActionContext{})
+	if err != nil {
+		return []menu.Item{{
+			Label:       "No contexts",
+			Description: err.Error(),
+			Run:         func() (string, error) { return "", err },
+		}}
+	}
+
+	items := make([]menu.Item, 0, len(config.Contexts))
+	for _, c := range config.Contexts {
+		name := c.Name
+		description := fmt.Sprintf("cluster %s", cliutil.Dash(c.Cluster))
+		if c.Namespace != "" {
+			description += fmt.Sprintf(", namespace %s", c.Namespace)
+		}
+		if name == config.CurrentContext {
+			description += " (current)"
+		}
+		prod := config.isProduction(c)
+		if prod {
+			description += " [production]"
+		}
+		items = append(items, menu.Item{
+			Label:       name,
+			Description: description,
+			Danger:      prod,
+			Run: func() (string, error) {
+				defer onSwitch()
+				return useContextAction(modules.ActionContext{Positionals: []string{name}})
+			},
+		})
+	}
+	return items
+}
+
+func showCurrent(ctx modules.ActionContext) (string, error) {
+	config, err := loadKubeconfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	if config.CurrentContext == "" {
+		return "No current context is set.", nil
+	}
+
+	var b strings.Builder
+	c, ok := config.context(config.CurrentContext)
+	fmt.Fprintf(&b, "Context: %s (from %s)\n", config.CurrentContext, config.CurrentContextFile)
+	if !ok {
+		fmt.Fprintf(&b, "Warning: context %q is not defined in any kubeconfig file\n", config.CurrentContext)
+		return strings.TrimRight(b.String(), "\n"), nil
+	}
+	fmt.Fprintf(&b, "Cluster: %s\n", cliutil.Dash(c.Cluster))
+	if cluster, ok := config.cluster(c.Cluster); ok {
+		fmt.Fprintf(&b, "Server: %s\n", cliutil.Dash(cluster.Server))
+		if cluster.Insecure {
+			b.WriteString("Warning: TLS verification is disabled for this cluster\n")
+		}
+	}
+	fmt.Fprintf(&b, "User: %s\n", cliutil.Dash(c.User))
+	fmt.Fprintf(&b, "Namespace: %s\n", cliutil.FirstNonEmpty(c.Namespace, "default"))
+	if config.isProduction(c) {
+		b.WriteString("Warning: this looks like a PRODUCTION context\n")
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func listContexts(ctx modules.ActionContext) (string, error) {
+	config, err := loadKubeconfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(config.Contexts) == 0 {
+		return "No contexts defined.", nil
+	}
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tNAME\tCLUSTER\tUSER\tNAMESPACE\tPROD\tFILE")
+	for _, c := range config.Contexts {
+		marker := ""
+		if c.Name == config.CurrentContext {
+			marker = "*"
+		}
+		prod := ""
+		if config.isProduction(c) {
+			prod = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", marker, c.Name, cliutil.Dash(c.Cluster), cliutil.Dash(c.User), cliutil.Dash(c.Namespace), cliutil.Dash(prod), c.File)
+	}
+	_ = w.Flush()
+	b.WriteString("\n* = current context")
+	return b.String(), nil
+}
+
+func listClusters(ctx modules.ActionContext) (string, error) {
+	config, err := loadKubeconfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(config.Clusters) == 0 {
+		return "No clusters defined.", nil
+	}
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSERVER\tTLS\tFILE")
+	for _, c := range config.Clusters {
+		tls := "verified"
+		if c.Insecure {
+			tls = "SKIPPED"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Name, cliutil.Dash(c.Server), tls, c.File)
+	}
+	_ = w.Flush()
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func useContextAction(ctx modules.ActionContext) (string, error) {
+	name := ctx.Params["context"]
+	if name == "" && len(ctx.Positionals) > 0 {
+		name = ctx.Positionals[0]
+	}
+	if name == "" {
+		return "", modules.UsageError("missing context name")
+	}
+
+	config, err := loadKubeconfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	if name == config.CurrentContext {
+		return fmt.Sprintf("Already using context %q.", name), nil
+	}
+	backup, err := config.useContext(name)
+	if err != nil {
+		return "", err
+	}
+
+	out := fmt.Sprintf("Switched to context %q in %s (backup: %s).", name, config.CurrentContextFile, backup)
+	if c, _ := config.context(name); config.isProduction(c) {
+		out += "\nWarning: this looks like a PRODUCTION context."
+	}
+	return out, nil
+}
+
+func setNamespaceAction(ctx modules.ActionContext) (string, error) {
+	namespace := ctx.Params["namespace"]
+	if namespace == "" && len(ctx.Positionals) > 0 {
+		namespace = ctx.Positionals[0]
+	}
+	if namespace == "" {
+		return "", modules.UsageError("missing namespace")
+	}
+
+	config, err := loadKubeconfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	contextName := cliutil.FirstNonEmpty(ctx.Params["context"], config.CurrentContext)
+	if contextName == "" {
+		return "", modules.UsageError("no current context; pass --context <name>")
+	}
+	backup, err := config.setNamespace(contextName, namespace)
+	if err != nil {
+		return "", err
+	}
+	c, _ := config.context(contextName)
+	return fmt.Sprintf("Namespace of context %q set to %q in %s (backup: %s).", contextName, namespace, c.File, backup), nil
+}
+
+func setNamespacePrompt() (string, error) {
+	namespace, err := cliutil.Prompt("Namespace: ")
+	if err != nil {
+		return "", err
+	}
+	if namespace == "" {
+		return "", fmt.Errorf("namespace cannot be empty")
+	}
+	return setNamespaceAction(modules.ActionContext{Positionals: []string{namespace}})
+}
+
+func listNamespaces(ctx modules.ActionContext) (string, error) {
+	return runKubectl(ctx, "get", "namespaces")
+}
+
+func runKubectl(ctx modules.ActionContext, args ...string) (string, error) {
+	if err := requirements.CommandExists("kubectl").Run(); err != nil {
+		return "", modules.RequirementError("%w", err)
+	}
+	if path := ctx.Params["kubeconfig"]; path != "" {
+		args = append(args, "--kubeconfig", path)
+	}
+	if name := ctx.Params["context"]; name != "" {
+		args = append(args, "--context", name)
+	}
+	return cliutil.Run("kubectl", args...)
+}
@@ -0,0 +1,143 @@
+package kube
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/devtoolstest"
+)
+
+const devConfig = `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+  - name: dev
+    cluster:
+      server: https://dev.example.test
+contexts:
+  - name: dev
+    context:
+      cluster: dev
+      user: alice
+users:
+  - name: alice
+    user: {}
+`
+
+const prodConfig = `apiVersion: v1
+kind: Config
+current-context: prod-eu
+clusters:
+  - name: prod-eu
+    cluster:
+      server: https://k8s.eu.example.test
+contexts:
+  - name: prod-eu
+    context:
+      cluster: prod-eu
+      user: alice
+      namespace: payments
+  - name: dev
+    context:
+      cluster: shadowed
+`
+
+func writeConfigs(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	dev := filepath.Join(dir, "dev.yaml")
+	prod := filepath.Join(dir, "prod.yaml")
+	for path, content := range map[string]string{dev: devConfig, prod: prodConfig} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv(envKubeconfig, dev+string(os.PathListSeparator)+prod)
+	t.Setenv(envProdPattern, "")
+	return dev, prod
+}
+
+func TestMergedContexts(t *testing.T) {
+	writeConfigs(t)
+	out, err := devtoolstest.RunAction(t, New(), "current", nil)
+	if err != nil {
+		t.Fatalf("current error = %v", err)
+	}
+	devtoolstest.AssertFrameContains(t, out, "Context: dev", "Server: https://dev.example.test", "Namespace: default")
+
+	out, err = devtoolstest.RunAction(t, New(), "contexts", nil)
+	if err != nil {
+		t.Fatalf("contexts error = %v", err)
+	}
+	if strings.Contains(out, "shadowed") {
+		t.Errorf("later definition of dev was not ignored:\n%s", out)
+	}
+	devtoolstest.AssertFrameContains(t, out, "prod-eu", "payments", "yes")
+}
+
+func TestUseContextRewritesOwningFileWithBackup(t *testing.T) {
+	dev, prod := writeConfigs(t)
+
+	out, err := devtoolstest.RunAction(t, New(), "use-context", nil, "prod-eu")
+	if err != nil {
+		t.Fatalf("use-context error = %v", err)
+	}
+	devtoolstest.AssertFrameContains(t, out, `Switched to context "prod-eu"`, "PRODUCTION")
+
+	data, _ := os.ReadFile(dev)
+	if !strings.Contains(string(data), "current-context: prod-eu") {
+		t.Errorf("current-context not updated in the first file:\n%s", data)
+	}
+	if backup, _ := os.ReadFile(dev + ".bak"); string(backup) != devConfig {
+		t.Errorf("backup does not hold the original file:\n%s", backup)
+	}
+	if data, _ := os.ReadFile(prod); string(data) != prodConfig {
+		t.Errorf("unrelated file was rewritten:\n%s", data)
+	}
+}
+
+func TestContextItemsMarkProduction(t *testing.T) {
+	writeConfigs(t)
+	for _, item := range contextItems(func() {}) {
+		if item.Danger != (item.Label == "prod-eu") {
+			t.Errorf("item %q Danger = %v", item.Label, item.Danger)
+		}
+	}
+}
+
+func TestContextMenuCachesUntilReopenedOrSwitched(t *testing.T) {
+	dev, _ := writeConfigs(t)
+	contexts := &contextMenu{}
+	items := contexts.Items()
+	if len(items) != 2 {
+		t.Fatalf("got %d context items, want 2", len(items))
+	}
+
+	extra := strings.Replace(devConfig, "contexts:\n", "contexts:\n  - name: staging\n    context:\n      cluster: dev\n", 1)
+	if err := os.WriteFile(dev, []byte(extra), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(contexts.Items()); got != 2 {
+		t.Errorf("kubeconfig reloaded while the menu was open: %d items", got)
+	}
+	contexts.Reset()
+	items = contexts.Items()
+	if len(items) != 3 {
+		t.Fatalf("after reopening got %d items, want 3", len(items))
+	}
+
+	for _, item := range items {
+		if item.Label == "staging" {
+			if _, err := item.Run(); err != nil {
+				t.Fatalf("switch error = %v", err)
+			}
+		}
+	}
+	for _, item := range contexts.Items() {
+		if item.Label == "staging" && !strings.HasSuffix(item.Description, "(current)") {
+			t.Errorf("items not reloaded after switching: %q", item.Description)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package mockserver
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"go-devtools/internal/appdir"
+	"go-devtools/internal/safefile"
+)
+
+const (
+	capturesFile    = "captures.json"
+	defaultCapacity = 100
+	persistDelay    = 250 * time.Millisecond
+)
+
+type capturedRequest struct {
+	ID         int                 `json:"id"`
+	Time       time.Time           `json:"time"`
+	Method     string              `json:"method"`
+	Host       string              `json:"host"`
+	URI        string              `json:"uri"`
+	Proto      string              `json:"proto"`
+	RemoteAddr string              `json:"remoteAddr"`
+	Headers    map[string][]string `json:"headers"`
+	Body       string              `json:"body,omitempty"`
+	BodyBase64 bool                `json:"bodyBase64,omitempty"`
+	Status     int                 `json:"status"`
+	Response   string              `json:"response,omitempty"`
+}
+
+func (c capturedRequest) bodyBytes() []byte {
+	if !c.BodyBase64 {
+		return []byte(c.Body)
+	}
+	data, err := base64.StdEncoding.DecodeString(c.Body)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func (c capturedRequest) summary() string {
+	contentType := headerValue(c.Headers, "Content-Type")
+	if contentType == "" {
+		contentType = "no content type"
+	}
+	return fmt.Sprintf("#%-4d %s %-6s %s (%d bytes, %s)",
+		c.ID,
+		c.Time.Local().Format("15:04:05"),
+		c.Method,
+		c.URI,
+		len(c.bodyBytes()),
+		contentType,
+	)
+}
+
+func (c capturedRequest) detail() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#%d captured %s from %s\n\n", c.ID, c.Time.Local().Format(time.RFC3339), c.RemoteAddr)
+	fmt.Fprintf(&b, "%s %s %s\n", c.Method, c.URI, c.Proto)
+	fmt.Fprintf(&b, "Host: %s\n", c.Host)
+	for _, key := range sortedHeaderKeys(c.Headers) {
+		fmt.Fprintf(&b, "%s: %s\n", key, strings.Join(c.Headers[key], ", "))
+	}
+
+	body := c.bodyBytes()
+	if len(body) > 0 {
+		b.WriteString("\n")
+		var pretty bytes.Buffer
+		switch {
+		case c.BodyBase64:
+			fmt.Fprintf(&b, "[%d bytes of binary data]", len(body))
+		case json.Indent(&pretty, body, "", "  ") == nil:
+			b.Write(pretty.Bytes())
+		default:
+			b.Write(body)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+type ringBuffer struct {
+	mu       sync.Mutex
+	capacity int
+	nextID   int
+	items    []capturedRequest
+}
+
+func newRingBuffer(capacity int) *ringBuffer {
+	return &ringBuffer{capacity: capacity, nextID: 1}
+}
+
+func (r *ringBuffer) add(c capturedRequest) capturedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c.ID = r.nextID
+	r.nextID++
+	r.items = append(r.items, c)
+	if len(r.items) > r.capacity {
+		r.items = r.items[len(r.items)-r.capacity:]
+	}
+	return c
+}
+
+func (r *ringBuffer) snapshot() []capturedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]capturedRequest{}, r.items...)
+}
+
+type captureHandler struct {
+	buffer   *ringBuffer
+	live     io.Writer
+	status   int
+	response string
+	persist  func()
+}
+
+func (h *captureHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(io.LimitReader(req.Body, maxRequestBody))
+	c := capturedRequest{
+		Time:       time.Now(),
+		Method:     req.Method,
+		Host:       req.Host,
+		URI:        req.URL.RequestURI(),
+		Proto:      req.Proto,
+		RemoteAddr: req.RemoteAddr,
+		Headers:    req.Header.Clone(),
+		Status:     h.status,
+		Response:   h.response,
+	}
+	if utf8.Valid(body) {
+		c.Body = string(body)
+	} else {
+		c.Body = base64.StdEncoding.EncodeToString(body)
+		c.BodyBase64 = true
+	}
+
+	c = h.buffer.add(c)
+	if h.persist != nil {
+		h.persist()
+	}
+	fmt.Fprintln(h.live, c.summary())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(h.status)
+	_, _ = io.WriteString(w, h.response)
+}
+
+// captureStore writes the ring buffer to disk at most once per persistDelay,
+// so a burst of webhooks does not rewrite the file on every request.
+type captureStore struct {
+	path    string
+	buffer  *ringBuffer
+	onError func(error)
+
+	mu    sync.Mutex
+	timer *time.Timer
+}
+
+func (s *captureStore) schedule() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer == nil {
+		s.timer = time.AfterFunc(persistDelay, s.flush)
+	}
+}
+
+func (s *captureStore) flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if err := saveCaptures(s.path, s.buffer.snapshot()); err != nil && s.onError != nil {
+		s.onError(err)
+	}
+}
+
+func capturesPath() (string, error) {
+	return appdir.Path(capturesFile)
+}
+
+func saveCaptures(path string, captures []capturedRequest) error {
+	data, err := json.MarshalIndent(captures, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode captures: %w", err)
+	}
+	if err := safefile.Write(path, data); err != nil {
+		return fmt.Errorf("failed to write captures: %w", err)
+	}
+	return nil
+}
+
+func loadCaptures() ([]capturedRequest, error) {
+	path, err := capturesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read captures: %w", err)
+	}
+	var captures []capturedRequest
+	if err := json.Unmarshal(data, &captures); err != nil {
+		return nil, fmt.Errorf("failed to parse captures %s: %w", path, err)
+	}
+	return captures, nil
+}
+
+var hopHeaders = map[string]bool{
+	"Connection":        true,
+	"Content-Length":    true,
+	"Host":              true,
+	"Keep-Alive":        true,
+	"Proxy-Connection":  true,
+	"Te":                true,
+	"Trailer":           true,
+	"Transfer-Encoding": true,
+	"Upgrade":           true,
+	"Accept-Encoding":   true,
+}
+
+func replayCapture(c capturedRequest, target string) (string, error) {
+	target = strings.TrimRight(target, "/")
+	req, err := http.NewRequest(c.Method, target+c.URI, bytes.NewReader(c.bodyBytes()))
+	if err != nil {
+		return "", fmt.Errorf("invalid replay target %q: %w", target, err)
+	}
+	for key, values := range c.Headers {
+		if hopHeaders[http.CanonicalHeaderKey(key)] {
+			continue
+		}
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	started := time.Now()
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("replay failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxRequestBody))
+
+	out := fmt.Sprintf("Replayed #%d to %s %s -> %s (%s)", c.ID, req.Method, req.URL, resp.Status, time.Since(started).Round(time.Millisecond))
+	if len(body) > 0 {
+		out += "\n\n" + strings.TrimRight(string(body), "\n")
+	}
+	return out, nil
+}
+
+func sortedHeaderKeys(headers map[string][]string) []string {
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func headerValue(headers map[string][]string, key string) string {
+	return http.Header(headers).Get(key)
+}
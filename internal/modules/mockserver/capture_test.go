@@ -0,0 +1,82 @@
+package mockserver
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"go-devtools/internal/devtoolstest"
+)
+
+func TestCaptureHandlerPersistsDebounced(t *testing.T) {
+	devtoolstest.Isolate(t)
+	path, err := capturesPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := newRingBuffer(5)
+	store := &captureStore{path: path, buffer: buffer, onError: func(err error) { t.Error(err) }}
+	var live bytes.Buffer
+	var liveMu sync.Mutex
+	handler := &captureHandler{
+		buffer:   buffer,
+		live:     lockedWriter{&liveMu, &live},
+		status:   http.StatusAccepted,
+		response: `{"ok":true}`,
+		persist:  store.schedule,
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Post(server.URL+"/hook?source=test", "application/json", strings.NewReader(`{"event":"push"}`))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusAccepted {
+				t.Errorf("status = %d, want 202", resp.StatusCode)
+			}
+		}()
+	}
+	wg.Wait()
+	store.flush()
+
+	captures, err := loadCaptures()
+	if err != nil {
+		t.Fatalf("loadCaptures() error = %v", err)
+	}
+	if len(captures) != 5 || captures[4].ID != 20 {
+		t.Fatalf("persisted %d captures (last id %d), want the last 5 of 20", len(captures), captures[len(captures)-1].ID)
+	}
+	if captures[0].URI != "/hook?source=test" || captures[0].Body != `{"event":"push"}` {
+		t.Errorf("capture = %+v", captures[0])
+	}
+	if got := strings.Count(live.String(), "\n"); got != 20 {
+		t.Errorf("live output has %d lines, want 20", got)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(filepath.Dir(path), ".captures.json.*")); len(matches) != 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
+
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  *bytes.Buffer
+}
+
+func (l lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
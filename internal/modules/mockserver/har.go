@@ -0,0 +1,141 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type harLog struct {
+	Log harContent `json:"log"`
+}
+
+type harContent struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string         `json:"startedDateTime"`
+	Time            float64        `json:"time"`
+	Request         harRequest     `json:"request"`
+	Response        harResponse    `json:"response"`
+	Cache           struct{}       `json:"cache"`
+	Timings         map[string]int `json:"timings"`
+	Comment         string         `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harBody        `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harBody struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+func buildHAR(captures []capturedRequest) ([]byte, error) {
+	entries := make([]harEntry, 0, len(captures))
+	for _, c := range captures {
+		entries = append(entries, harEntry{
+			StartedDateTime: c.Time.UTC().Format(time.RFC3339Nano),
+			Request:         harRequestFor(c),
+			Response: harResponse{
+				Status:      c.Status,
+				StatusText:  http.StatusText(c.Status),
+				HTTPVersion: c.Proto,
+				Cookies:     []harNameValue{},
+				Headers:     []harNameValue{{Name: "Content-Type", Value: "application/json"}},
+				Content:     harBody{Size: len(c.Response), MimeType: "application/json", Text: c.Response},
+				HeadersSize: -1,
+				BodySize:    len(c.Response),
+			},
+			Timings: map[string]int{"send": 0, "wait": 0, "receive": 0},
+			Comment: fmt.Sprintf("captured by go-devtools (#%d from %s)", c.ID, c.RemoteAddr),
+		})
+	}
+
+	data, err := json.MarshalIndent(harLog{Log: harContent{
+		Version: "1.2",
+		Creator: harCreator{Name: "go-devtools", Version: "dev"},
+		Entries: entries,
+	}}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode HAR: %w", err)
+	}
+	return data, nil
+}
+
+func harRequestFor(c capturedRequest) harRequest {
+	req := harRequest{
+		Method:      c.Method,
+		URL:         "http://" + c.Host + c.URI,
+		HTTPVersion: c.Proto,
+		Cookies:     []harNameValue{},
+		Headers:     []harNameValue{},
+		QueryString: []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    len(c.bodyBytes()),
+	}
+	for _, key := range sortedHeaderKeys(c.Headers) {
+		for _, value := range c.Headers[key] {
+			req.Headers = append(req.Headers, harNameValue{Name: key, Value: value})
+		}
+	}
+	if _, rawQuery, ok := strings.Cut(c.URI, "?"); ok {
+		query, _ := url.ParseQuery(rawQuery)
+		for key, values := range query {
+			for _, value := range values {
+				req.QueryString = append(req.QueryString, harNameValue{Name: key, Value: value})
+			}
+		}
+	}
+	if c.Body != "" {
+		req.PostData = &harPostData{MimeType: headerValue(c.Headers, "Content-Type"), Text: c.Body}
+		if c.BodyBase64 {
+			req.PostData.Encoding = "base64"
+		}
+	}
+	return req
+}
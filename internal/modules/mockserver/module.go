@@ -0,0 +1,431 @@
+package mockserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/requirements"
+)
+
+const (
+	defaultAddr        = "127.0.0.1:8080"
+	defaultCaptureAddr = "127.0.0.1:8081"
+	defaultHAR         = "captures.har"
+)
+
+type Tool struct{}
+
+func New() modules.Tool {
+	return Tool{}
+}
+
+func (Tool) ID() string { return "mock-server" }
+
+func (Tool) Label() string { return "Mock Server" }
+
+func (Tool) Description() string { return "Mock HTTP routes and capture incoming webhooks" }
+
+func (Tool) Requirements() []requirements.Check { return nil }
+
+func (Tool) Actions() []modules.Action {
+	return []modules.Action{
+		{
+			ID:          "serve",
+			Label:       "Start mock server",
+			Description: "Serve routes from a route file and log every request (Ctrl+C to stop)",
+			Usage:       "devtools run mock-server serve <routes.yaml|routes.json> [--addr 127.0.0.1:8080] [--duration 10m] [--verbose true] [--seed 1]",
+			Run:         runServe,
+		},
+		{
+			ID:          "validate",
+			Label:       "Validate route file",
+			Description: "Parse a route file and print its route table",
+			Usage:       "devtools run mock-server validate <routes.yaml|routes.json>",
+			Run:         runValidate,
+		},
+		{
+			ID:          "capture",
+			Label:       "Capture requests",
+			Description: "Record every incoming request into a ring buffer and print it live (Ctrl+C to stop)",
+			Usage:       "devtools run mock-server capture [--addr 127.0.0.1:8081] [--size 100] [--status 200] [--response '{\"ok\":true}'] [--duration 10m]",
+			Run:         runCapture,
+		},
+		{
+			ID:          "captures",
+			Label:       "List captured requests",
+			Description: "List requests recorded by the last capture session",
+			Usage:       "devtools run mock-server captures",
+			Run:         runListCaptures,
+		},
+		{
+			ID:          "inspect",
+			Label:       "Inspect captured request",
+			Description: "Show headers and body of a captured request",
+			Usage:       "devtools run mock-server inspect <id>",
+			Run:         runInspect,
+		},
+		{
+			ID:          "replay",
+			Label:       "Replay captured request",
+			Description: "Send a captured request to another base URL",
+			Usage:       "devtools run mock-server replay <id> --to http://localhost:3000",
+			Run:         runReplay,
+		},
+		{
+			ID:          "export-har",
+			Label:       "Export captures as HAR",
+			Description: "Write captured requests to a HAR 1.2 file",
+			Usage:       "devtools run mock-server export-har [--out captures.har]",
+			Run:         runExportHAR,
+		},
+	}
+}
+
+func (Tool) Menu() *menu.Menu {
+	capture := menu.NewBuilder("Mock Server / Request capture").
+		LiveAction("Capture requests", fmt.Sprintf("Listen on %s until Ctrl+C", defaultCaptureAddr), func(out io.Writer) (string, error) {
+			return capture(modules.ActionContext{}, out)
+		}).
+		Action("List captured requests", "Requests from the last session", func() (string, error) {
+			return runListCaptures(modules.ActionContext{})
+		}).
+		Action("Inspect captured request", "Prompt for request id", func() (string, error) {
+			id, err := cliutil.Prompt("Request id: ")
+			if err != nil {
+				return "", err
+			}
+			return runInspect(modules.ActionContext{Params: map[string]string{"id": id}})
+		}).
+		Action("Replay captured request", "Prompt for request id and target URL", func() (string, error) {
+			id, err := cliutil.Prompt("Request id: ")
+			if err != nil {
+				return "", err
+			}
+			target, err := cliutil.Prompt("Target base URL: ")
+			if err != nil {
+				return "", err
+			}
+			return runReplay(modules.ActionContext{Params: map[string]string{"id": id, "to": target}})
+		}).
+		Action("Export captures as HAR", "Writes captures.har in the current directory", func() (string, error) {
+			return runExportHAR(modules.ActionContext{})
+		}).
+		WithBack().
+		Build()
+
+	return menu.NewBuilder("Mock Server").
+		Action("Start mock server", "Prompt for route file and address", servePrompt).
+		Action("Validate route file", "Prompt for route file", func() (string, error) {
+			path, err := cliutil.Prompt("Route file: ")
+			if err != nil {
+				return "", err
+			}
+			return runValidate(modules.ActionContext{Params: map[string]string{"routes": path}})
+		}).
+		SubMenu("Request capture", "Local webhook catcher", capture).
+		WithBack().
+		Build()
+}
+
+func runValidate(ctx modules.ActionContext) (string, error) {
+	path := routesPath(ctx)
+	if path == "" {
+		return "", modules.UsageError("missing route file (use --routes or first positional argument)")
+	}
+	routes, err := loadRoutes(path)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %d routes\n", path, len(routes))
+	for _, r := range routes {
+		fmt.Fprintf(&b, "  %-6s %-30s -> %d", r.Method, r.Path, r.Status)
+		if r.Latency != "" {
+			fmt.Fprintf(&b, "  latency=%s", r.Latency)
+		}
+		if r.FailureRate > 0 {
+			fmt.Fprintf(&b, "  failures=%.0f%%(%d)", r.FailureRate*100, r.FailureStatus)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func runServe(ctx modules.ActionContext) (string, error) {
+	path := routesPath(ctx)
+	if path == "" {
+		return "", modules.UsageError("missing route file (use --routes or first positional argument)")
+	}
+	routes, err := loadRoutes(path)
+	if err != nil {
+		return "", err
+	}
+
+	addr := ctx.Params["addr"]
+	if addr == "" {
+		addr = defaultAddr
+	}
+	var duration time.Duration
+	if raw := ctx.Params["duration"]; raw != "" {
+		if duration, err = time.ParseDuration(raw); err != nil {
+			return "", modules.UsageError("invalid duration %q", raw)
+		}
+	}
+	seed := time.Now().UnixNano()
+	if raw := ctx.Params["seed"]; raw != "" {
+		if _, err := fmt.Sscan(raw, &seed); err != nil {
+			return "", modules.UsageError("invalid seed %q", raw)
+		}
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mock := newServer(routes, os.Stdout, ctx.Params["verbose"] == "true", seed)
+	httpServer := &http.Server{Handler: mock, ReadHeaderTimeout: 10 * time.Second}
+
+	fmt.Printf("Mock server listening on http://%s (%d routes from %s). Press Ctrl+C to stop.\n", listener.Addr(), len(routes), path)
+	started := time.Now()
+	if err := serveUntilDone(httpServer, listener, duration); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Mock server stopped after %s; served %d requests.", time.Since(started).Round(time.Second), mock.count()), nil
+}
+
+func serveUntilDone(httpServer *http.Server, listener net.Listener, duration time.Duration) error {
+	stop, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	if duration > 0 {
+		var cancelTimeout context.CancelFunc
+		stop, cancelTimeout = context.WithTimeout(stop, duration)
+		defer cancelTimeout()
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("server failed: %w", err)
+	case <-stop.Done():
+	}
+
+	shutdown, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelShutdown()
+	if err := httpServer.Shutdown(shutdown); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to stop server: %w", err)
+	}
+	return nil
+}
+
+func runCapture(ctx modules.ActionContext) (string, error) {
+	return capture(ctx, os.Stdout)
+}
+
+// capture runs a capture session, printing each request to live as it
+// arrives: stdout in command mode, the output pane in the TUI.
+func capture(ctx modules.ActionContext, live io.Writer) (string, error) {
+	addr := ctx.Params["addr"]
+	if addr == "" {
+		addr = defaultCaptureAddr
+	}
+	capacity := defaultCapacity
+	if raw := ctx.Params["size"]; raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			return "", modules.UsageError("invalid size %q", raw)
+		}
+		capacity = parsed
+	}
+	status := http.StatusOK
+	if raw := ctx.Params["status"]; raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || http.StatusText(parsed) == "" {
+			return "", modules.UsageError("invalid status %q", raw)
+		}
+		status = parsed
+	}
+	response := ctx.Params["response"]
+	if response == "" {
+		response = `{"ok":true}`
+	}
+	var duration time.Duration
+	if raw := ctx.Params["duration"]; raw != "" {
+		var err error
+		if duration, err = time.ParseDuration(raw); err != nil {
+			return "", modules.UsageError("invalid duration %q", raw)
+		}
+	}
+
+	path, err := capturesPath()
+	if err != nil {
+		return "", err
+	}
+	if err := saveCaptures(path, nil); err != nil {
+		return "", err
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	buffer := newRingBuffer(capacity)
+	store := &captureStore{
+		path:   path,
+		buffer: buffer,
+		onError: func(err error) {
+			fmt.Fprintf(live, "warning: %v\n", err)
+		},
+	}
+	handler := &captureHandler{
+		buffer:   buffer,
+		live:     live,
+		status:   status,
+		response: response,
+		persist:  store.schedule,
+	}
+	httpServer := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	fmt.Fprintf(live, "Capturing requests on http://%s (keeping the last %d). Press Ctrl+C to stop.\n", listener.Addr(), capacity)
+	fmt.Fprintln(live, "Inspect, replay or export them afterwards from the Request capture menu or the inspect/replay/export-har actions.")
+	err = serveUntilDone(httpServer, listener, duration)
+	store.flush()
+	if err != nil {
+		return "", err
+	}
+
+	captured := buffer.snapshot()
+	var b strings.Builder
+	fmt.Fprintf(&b, "Capture stopped; %d requests kept in %s.", len(captured), path)
+	for _, c := range captured {
+		fmt.Fprintf(&b, "\n%s", c.summary())
+	}
+	return b.String(), nil
+}
+
+func runListCaptures(_ modules.ActionContext) (string, error) {
+	captures, err := loadCaptures()
+	if err != nil {
+		return "", err
+	}
+	if len(captures) == 0 {
+		return "No captured requests. Start a session with the capture action.", nil
+	}
+	lines := make([]string, 0, len(captures))
+	for _, c := range captures {
+		lines = append(lines, c.summary())
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func runInspect(ctx modules.ActionContext) (string, error) {
+	c, err := findCapture(ctx)
+	if err != nil {
+		return "", err
+	}
+	return c.detail(), nil
+}
+
+func runReplay(ctx modules.ActionContext) (string, error) {
+	c, err := findCapture(ctx)
+	if err != nil {
+		return "", err
+	}
+	target := ctx.Params["to"]
+	if target == "" && len(ctx.Positionals) > 1 {
+		target = ctx.Positionals[1]
+	}
+	if target == "" {
+		return "", modules.UsageError("missing target base URL (use --to)")
+	}
+	return replayCapture(c, target)
+}
+
+func runExportHAR(ctx modules.ActionContext) (string, error) {
+	captures, err := loadCaptures()
+	if err != nil {
+		return "", err
+	}
+	if len(captures) == 0 {
+		return "", modules.NotFoundError("no captured requests to export")
+	}
+	data, err := buildHAR(captures)
+	if err != nil {
+		return "", err
+	}
+
+	out := ctx.Params["out"]
+	if out == "" {
+		out = defaultHAR
+	}
+	if err := os.WriteFile(out, append(data, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", out, err)
+	}
+	return fmt.Sprintf("Exported %d requests to %s.", len(captures), out), nil
+}
+
+func findCapture(ctx modules.ActionContext) (capturedRequest, error) {
+	raw := ctx.Params["id"]
+	if raw == "" && len(ctx.Positionals) > 0 {
+		raw = ctx.Positionals[0]
+	}
+	if raw == "" {
+		return capturedRequest{}, modules.UsageError("missing request id (use --id or first positional argument)")
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(raw, "#"))
+	if err != nil {
+		return capturedRequest{}, modules.UsageError("invalid request id %q", raw)
+	}
+
+	captures, err := loadCaptures()
+	if err != nil {
+		return capturedRequest{}, err
+	}
+	for _, c := range captures {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return capturedRequest{}, modules.NotFoundError("captured request #%d not found", id)
+}
+
+func routesPath(ctx modules.ActionContext) string {
+	if path := ctx.Params["routes"]; path != "" {
+		return path
+	}
+	if len(ctx.Positionals) > 0 {
+		return ctx.Positionals[0]
+	}
+	return ""
+}
+
+func servePrompt() (string, error) {
+	path, err := cliutil.Prompt("Route file: ")
+	if err != nil {
+		return "", err
+	}
+	addr, err := cliutil.Prompt(fmt.Sprintf("Listen address [%s]: ", defaultAddr))
+	if err != nil {
+		return "", err
+	}
+	return runServe(modules.ActionContext{Params: map[string]string{"routes": path, "addr": addr}})
+}
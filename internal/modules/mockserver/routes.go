@@ -0,0 +1,184 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"go-devtools/internal/modules"
+)
+
+type routeFile struct {
+	Routes []route `json:"routes" yaml:"routes"`
+}
+
+type route struct {
+	Method        string            `json:"method" yaml:"method"`
+	Path          string            `json:"path" yaml:"path"`
+	Status        int               `json:"status" yaml:"status"`
+	Headers       map[string]string `json:"headers" yaml:"headers"`
+	Body          string            `json:"body" yaml:"body"`
+	BodyFile      string            `json:"bodyFile" yaml:"bodyFile"`
+	Latency       string            `json:"latency" yaml:"latency"`
+	FailureRate   float64           `json:"failureRate" yaml:"failureRate"`
+	FailureStatus int               `json:"failureStatus" yaml:"failureStatus"`
+	FailureBody   string            `json:"failureBody" yaml:"failureBody"`
+
+	segments   []string
+	template   *template.Template
+	minLatency time.Duration
+	maxLatency time.Duration
+}
+
+func loadRoutes(path string) ([]*route, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read route file: %w", err)
+	}
+
+	var file routeFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	default:
+		err = yaml.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, modules.UsageError("failed to parse route file %s: %v", path, err)
+	}
+	if len(file.Routes) == 0 {
+		return nil, modules.UsageError("route file %s defines no routes", path)
+	}
+
+	routes := make([]*route, 0, len(file.Routes))
+	for i := range file.Routes {
+		r := &file.Routes[i]
+		if err := r.compile(filepath.Dir(path)); err != nil {
+			return nil, modules.UsageError("route %d (%s %s): %v", i+1, r.Method, r.Path, err)
+		}
+		routes = append(routes, r)
+	}
+	return routes, nil
+}
+
+func (r *route) compile(baseDir string) error {
+	if !strings.HasPrefix(r.Path, "/") {
+		return fmt.Errorf("path must start with /")
+	}
+	r.Method = strings.ToUpper(r.Method)
+	if r.Method == "" {
+		r.Method = "*"
+	}
+	if r.Status == 0 {
+		r.Status = http.StatusOK
+	}
+	if r.FailureRate < 0 || r.FailureRate > 1 {
+		return fmt.Errorf("failureRate must be between 0 and 1")
+	}
+	if r.FailureStatus == 0 {
+		r.FailureStatus = http.StatusInternalServerError
+	}
+	r.segments = splitPath(r.Path)
+
+	if r.BodyFile != "" {
+		bodyPath := r.BodyFile
+		if !filepath.IsAbs(bodyPath) {
+			bodyPath = filepath.Join(baseDir, bodyPath)
+		}
+		data, err := os.ReadFile(bodyPath)
+		if err != nil {
+			return fmt.Errorf("failed to read bodyFile: %w", err)
+		}
+		r.Body = string(data)
+	}
+
+	tmpl, err := template.New(r.Path).Funcs(templateFuncs).Parse(r.Body)
+	if err != nil {
+		return fmt.Errorf("invalid body template: %w", err)
+	}
+	r.template = tmpl
+
+	if r.Latency != "" {
+		minRaw, maxRaw, isRange := strings.Cut(r.Latency, "..")
+		if r.minLatency, err = time.ParseDuration(strings.TrimSpace(minRaw)); err != nil {
+			return fmt.Errorf("invalid latency %q", r.Latency)
+		}
+		r.maxLatency = r.minLatency
+		if isRange {
+			if r.maxLatency, err = time.ParseDuration(strings.TrimSpace(maxRaw)); err != nil || r.maxLatency < r.minLatency {
+				return fmt.Errorf("invalid latency range %q", r.Latency)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *route) match(method, path string) (map[string]string, bool) {
+	if r.Method != "*" && r.Method != method {
+		return nil, false
+	}
+
+	params := map[string]string{}
+	segments := splitPath(path)
+	for i, pattern := range r.segments {
+		if pattern == "*" {
+			params["*"] = strings.Join(segments[i:], "/")
+			return params, true
+		}
+		if i >= len(segments) {
+			return nil, false
+		}
+		if strings.HasPrefix(pattern, "{") && strings.HasSuffix(pattern, "}") {
+			params[strings.Trim(pattern, "{}")] = segments[i]
+			continue
+		}
+		if pattern != segments[i] {
+			return nil, false
+		}
+	}
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+	return params, true
+}
+
+func (r *route) latency(rng *rand.Rand) time.Duration {
+	if r.maxLatency <= r.minLatency {
+		return r.minLatency
+	}
+	return r.minLatency + time.Duration(rng.Int63n(int64(r.maxLatency-r.minLatency)))
+}
+
+func splitPath(path string) []string {
+	trimmed := strings.Trim(path, "/")
+	if trimmed == "" {
+		return nil
+	}
+	return strings.Split(trimmed, "/")
+}
+
+var templateFuncs = template.FuncMap{
+	"now": func() string { return time.Now().UTC().Format(time.RFC3339) },
+	// randInt is rebound per request to the server's seeded generator.
+	"randInt": func(min, max int) int { return min },
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"default": func(fallback, value any) any {
+		if value == nil || value == "" {
+			return fallback
+		}
+		return value
+	},
+	"json": func(value any) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+}
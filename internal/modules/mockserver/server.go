@@ -0,0 +1,178 @@
+package mockserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"text/template"
+	"time"
+)
+
+const maxRequestBody = 1 << 20
+
+type templateData struct {
+	Method  string
+	Path    string
+	Params  map[string]string
+	Query   map[string]string
+	Headers map[string]string
+	Body    string
+	JSON    any
+}
+
+type server struct {
+	routes  []*route
+	log     io.Writer
+	verbose bool
+
+	mu       sync.Mutex
+	rng      *rand.Rand
+	requests int
+}
+
+func newServer(routes []*route, log io.Writer, verbose bool, seed int64) *server {
+	return &server{
+		routes:  routes,
+		log:     log,
+		verbose: verbose,
+		rng:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	started := time.Now()
+	body, _ := io.ReadAll(io.LimitReader(req.Body, maxRequestBody))
+
+	status, matched := s.respond(w, req, body)
+	s.logRequest(req, body, status, matched, time.Since(started))
+}
+
+func (s *server) respond(w http.ResponseWriter, req *http.Request, body []byte) (int, string) {
+	for _, r := range s.routes {
+		params, ok := r.match(req.Method, req.URL.Path)
+		if !ok {
+			continue
+		}
+		matched := fmt.Sprintf("%s %s", r.Method, r.Path)
+
+		s.mu.Lock()
+		delay := r.latency(s.rng)
+		fail := r.FailureRate > 0 && s.rng.Float64() < r.FailureRate
+		s.mu.Unlock()
+
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-req.Context().Done():
+				return 499, matched
+			}
+		}
+
+		if fail {
+			for key, value := range r.Headers {
+				w.Header().Set(key, value)
+			}
+			failureBody := r.FailureBody
+			if failureBody == "" {
+				failureBody = `{"error":"injected failure"}`
+				w.Header().Set("Content-Type", "application/json")
+			} else if w.Header().Get("Content-Type") == "" {
+				w.Header().Set("Content-Type", "application/json")
+			}
+			w.WriteHeader(r.FailureStatus)
+			_, _ = io.WriteString(w, failureBody)
+			return r.FailureStatus, matched + " (injected failure)"
+		}
+
+		tmpl, err := r.template.Clone()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("mock template error: %v", err), http.StatusInternalServerError)
+			return http.StatusInternalServerError, matched + " (template error)"
+		}
+		tmpl.Funcs(template.FuncMap{"randInt": s.randInt})
+
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, newTemplateData(req, params, body)); err != nil {
+			http.Error(w, fmt.Sprintf("mock template error: %v", err), http.StatusInternalServerError)
+			return http.StatusInternalServerError, matched + " (template error)"
+		}
+		for key, value := range r.Headers {
+			w.Header().Set(key, value)
+		}
+		w.WriteHeader(r.Status)
+		_, _ = w.Write(out.Bytes())
+		return r.Status, matched
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"error":  "no mock route matches this request",
+		"method": req.Method,
+		"path":   req.URL.Path,
+	})
+	return http.StatusNotFound, "no route"
+}
+
+// randInt backs the randInt template function with the server's seeded
+// generator so --seed also reproduces template output.
+func (s *server) randInt(min, max int) int {
+	if max <= min {
+		return min
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return min + s.rng.Intn(max-min)
+}
+
+func newTemplateData(req *http.Request, params map[string]string, body []byte) templateData {
+	data := templateData{
+		Method:  req.Method,
+		Path:    req.URL.Path,
+		Params:  params,
+		Query:   map[string]string{},
+		Headers: map[string]string{},
+		Body:    string(body),
+	}
+	for key, values := range req.URL.Query() {
+		data.Query[key] = values[0]
+	}
+	for key, values := range req.Header {
+		data.Headers[key] = values[0]
+	}
+	if len(body) > 0 {
+		var parsed any
+		if err := json.Unmarshal(body, &parsed); err == nil {
+			data.JSON = parsed
+		}
+	}
+	return data
+}
+
+func (s *server) logRequest(req *http.Request, body []byte, status int, matched string, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+
+	fmt.Fprintf(s.log, "%s %-6s %s -> %d [%s] %s\n",
+		time.Now().Format("15:04:05"),
+		req.Method,
+		req.URL.RequestURI(),
+		status,
+		matched,
+		elapsed.Round(time.Millisecond),
+	)
+	if s.verbose && len(body) > 0 {
+		fmt.Fprintf(s.log, "         body: %s\n", body)
+	}
+}
+
+func (s *server) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
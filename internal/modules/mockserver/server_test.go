@@ -0,0 +1,74 @@
+package mockserver
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testRoutes = `routes:
+  - method: GET
+    path: /users/{id}
+    headers:
+      X-Mock: users
+    body: '{"id":"{{.Params.id}}","score":{{randInt 0 1000000}}}'
+  - path: /flaky
+    headers:
+      Content-Type: text/plain
+      X-Mock: flaky
+    failureRate: 1
+    failureStatus: 503
+    failureBody: try again
+`
+
+func newTestServer(t *testing.T, seed int64) *httptest.Server {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "routes.yaml")
+	if err := os.WriteFile(path, []byte(testRoutes), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	routes, err := loadRoutes(path)
+	if err != nil {
+		t.Fatalf("loadRoutes() error = %v", err)
+	}
+	server := httptest.NewServer(newServer(routes, io.Discard, false, seed))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, url string) (*http.Response, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestRandIntUsesSeed(t *testing.T) {
+	_, first := get(t, newTestServer(t, 7).URL+"/users/42")
+	_, second := get(t, newTestServer(t, 7).URL+"/users/42")
+	_, other := get(t, newTestServer(t, 8).URL+"/users/42")
+
+	if first != second {
+		t.Errorf("same seed produced %q and %q", first, second)
+	}
+	if first == other {
+		t.Errorf("different seeds produced the same body %q", first)
+	}
+}
+
+func TestInjectedFailureKeepsRouteHeaders(t *testing.T) {
+	resp, body := get(t, newTestServer(t, 1).URL+"/flaky")
+	if resp.StatusCode != http.StatusServiceUnavailable || body != "try again" {
+		t.Fatalf("response = %d %q", resp.StatusCode, body)
+	}
+	if resp.Header.Get("X-Mock") != "flaky" || resp.Header.Get("Content-Type") != "text/plain" {
+		t.Errorf("headers = %v, want the route headers", resp.Header)
+	}
+}
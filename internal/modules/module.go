@@ -1,7 +1,7 @@
 package modules
 
 import (
-	"fmt"
+	"context"
 
 	"go-devtools/internal/menu"
 	"go-devtools/internal/requirements"
@@ -10,6 +10,18 @@ import (
 type ActionContext struct {
 	Params      map[string]string
 	Positionals []string
+	// Ctx is cancelled when the user interrupts a CLI run. It is nil for
+	// actions started from the TUI; use Context to read it.
+	Ctx context.Context
+}
+
+// Context returns the action's cancellation context, or
+// context.Background when none was set.
+func (c ActionContext) Context() context.Context {
+	if c.Ctx == nil {
+		return context.Background()
+	}
+	return c.Ctx
 }
 
 type Action struct {
@@ -17,7 +29,11 @@ type Action struct {
 	Label       string
 	Description string
 	Usage       string
-	Run         func(ActionContext) (string, error)
+	Sensitive   bool
+	// SecretOutput keeps the action's output (generated secrets, one-time
+	// codes) out of the history file.
+	SecretOutput bool
+	Run          func(ActionContext) (string, error)
 }
 
 type Tool interface {
@@ -31,6 +47,7 @@ type Tool interface {
 
 func ToMenuItem(tool Tool) menu.Item {
 	return menu.Item{
+		ID:           tool.ID(),
 		Label:        tool.Label(),
 		Description:  tool.Description(),
 		NextMenu:     tool.Menu(),
@@ -64,14 +81,20 @@ func FindAction(tool Tool, actionID string) (Action, bool) {
 	return Action{}, false
 }
 
+// ValidateRequirements runs the tool's checks and, once all of them pass,
+// their export steps.
 func ValidateRequirements(tool Tool) error {
-	for _, check := range tool.Requirements() {
+	checks := tool.Requirements()
+	for _, check := range checks {
 		if err := check.Run(); err != nil {
 			if check.Installer != nil {
-				return fmt.Errorf("%w (installer available: %s)", err, check.Installer.Label)
+				return RequirementError("%w (installer available: %s)", err, check.Installer.Label)
 			}
-			return err
+			return RequirementError("%w", err)
 		}
 	}
+	if err := requirements.ExportAll(checks); err != nil {
+		return RequirementError("%w", err)
+	}
 	return nil
 }
@@ -0,0 +1,514 @@
+package otp
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/requirements"
+)
+
+const (
+	defaultSecretBytes = 20
+	defaultWindow      = 1
+)
+
+type Tool struct{}
+
+func New() modules.Tool {
+	return Tool{}
+}
+
+func (Tool) ID() string { return "otp" }
+
+func (Tool) Label() string { return "OTP" }
+
+func (Tool) Description() string { return "TOTP/HOTP codes for test accounts" }
+
+func (Tool) Requirements() []requirements.Check { return nil }
+
+func (Tool) Actions() []modules.Action {
+	return []modules.Action{
+		{
+			ID:          "add",
+			Label:       "Add account",
+			Description: "Store a secret given as base32 or an otpauth:// URI",
+			Usage:       "devtools run otp add [name] --secret <base32|otpauth-uri> | stdin [--issuer <name>] [--type totp|hotp] [--algorithm SHA1|SHA256|SHA512] [--digits 6] [--period 30] [--counter 0] [--force true]",
+			Run:         addAccount,
+		},
+		{
+			ID:          "list",
+			Label:       "List accounts",
+			Description: "Stored accounts and their parameters",
+			Usage:       "devtools run otp list",
+			Run:         listAccounts,
+		},
+		{
+			ID:           "code",
+			Label:        "Current codes",
+			Description:  "Current code and seconds remaining for one or all accounts",
+			Usage:        "devtools run otp code [name]",
+			SecretOutput: true,
+			Run:          currentCodes,
+		},
+		{
+			ID:           "new",
+			Label:        "New secret",
+			Description:  "Generate a secret and its provisioning URI, and store the account",
+			Usage:        "devtools run otp new <name> [--issuer <name>] [--algorithm SHA1] [--digits 6] [--period 30] [--bytes 20] [--save false] [--force true]",
+			SecretOutput: true,
+			Run:          newAccount,
+		},
+		{
+			ID:          "validate",
+			Label:       "Validate code",
+			Description: "Check a code against an account, allowing for clock drift",
+			Usage:       "devtools run otp validate <name> <code> [--window 1]",
+			Run:         validateCode,
+		},
+		{
+			ID:           "uri",
+			Label:        "Provisioning URI",
+			Description:  "otpauth:// URI of a stored account",
+			Usage:        "devtools run otp uri <name>",
+			SecretOutput: true,
+			Run:          accountURI,
+		},
+		{
+			ID:          "remove",
+			Label:       "Remove account",
+			Description: "Delete a stored account",
+			Usage:       "devtools run otp remove <name>",
+			Run:         removeAccount,
+		},
+	}
+}
+
+func (Tool) Menu() *menu.Menu {
+	return menu.NewBuilder("OTP").
+		Action("Current codes", "Codes for all TOTP accounts", func() (string, error) {
+			return currentCodes(modules.ActionContext{})
+		}).
+		Action("List accounts", "Stored accounts and their parameters", func() (string, error) {
+			return listAccounts(modules.ActionContext{})
+		}).
+		Action("Add account", "Prompt for a name and a base32 secret or otpauth:// URI", func() (string, error) {
+			name, err := cliutil.Prompt("Name (blank to take it from the URI): ")
+			if err != nil {
+				return "", err
+			}
+			secret, err := cliutil.Prompt("Secret or otpauth:// URI: ")
+			if err != nil {
+				return "", err
+			}
+			return addAccount(withName(name, map[string]string{"secret": secret}))
+		}).
+		Action("New secret", "Generate and store a TOTP secret", func() (string, error) {
+			name, err := cliutil.Prompt("Account name: ")
+			if err != nil {
+				return "", err
+			}
+			issuer, err := cliutil.Prompt("Issuer (optional): ")
+			if err != nil {
+				return "", err
+			}
+			return newAccount(withName(name, map[string]string{"issuer": issuer}))
+		}).
+		Action("Validate code", "Check a code with a window of one step", func() (string, error) {
+			name, err := cliutil.Prompt("Account name: ")
+			if err != nil {
+				return "", err
+			}
+			code, err := cliutil.Prompt("Code: ")
+			if err != nil {
+				return "", err
+			}
+			return validateCode(modules.ActionContext{Positionals: []string{name, code}})
+		}).
+		Action("Remove account", "Delete a stored account", func() (string, error) {
+			name, err := cliutil.Prompt("Account name: ")
+			if err != nil {
+				return "", err
+			}
+			return removeAccount(withName(name, nil))
+		}).
+		WithBack().
+		Build()
+}
+
+func withName(name string, params map[string]string) modules.ActionContext {
+	ctx := modules.ActionContext{Params: params}
+	if name != "" {
+		ctx.Positionals = []string{name}
+	}
+	return ctx
+}
+
+func nameArg(ctx modules.ActionContext) (string, error) {
+	if len(ctx.Positionals) == 0 || strings.TrimSpace(ctx.Positionals[0]) == "" {
+		return "", modules.UsageError("missing account name")
+	}
+	return strings.TrimSpace(ctx.Positionals[0]), nil
+}
+
+// readSecret takes --secret or the first line of piped stdin, which keeps the
+// secret out of shell history.
+func readSecret(ctx modules.ActionContext) (string, error) {
+	if secret := strings.TrimSpace(ctx.Params["secret"]); secret != "" {
+		return secret, nil
+	}
+	if stdinPiped() {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("failed to read stdin: %w", err)
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			return line, nil
+		}
+	}
+	return "", modules.UsageError("missing --secret (base32 or otpauth:// URI)")
+}
+
+func addAccount(ctx modules.ActionContext) (string, error) {
+	secret, err := readSecret(ctx)
+	if err != nil {
+		return "", err
+	}
+	a := account{Type: typeTOTP, Algorithm: defaultAlgorithm, Digits: defaultDigits, Period: defaultPeriod}
+	if strings.HasPrefix(strings.ToLower(secret), "otpauth:") {
+		if a, err = parseURI(secret); err != nil {
+			return "", err
+		}
+	} else {
+		a.Secret = secret
+	}
+	if err := applyOptions(ctx, &a); err != nil {
+		return "", err
+	}
+	if len(ctx.Positionals) > 0 {
+		a.Name = strings.TrimSpace(ctx.Positionals[0])
+	}
+	if a.Name == "" {
+		return "", modules.UsageError("missing account name")
+	}
+	if a.Secret, err = normalizeSecret(a.Secret); err != nil {
+		return "", err
+	}
+	if err := a.validate(); err != nil {
+		return "", err
+	}
+
+	s, err := loadStore(ctx)
+	if err != nil {
+		return "", err
+	}
+	if err := s.put(a, ctx.Params["force"] == "true"); err != nil {
+		return "", err
+	}
+	if err := s.save(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Stored %s account %q (%s, %d digits).", strings.ToUpper(a.Type), a.Name, a.Algorithm, a.Digits), nil
+}
+
+// applyOptions overrides account parameters with explicit flags.
+func applyOptions(ctx modules.ActionContext, a *account) error {
+	if issuer := ctx.Params["issuer"]; issuer != "" {
+		a.Issuer = issuer
+	}
+	if kind := strings.ToLower(ctx.Params["type"]); kind != "" {
+		a.Type = kind
+		if kind == typeHOTP {
+			a.Period = 0
+		}
+	}
+	if algorithm := ctx.Params["algorithm"]; algorithm != "" {
+		a.Algorithm = strings.ToUpper(strings.ReplaceAll(algorithm, "-", ""))
+	}
+	var err error
+	if a.Digits, err = intParam(ctx, "digits", a.Digits, 6, 10); err != nil {
+		return err
+	}
+	if a.Type == typeTOTP {
+		if a.Period, err = intParam(ctx, "period", a.Period, 1, 3600); err != nil {
+			return err
+		}
+	}
+	if raw := ctx.Params["counter"]; raw != "" {
+		if a.Counter, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			return modules.UsageError("--counter must be a non-negative number")
+		}
+	}
+	return nil
+}
+
+func listAccounts(ctx modules.ActionContext) (string, error) {
+	s, err := loadStore(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(s.Accounts) == 0 {
+		return "No OTP accounts stored (see otp add or otp new).", nil
+	}
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tISSUER\tTYPE\tALGORITHM\tDIGITS\tPERIOD/COUNTER")
+	for _, a := range s.Accounts {
+		interval := fmt.Sprintf("%ds", a.Period)
+		if a.Type == typeHOTP {
+			interval = strconv.FormatUint(a.Counter, 10)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", a.Name, cliutil.Dash(a.Issuer), a.Type, a.Algorithm, a.Digits, interval)
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// currentCodes prints TOTP codes for every account, or the code of a single
+// account. Asking for an HOTP account by name consumes its counter.
+func currentCodes(ctx modules.ActionContext) (string, error) {
+	s, err := loadStore(ctx)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	if len(ctx.Positionals) > 0 {
+		a, err := s.get(ctx.Positionals[0])
+		if err != nil {
+			return "", err
+		}
+		if a.Type == typeHOTP {
+			code, err := a.hotp(a.Counter)
+			if err != nil {
+				return "", err
+			}
+			used := a.Counter
+			a.Counter++
+			if err := s.save(); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s (counter %d)", code, used), nil
+		}
+		code, err := a.hotp(a.step(now))
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s (%ds remaining)", code, a.remaining(now)), nil
+	}
+
+	if len(s.Accounts) == 0 {
+		return "No OTP accounts stored (see otp add or otp new).", nil
+	}
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCODE\tREMAINING")
+	for _, a := range s.Accounts {
+		if a.Type == typeHOTP {
+			fmt.Fprintf(w, "%s\t-\thotp: run otp code %s\n", a.Name, a.Name)
+			continue
+		}
+		code, err := a.hotp(a.step(now))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(w, "%s\t%s\t%ds\n", a.Name, code, a.remaining(now))
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func newAccount(ctx modules.ActionContext) (string, error) {
+	name, err := nameArg(ctx)
+	if err != nil {
+		return "", err
+	}
+	size, err := intParam(ctx, "bytes", defaultSecretBytes, 10, 64)
+	if err != nil {
+		return "", err
+	}
+	raw := make([]byte, size)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+	a := account{
+		Name:      name,
+		Secret:    base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw),
+		Type:      typeTOTP,
+		Algorithm: defaultAlgorithm,
+		Digits:    defaultDigits,
+		Period:    defaultPeriod,
+	}
+	if err := applyOptions(ctx, &a); err != nil {
+		return "", err
+	}
+	if err := a.validate(); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Secret: %s\nURI:    %s\n", a.Secret, a.uri())
+	if ctx.Params["save"] == "false" {
+		return strings.TrimRight(b.String(), "\n"), nil
+	}
+	s, err := loadStore(ctx)
+	if err != nil {
+		return "", err
+	}
+	if err := s.put(a, ctx.Params["force"] == "true"); err != nil {
+		return "", err
+	}
+	if err := s.save(); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&b, "Stored as %q in %s", a.Name, s.path)
+	return b.String(), nil
+}
+
+// validateCode accepts codes up to --window steps either side of now for
+// TOTP, or up to --window counters ahead for HOTP, which then resyncs.
+func validateCode(ctx modules.ActionContext) (string, error) {
+	if len(ctx.Positionals) < 2 {
+		return "", modules.UsageError("usage: validate <name> <code>")
+	}
+	window, err := intParam(ctx, "window", defaultWindow, 0, 10)
+	if err != nil {
+		return "", err
+	}
+	s, err := loadStore(ctx)
+	if err != nil {
+		return "", err
+	}
+	a, err := s.get(ctx.Positionals[0])
+	if err != nil {
+		return "", err
+	}
+	code := strings.ReplaceAll(strings.TrimSpace(ctx.Positionals[1]), " ", "")
+	if len(code) != a.Digits {
+		return "", fmt.Errorf("code must have %d digits", a.Digits)
+	}
+
+	if a.Type == typeHOTP {
+		for offset := 0; offset <= window; offset++ {
+			ok, err := matches(*a, a.Counter+uint64(offset), code)
+			if err != nil {
+				return "", err
+			}
+			if ok {
+				used := a.Counter + uint64(offset)
+				a.Counter = used + 1
+				if err := s.save(); err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("Code is valid (counter %d); next counter is %d.", used, used+1), nil
+			}
+		}
+		return "", fmt.Errorf("code is not valid for counters %d-%d", a.Counter, a.Counter+uint64(window))
+	}
+
+	current := a.step(time.Now())
+	for _, offset := range windowOffsets(window) {
+		if offset < 0 && uint64(-offset) > current {
+			continue
+		}
+		ok, err := matches(*a, uint64(int64(current)+int64(offset)), code)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return "Code is valid (" + describeOffset(offset) + ").", nil
+		}
+	}
+	return "", fmt.Errorf("code is not valid within ±%d steps of %ds", window, a.Period)
+}
+
+// windowOffsets orders offsets by distance from the current step.
+func windowOffsets(window int) []int {
+	offsets := []int{0}
+	for i := 1; i <= window; i++ {
+		offsets = append(offsets, -i, i)
+	}
+	return offsets
+}
+
+func matches(a account, counter uint64, code string) (bool, error) {
+	expected, err := a.hotp(counter)
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1, nil
+}
+
+func describeOffset(offset int) string {
+	switch {
+	case offset == 0:
+		return "current step"
+	case offset < 0:
+		return fmt.Sprintf("%d step(s) behind", -offset)
+	}
+	return fmt.Sprintf("%d step(s) ahead", offset)
+}
+
+func accountURI(ctx modules.ActionContext) (string, error) {
+	name, err := nameArg(ctx)
+	if err != nil {
+		return "", err
+	}
+	s, err := loadStore(ctx)
+	if err != nil {
+		return "", err
+	}
+	a, err := s.get(name)
+	if err != nil {
+		return "", err
+	}
+	return a.uri(), nil
+}
+
+func removeAccount(ctx modules.ActionContext) (string, error) {
+	name, err := nameArg(ctx)
+	if err != nil {
+		return "", err
+	}
+	s, err := loadStore(ctx)
+	if err != nil {
+		return "", err
+	}
+	i, ok := s.find(name)
+	if !ok {
+		return "", modules.NotFoundError("unknown OTP account %q (see otp list)", name)
+	}
+	removed := s.Accounts[i].Name
+	s.Accounts = append(s.Accounts[:i], s.Accounts[i+1:]...)
+	if err := s.save(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Removed %q.", removed), nil
+}
+
+func stdinPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+func intParam(ctx modules.ActionContext, name string, fallback, minimum, maximum int) (int, error) {
+	raw := ctx.Params[name]
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < minimum || value > maximum {
+		return 0, modules.UsageError("--%s must be a number between %d and %d", name, minimum, maximum)
+	}
+	return value, nil
+}
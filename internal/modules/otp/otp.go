@@ -0,0 +1,183 @@
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/modules"
+)
+
+const (
+	typeTOTP = "totp"
+	typeHOTP = "hotp"
+
+	defaultDigits    = 6
+	defaultPeriod    = 30
+	defaultAlgorithm = "SHA1"
+)
+
+type account struct {
+	Name      string `json:"name"`
+	Issuer    string `json:"issuer,omitempty"`
+	Secret    string `json:"secret"`
+	Type      string `json:"type"`
+	Algorithm string `json:"algorithm"`
+	Digits    int    `json:"digits"`
+	Period    int    `json:"period,omitempty"`
+	Counter   uint64 `json:"counter,omitempty"`
+}
+
+// normalizeSecret accepts the grouped, lower-case and unpadded forms that
+// sites display and returns canonical unpadded base32.
+func normalizeSecret(secret string) (string, error) {
+	cleaned := strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "=", "").Replace(secret))
+	if cleaned == "" {
+		return "", modules.UsageError("secret is empty")
+	}
+	if _, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(cleaned); err != nil {
+		return "", modules.UsageError("secret is not valid base32: %v", err)
+	}
+	return cleaned, nil
+}
+
+func (a account) key() ([]byte, error) {
+	return base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(a.Secret)
+}
+
+func (a account) hasher() (func() hash.Hash, error) {
+	switch strings.ToUpper(a.Algorithm) {
+	case "", "SHA1":
+		return sha1.New, nil
+	case "SHA256":
+		return sha256.New, nil
+	case "SHA512":
+		return sha512.New, nil
+	}
+	return nil, modules.UsageError("unsupported algorithm %q (use SHA1, SHA256 or SHA512)", a.Algorithm)
+}
+
+func (a account) validate() error {
+	if a.Type != typeTOTP && a.Type != typeHOTP {
+		return modules.UsageError("type must be totp or hotp")
+	}
+	if a.Digits < 6 || a.Digits > 10 {
+		return modules.UsageError("digits must be between 6 and 10")
+	}
+	if a.Type == typeTOTP && (a.Period < 1 || a.Period > 3600) {
+		return modules.UsageError("period must be between 1 and 3600 seconds")
+	}
+	if _, err := a.hasher(); err != nil {
+		return err
+	}
+	_, err := normalizeSecret(a.Secret)
+	return err
+}
+
+// hotp implements RFC 4226 dynamic truncation.
+func (a account) hotp(counter uint64) (string, error) {
+	key, err := a.key()
+	if err != nil {
+		return "", fmt.Errorf("stored secret for %s is invalid: %w", a.Name, err)
+	}
+	hasher, err := a.hasher()
+	if err != nil {
+		return "", err
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(hasher, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	modulus := uint64(1)
+	for i := 0; i < a.Digits; i++ {
+		modulus *= 10
+	}
+	return fmt.Sprintf("%0*d", a.Digits, uint64(value)%modulus), nil
+}
+
+func (a account) step(at time.Time) uint64 {
+	return uint64(at.Unix()) / uint64(a.Period)
+}
+
+func (a account) remaining(at time.Time) int {
+	return a.Period - int(at.Unix()%int64(a.Period))
+}
+
+func (a account) label() string {
+	if a.Issuer != "" && !strings.HasPrefix(a.Name, a.Issuer+":") {
+		return a.Issuer + ":" + a.Name
+	}
+	return a.Name
+}
+
+// uri builds the Key Uri Format understood by authenticator apps.
+func (a account) uri() string {
+	query := url.Values{}
+	query.Set("secret", a.Secret)
+	if a.Issuer != "" {
+		query.Set("issuer", a.Issuer)
+	}
+	query.Set("algorithm", strings.ToUpper(a.Algorithm))
+	query.Set("digits", strconv.Itoa(a.Digits))
+	if a.Type == typeHOTP {
+		query.Set("counter", strconv.FormatUint(a.Counter, 10))
+	} else {
+		query.Set("period", strconv.Itoa(a.Period))
+	}
+	u := url.URL{Scheme: "otpauth", Host: a.Type, Path: "/" + a.label(), RawQuery: query.Encode()}
+	return u.String()
+}
+
+func parseURI(raw string) (account, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Scheme != "otpauth" {
+		return account{}, modules.UsageError("not an otpauth:// URI")
+	}
+	query := u.Query()
+	a := account{
+		Type:      strings.ToLower(u.Host),
+		Secret:    query.Get("secret"),
+		Issuer:    query.Get("issuer"),
+		Algorithm: strings.ToUpper(cliutil.FirstNonEmpty(query.Get("algorithm"), defaultAlgorithm)),
+		Digits:    defaultDigits,
+		Period:    defaultPeriod,
+	}
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, name, ok := strings.Cut(label, ":"); ok {
+		a.Issuer = cliutil.FirstNonEmpty(a.Issuer, strings.TrimSpace(issuer))
+		label = strings.TrimSpace(name)
+	}
+	a.Name = label
+	if raw := query.Get("digits"); raw != "" {
+		if a.Digits, err = strconv.Atoi(raw); err != nil {
+			return account{}, modules.UsageError("invalid digits %q in URI", raw)
+		}
+	}
+	if raw := query.Get("period"); raw != "" {
+		if a.Period, err = strconv.Atoi(raw); err != nil {
+			return account{}, modules.UsageError("invalid period %q in URI", raw)
+		}
+	}
+	if raw := query.Get("counter"); raw != "" {
+		if a.Counter, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			return account{}, modules.UsageError("invalid counter %q in URI", raw)
+		}
+	}
+	if a.Type == typeHOTP {
+		a.Period = 0
+	}
+	return a, nil
+}
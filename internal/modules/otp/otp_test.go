@@ -0,0 +1,60 @@
+package otp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-devtools/internal/modules"
+)
+
+// RFC 4226 appendix D and RFC 6238 appendix B use the ASCII key
+// "12345678901234567890".
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestHOTPAndTOTPVectors(t *testing.T) {
+	a := account{Name: "rfc", Secret: rfcSecret, Type: typeHOTP, Algorithm: "SHA1", Digits: 6}
+	for counter, want := range []string{"755224", "287082", "359152", "969429"} {
+		if got, err := a.hotp(uint64(counter)); err != nil || got != want {
+			t.Errorf("hotp(%d) = %q, %v, want %q", counter, got, err, want)
+		}
+	}
+
+	a = account{Name: "rfc", Secret: rfcSecret, Type: typeTOTP, Algorithm: "SHA1", Digits: 8, Period: 30}
+	for unix, want := range map[int64]string{59: "94287082", 1111111109: "07081804", 2000000000: "69279037"} {
+		if got, err := a.hotp(a.step(time.Unix(unix, 0))); err != nil || got != want {
+			t.Errorf("totp(%d) = %q, %v, want %q", unix, got, err, want)
+		}
+	}
+}
+
+func TestAddFromURIAndValidateHOTP(t *testing.T) {
+	storeFile := filepath.Join(t.TempDir(), "otp.json")
+	uri := "otpauth://hotp/ACME:alice@example.com?secret=gezd+gnbv+gy3t+qojq+gezd+gnbv+gy3t+qojq&issuer=ACME&counter=0"
+	out, err := addAccount(modules.ActionContext{Params: map[string]string{"store": storeFile, "secret": uri}})
+	if err != nil {
+		t.Fatalf("add error = %v", err)
+	}
+	if !strings.Contains(out, `HOTP account "alice@example.com"`) {
+		t.Errorf("add output = %q", out)
+	}
+	if info, err := os.Stat(storeFile); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("store mode = %v, %v, want 0600", info, err)
+	}
+
+	// Counter 2 lies inside a two-step look-ahead window and resyncs to 3.
+	ctx := modules.ActionContext{Params: map[string]string{"store": storeFile, "window": "2"}, Positionals: []string{"alice@example.com", "359152"}}
+	if out, err := validateCode(ctx); err != nil || !strings.Contains(out, "next counter is 3") {
+		t.Errorf("validate = %q, %v", out, err)
+	}
+	if _, err := validateCode(ctx); err == nil {
+		t.Error("validate accepted a code that was already used")
+	}
+
+	out, err = currentCodes(modules.ActionContext{Params: map[string]string{"store": storeFile}, Positionals: []string{"alice@example.com"}})
+	if err != nil || out != "969429 (counter 3)" {
+		t.Errorf("code = %q, %v", out, err)
+	}
+}
@@ -0,0 +1,84 @@
+package otp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go-devtools/internal/appdir"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/safefile"
+)
+
+const storeFile = "otp-accounts.json"
+
+type store struct {
+	path     string
+	Accounts []account `json:"accounts"`
+}
+
+func storePath(ctx modules.ActionContext) (string, error) {
+	if path := ctx.Params["store"]; path != "" {
+		return path, nil
+	}
+	return appdir.Path(storeFile)
+}
+
+func loadStore(ctx modules.ActionContext) (*store, error) {
+	path, err := storePath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s := &store{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read OTP accounts: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse OTP accounts file %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *store) save() error {
+	sort.Slice(s.Accounts, func(i, j int) bool { return strings.ToLower(s.Accounts[i].Name) < strings.ToLower(s.Accounts[j].Name) })
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode OTP accounts: %w", err)
+	}
+	return safefile.Write(s.path, append(data, '\n'))
+}
+
+func (s *store) find(name string) (int, bool) {
+	for i, a := range s.Accounts {
+		if strings.EqualFold(a.Name, name) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (s *store) get(name string) (*account, error) {
+	i, ok := s.find(name)
+	if !ok {
+		return nil, modules.NotFoundError("unknown OTP account %q (see otp list)", name)
+	}
+	return &s.Accounts[i], nil
+}
+
+func (s *store) put(a account, replace bool) error {
+	if i, ok := s.find(a.Name); ok {
+		if !replace {
+			return modules.UsageError("account %q already exists (use --force true to replace it)", a.Name)
+		}
+		s.Accounts[i] = a
+		return nil
+	}
+	s.Accounts = append(s.Accounts, a)
+	return nil
+}
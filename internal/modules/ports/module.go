@@ -0,0 +1,303 @@
+package ports
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/requirements"
+)
+
+// killWait is how long kill waits for a process to exit after SIGTERM.
+const killWait = 5 * time.Second
+
+type Tool struct{}
+
+func New() modules.Tool {
+	return Tool{}
+}
+
+func (Tool) ID() string { return "ports" }
+
+func (Tool) Label() string { return "Ports" }
+
+func (Tool) Description() string { return "Listening sockets and the processes behind them" }
+
+func (Tool) Requirements() []requirements.Check {
+	return []requirements.Check{{
+		Name: "/proc/net (Linux)",
+		Validate: func() error {
+			if runtime.GOOS != "linux" {
+				return fmt.Errorf("the ports module reads /proc/net and only works on Linux")
+			}
+			if _, err := os.Stat(filepath.Join(procRoot, "net", "tcp")); err != nil {
+				return fmt.Errorf("cannot read /proc/net/tcp: %w", err)
+			}
+			return nil
+		},
+	}}
+}
+
+func (Tool) Actions() []modules.Action {
+	return []modules.Action{
+		{
+			ID:          "list",
+			Label:       "Listening ports",
+			Description: "Listening TCP and bound UDP sockets with PID and command",
+			Usage:       "devtools run ports list [--proto tcp|udp|all]",
+			Run:         listPorts,
+		},
+		{
+			ID:          "who",
+			Label:       "What is using a port",
+			Description: "Processes listening on a port, with their command lines",
+			Usage:       "devtools run ports who <port> [--proto tcp|udp|all]",
+			Run:         whoUsesPort,
+		},
+		{
+			ID:          "kill",
+			Label:       "Free a port",
+			Description: "Signal the processes listening on a port after confirmation",
+			Usage:       "devtools run ports kill <port> [--proto tcp|udp|all] [--signal TERM|KILL] [--yes true]",
+			Run:         killPort,
+		},
+	}
+}
+
+func (Tool) Menu() *menu.Menu {
+	return menu.NewBuilder("Ports").
+		Action("Listening ports", "TCP and UDP", func() (string, error) {
+			return listPorts(modules.ActionContext{})
+		}).
+		Action("What is using a port", "Prompt for a port number", func() (string, error) {
+			port, err := cliutil.Prompt("Port: ")
+			if err != nil {
+				return "", err
+			}
+			return whoUsesPort(modules.ActionContext{Positionals: []string{port}})
+		}).
+		Action("Free a port", "Prompt for a port, confirm, then send SIGTERM", func() (string, error) {
+			port, err := cliutil.Prompt("Port: ")
+			if err != nil {
+				return "", err
+			}
+			return killPort(modules.ActionContext{Positionals: []string{port}})
+		}).
+		WithBack().
+		Build()
+}
+
+func protocols(ctx modules.ActionContext) ([]string, error) {
+	switch strings.ToLower(ctx.Params["proto"]) {
+	case "", "all":
+		return []string{"tcp", "udp"}, nil
+	case "tcp":
+		return []string{"tcp"}, nil
+	case "udp":
+		return []string{"udp"}, nil
+	}
+	return nil, modules.UsageError("--proto must be tcp, udp or all")
+}
+
+func portArg(ctx modules.ActionContext) (int, error) {
+	if len(ctx.Positionals) == 0 {
+		return 0, modules.UsageError("missing port")
+	}
+	port, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(ctx.Positionals[0]), ":"))
+	if err != nil || port < 1 || port > 65535 {
+		return 0, modules.UsageError("invalid port %q", ctx.Positionals[0])
+	}
+	return port, nil
+}
+
+// socketsOnPort returns the sockets bound to port; a missing listener is a
+// NotFoundError so scripts can check the exit status.
+func socketsOnPort(ctx modules.ActionContext) (int, []socket, error) {
+	port, err := portArg(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	protos, err := protocols(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	all, err := listeningSockets(protos)
+	if err != nil {
+		return 0, nil, err
+	}
+	var matched []socket
+	for _, s := range all {
+		if s.Port == port {
+			matched = append(matched, s)
+		}
+	}
+	if len(matched) == 0 {
+		return port, nil, modules.NotFoundError("nothing is listening on port %d", port)
+	}
+	return port, matched, nil
+}
+
+func listPorts(ctx modules.ActionContext) (string, error) {
+	protos, err := protocols(ctx)
+	if err != nil {
+		return "", err
+	}
+	sockets, err := listeningSockets(protos)
+	if err != nil {
+		return "", err
+	}
+	if len(sockets) == 0 {
+		return "No listening sockets.", nil
+	}
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROTO\tADDRESS\tPORT\tUSER\tPID\tCOMMAND")
+	hidden := false
+	for _, s := range sockets {
+		pids, commands := "-", "-"
+		if len(s.PIDs) > 0 {
+			var ids, names []string
+			for _, pid := range s.PIDs {
+				ids = append(ids, strconv.Itoa(pid))
+				names = append(names, lookupProcess(pid).Command)
+			}
+			pids, commands = strings.Join(ids, ","), strings.Join(names, ",")
+		} else {
+			hidden = true
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", s.Proto, s.address(), s.Port, userName(s.UID), pids, commands)
+	}
+	w.Flush()
+	if hidden {
+		b.WriteString("\nPID - means the socket belongs to another user's process; run with sudo to see it.")
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func whoUsesPort(ctx modules.ActionContext) (string, error) {
+	port, sockets, err := socketsOnPort(ctx)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, s := range sockets {
+		fmt.Fprintf(&b, "%s %s:%d (user %s)\n", s.Proto, s.address(), port, userName(s.UID))
+		if len(s.PIDs) == 0 {
+			b.WriteString("  process not visible; run with sudo to see it\n")
+		}
+		for _, pid := range s.PIDs {
+			p := lookupProcess(pid)
+			fmt.Fprintf(&b, "  PID %d  %s\n", p.PID, p.Command)
+			if p.Args != "" {
+				fmt.Fprintf(&b, "    %s\n", p.Args)
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func killPort(ctx modules.ActionContext) (string, error) {
+	sig, sigName, err := signalParam(ctx)
+	if err != nil {
+		return "", err
+	}
+	port, sockets, err := socketsOnPort(ctx)
+	if err != nil {
+		return "", err
+	}
+	pids := ownerPIDs(sockets)
+	if len(pids) == 0 {
+		return "", fmt.Errorf("port %d is held by a process of user %s that is not visible; run with sudo", port, userName(sockets[0].UID))
+	}
+
+	var targets []string
+	for _, pid := range pids {
+		if pid == 1 || pid == os.Getpid() {
+			return "", fmt.Errorf("refusing to signal PID %d", pid)
+		}
+		targets = append(targets, fmt.Sprintf("%d (%s)", pid, lookupProcess(pid).Command))
+	}
+	question := fmt.Sprintf("Send SIG%s to %s listening on port %d?", sigName, strings.Join(targets, ", "), port)
+	if err := confirm(ctx, question); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, pid := range pids {
+		proc, err := os.FindProcess(pid)
+		if err == nil {
+			err = proc.Signal(sig)
+		}
+		if err != nil {
+			return b.String(), fmt.Errorf("failed to signal PID %d: %w", pid, err)
+		}
+		fmt.Fprintf(&b, "Sent SIG%s to PID %d.\n", sigName, pid)
+	}
+
+	deadline := time.Now().Add(killWait)
+	for time.Now().Before(deadline) {
+		if _, _, err := socketsOnPort(ctx); modules.KindOf(err) == modules.KindNotFound {
+			fmt.Fprintf(&b, "Port %d is free.", port)
+			return b.String(), nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return strings.TrimRight(b.String(), "\n"), fmt.Errorf("process still running after %s; retry with --signal KILL", killWait)
+}
+
+func signalParam(ctx modules.ActionContext) (syscall.Signal, string, error) {
+	switch strings.TrimPrefix(strings.ToUpper(ctx.Params["signal"]), "SIG") {
+	case "", "TERM", "15":
+		return syscall.SIGTERM, "TERM", nil
+	case "KILL", "9":
+		return syscall.SIGKILL, "KILL", nil
+	case "INT", "2":
+		return syscall.SIGINT, "INT", nil
+	}
+	return 0, "", modules.UsageError("--signal must be TERM, KILL or INT")
+}
+
+func ownerPIDs(sockets []socket) []int {
+	seen := map[int]bool{}
+	var pids []int
+	for _, s := range sockets {
+		for _, pid := range s.PIDs {
+			if !seen[pid] {
+				seen[pid] = true
+				pids = append(pids, pid)
+			}
+		}
+	}
+	sort.Ints(pids)
+	return pids
+}
+
+// confirm accepts --yes true, otherwise asks on an interactive terminal and
+// refuses when stdin is not one.
+func confirm(ctx modules.ActionContext, question string) error {
+	if ctx.Params["yes"] == "true" {
+		return nil
+	}
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return modules.UsageError("%s Pass --yes true to confirm", question)
+	}
+	answer, err := cliutil.Prompt(question + " [y/N] ")
+	if err != nil {
+		return modules.UsageError("no answer given; pass --yes true to confirm")
+	}
+	if answer = strings.ToLower(answer); answer != "y" && answer != "yes" {
+		return modules.CancelledError("cancelled")
+	}
+	return nil
+}
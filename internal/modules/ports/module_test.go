@@ -0,0 +1,110 @@
+package ports
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go-devtools/internal/modules"
+)
+
+const procNetHeader = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+
+// fakeProc builds a /proc tree in which pid holds a TCP listener on port
+// 8080 (0x1F90) and points procRoot at it.
+func fakeProc(t *testing.T, pid int) string {
+	t.Helper()
+	root := t.TempDir()
+	fdDir := filepath.Join(root, strconv.Itoa(pid), "fd")
+	if err := os.MkdirAll(fdDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("socket:[555]", filepath.Join(fdDir, "3")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, strconv.Itoa(pid), "comm"), []byte("helper\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "net"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	line := "   0: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 555 1 0000000000000000 100 0 0 10 0\n"
+	if err := os.WriteFile(filepath.Join(root, "net", "tcp"), []byte(procNetHeader+line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	old := procRoot
+	procRoot = root
+	t.Cleanup(func() { procRoot = old })
+	return root
+}
+
+func TestKillPortSignalsOwnerAndWaitsForPort(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=TestHelperProcess")
+	cmd.Env = append(os.Environ(), "PORTS_HELPER=sleep")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	root := fakeProc(t, cmd.Process.Pid)
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		cmd.Wait()
+		// The socket goes away with the process, as it would in /proc.
+		os.WriteFile(filepath.Join(root, "net", "tcp"), []byte(procNetHeader), 0o644)
+	}()
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		<-exited
+	})
+
+	out, err := killPort(modules.ActionContext{
+		Positionals: []string{"8080"},
+		Params:      map[string]string{"proto": "tcp", "yes": "true"},
+	})
+	if err != nil {
+		t.Fatalf("killPort() error = %v\n%s", err, out)
+	}
+	want := "Sent SIGTERM to PID " + strconv.Itoa(cmd.Process.Pid) + ".\nPort 8080 is free."
+	if out != want {
+		t.Errorf("killPort() = %q, want %q", out, want)
+	}
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Error("helper process still running")
+	}
+}
+
+func TestKillPortRefusesInvisibleOwner(t *testing.T) {
+	fakeProc(t, 4242)
+	os.RemoveAll(filepath.Join(procRoot, "4242"))
+
+	_, err := killPort(modules.ActionContext{
+		Positionals: []string{"8080"},
+		Params:      map[string]string{"yes": "true"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "not visible") {
+		t.Errorf("killPort() error = %v, want the not visible error", err)
+	}
+	_, err = killPort(modules.ActionContext{
+		Positionals: []string{"9090"},
+		Params:      map[string]string{"yes": "true"},
+	})
+	if modules.KindOf(err) != modules.KindNotFound {
+		t.Errorf("killPort(9090) error = %v, want not found", err)
+	}
+}
+
+// TestHelperProcess is the process killed by TestKillPortSignalsOwnerAndWaitsForPort.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("PORTS_HELPER") != "sleep" {
+		return
+	}
+	time.Sleep(time.Minute)
+	os.Exit(0)
+}
@@ -0,0 +1,195 @@
+package ports
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// procRoot is a variable so tests can point it at a fake tree.
+var procRoot = "/proc"
+
+// tcpListen is the st column value of a listening socket in /proc/net/tcp.
+const tcpListen = "0A"
+
+type socket struct {
+	Proto string
+	Addr  net.IP
+	Port  int
+	UID   int
+	Inode string
+	PIDs  []int
+}
+
+type process struct {
+	PID     int
+	Command string
+	Args    string
+}
+
+func (s socket) address() string {
+	if s.Addr.IsUnspecified() {
+		if s.Addr.To4() != nil {
+			return "0.0.0.0"
+		}
+		return "[::]"
+	}
+	if s.Addr.To4() == nil {
+		return "[" + s.Addr.String() + "]"
+	}
+	return s.Addr.String()
+}
+
+// listeningSockets reads listening TCP sockets and unconnected UDP sockets
+// for the requested protocols and attaches the PIDs that hold them open.
+func listeningSockets(protos []string) ([]socket, error) {
+	var sockets []socket
+	for _, proto := range protos {
+		for _, file := range []string{proto, proto + "6"} {
+			found, err := readProcNet(filepath.Join(procRoot, "net", file), file)
+			if err != nil {
+				return nil, err
+			}
+			sockets = append(sockets, found...)
+		}
+	}
+	owners := socketOwners()
+	for i := range sockets {
+		sockets[i].PIDs = owners[sockets[i].Inode]
+	}
+	sort.SliceStable(sockets, func(i, j int) bool {
+		if sockets[i].Port != sockets[j].Port {
+			return sockets[i].Port < sockets[j].Port
+		}
+		return sockets[i].Proto < sockets[j].Proto
+	})
+	return sockets, nil
+}
+
+func readProcNet(path, proto string) ([]socket, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// IPv6 may be disabled; the IPv4 table always exists on Linux.
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var sockets []socket
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		local, remote, state := fields[1], fields[2], fields[3]
+		if strings.HasPrefix(proto, "tcp") && state != tcpListen {
+			continue
+		}
+		if strings.HasPrefix(proto, "udp") && !strings.HasSuffix(remote, ":0000") {
+			continue
+		}
+		ip, port, err := parseHexAddr(local)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected address %q in %s: %w", local, path, err)
+		}
+		uid, _ := strconv.Atoi(fields[7])
+		sockets = append(sockets, socket{
+			Proto: proto,
+			Addr:  ip,
+			Port:  port,
+			UID:   uid,
+			Inode: fields[9],
+		})
+	}
+	return sockets, scanner.Err()
+}
+
+// parseHexAddr decodes "0100007F:1F90". The address is stored as 32-bit
+// words in host (little-endian) order, the port in network order.
+func parseHexAddr(value string) (net.IP, int, error) {
+	rawIP, rawPort, ok := strings.Cut(value, ":")
+	if !ok {
+		return nil, 0, fmt.Errorf("missing port")
+	}
+	port, err := strconv.ParseUint(rawPort, 16, 16)
+	if err != nil {
+		return nil, 0, err
+	}
+	b, err := hex.DecodeString(rawIP)
+	if err != nil || (len(b) != net.IPv4len && len(b) != net.IPv6len) {
+		return nil, 0, fmt.Errorf("invalid address")
+	}
+	for i := 0; i < len(b); i += 4 {
+		b[i], b[i+1], b[i+2], b[i+3] = b[i+3], b[i+2], b[i+1], b[i]
+	}
+	return net.IP(b), int(port), nil
+}
+
+// socketOwners maps socket inodes to the PIDs holding them. Without root,
+// only the current user's processes can be inspected.
+func socketOwners() map[string][]int {
+	owners := map[string][]int{}
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return owners
+	}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join(procRoot, entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			inode := strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")
+			if pids := owners[inode]; len(pids) == 0 || pids[len(pids)-1] != pid {
+				owners[inode] = append(pids, pid)
+			}
+		}
+	}
+	return owners
+}
+
+func lookupProcess(pid int) process {
+	p := process{PID: pid, Command: "?"}
+	dir := filepath.Join(procRoot, strconv.Itoa(pid))
+	if comm, err := os.ReadFile(filepath.Join(dir, "comm")); err == nil {
+		p.Command = strings.TrimSpace(string(comm))
+	}
+	if cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		p.Args = strings.TrimSpace(strings.ReplaceAll(string(cmdline), "\x00", " "))
+	}
+	return p
+}
+
+var userNames = map[int]string{}
+
+func userName(uid int) string {
+	if name, ok := userNames[uid]; ok {
+		return name
+	}
+	name := strconv.Itoa(uid)
+	if u, err := user.LookupId(name); err == nil {
+		name = u.Username
+	}
+	userNames[uid] = name
+	return name
+}
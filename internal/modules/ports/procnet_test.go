@@ -0,0 +1,57 @@
+package ports
+
+import (
+	"net"
+	"os"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"go-devtools/internal/modules"
+)
+
+func TestReadProcNetKeepsListeningSockets(t *testing.T) {
+	sockets, err := readProcNet("testdata/tcp6", "tcp6")
+	if err != nil {
+		t.Fatalf("readProcNet error = %v", err)
+	}
+	if len(sockets) != 2 {
+		t.Fatalf("got %d sockets, want the 2 listening ones: %+v", len(sockets), sockets)
+	}
+	if got := sockets[0]; got.address() != "[::]" || got.Port != 8080 || got.UID != 1000 || got.Inode != "41234" {
+		t.Errorf("socket 0 = %+v (%s)", got, got.address())
+	}
+	if got := sockets[1]; got.address() != "[::1]" || got.Port != 5432 {
+		t.Errorf("socket 1 = %+v (%s)", got, got.address())
+	}
+
+	ip, port, err := parseHexAddr("0100007F:1F90")
+	if err != nil || ip.String() != "127.0.0.1" || port != 8080 {
+		t.Errorf("parseHexAddr = %v, %d, %v", ip, port, err)
+	}
+}
+
+func TestWhoFindsOwnListener(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("reads /proc")
+	}
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	port := strconv.Itoa(ln.Addr().(*net.TCPAddr).Port)
+
+	_, sockets, err := socketsOnPort(modules.ActionContext{Positionals: []string{port}})
+	if err != nil {
+		t.Fatalf("socketsOnPort error = %v", err)
+	}
+	if len(sockets) != 1 || len(sockets[0].PIDs) != 1 || sockets[0].PIDs[0] != os.Getpid() {
+		t.Errorf("sockets = %+v, want one owned by PID %d", sockets, os.Getpid())
+	}
+
+	ln.Close()
+	if _, err := whoUsesPort(modules.ActionContext{Positionals: []string{port}}); modules.KindOf(err) != modules.KindNotFound {
+		t.Errorf("closed port error = %v, want not found", err)
+	}
+}
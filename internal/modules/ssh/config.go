@@ -0,0 +1,326 @@
+package ssh
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/modules"
+)
+
+const maxIncludeDepth = 16
+
+type configOption struct {
+	Key   string
+	Value string
+	File  string
+	Line  int
+}
+
+type hostBlock struct {
+	Patterns []string
+	Match    string
+	File     string
+	Line     int
+	Options  []configOption
+}
+
+// parseConfig reads an ssh_config file, following Include directives.
+// Options before the first Host line form a block that matches every host.
+func parseConfig(path, sshDir string) ([]hostBlock, error) {
+	blocks := []hostBlock{{Patterns: []string{"*"}, File: path}}
+	if err := parseConfigFile(path, sshDir, &blocks, 0); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+func parseConfigFile(path, sshDir string, blocks *[]hostBlock, depth int) error {
+	if depth > maxIncludeDepth {
+		return fmt.Errorf("too many nested Include directives at %s", path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		key, args := splitConfigLine(scanner.Text())
+		if key == "" {
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "host":
+			*blocks = append(*blocks, hostBlock{Patterns: args, File: path, Line: lineNo})
+		case "match":
+			*blocks = append(*blocks, hostBlock{Match: strings.Join(args, " "), File: path, Line: lineNo})
+		case "include":
+			parent := (*blocks)[len(*blocks)-1]
+			before := len(*blocks)
+			for _, pattern := range args {
+				if err := includeFiles(pattern, sshDir, blocks, depth); err != nil {
+					return err
+				}
+			}
+			// Lines after the Include still belong to the block it appeared in.
+			if len(*blocks) != before {
+				*blocks = append(*blocks, hostBlock{Patterns: parent.Patterns, Match: parent.Match, File: path, Line: lineNo})
+			}
+		default:
+			current := &(*blocks)[len(*blocks)-1]
+			current.Options = append(current.Options, configOption{Key: canonicalKey(key), Value: strings.Join(args, " "), File: path, Line: lineNo})
+		}
+	}
+	return scanner.Err()
+}
+
+// includeFiles resolves relative Include paths against ~/.ssh like ssh does.
+func includeFiles(pattern, sshDir string, blocks *[]hostBlock, depth int) error {
+	pattern = expandHome(pattern)
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(sshDir, pattern)
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid Include pattern %q: %w", pattern, err)
+	}
+	sort.Strings(matches)
+	for _, match := range matches {
+		if err := parseConfigFile(match, sshDir, blocks, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitConfigLine handles "Key value", "Key=value" and double-quoted arguments.
+func splitConfigLine(line string) (string, []string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", nil
+	}
+	end := strings.IndexAny(line, " \t=")
+	if end < 0 {
+		return line, nil
+	}
+	key := line[:end]
+	rest := strings.TrimSpace(line[end:])
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, "="))
+
+	args := make([]string, 0)
+	var current strings.Builder
+	quoted, inArg := false, false
+	for _, r := range rest {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inArg = true
+		case (r == ' ' || r == '\t') && !quoted:
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return key, args
+}
+
+var knownKeys = map[string]string{}
+
+func init() {
+	for _, key := range []string{
+		"HostName", "User", "Port", "IdentityFile", "IdentitiesOnly", "IdentityAgent", "ProxyJump",
+		"ProxyCommand", "ForwardAgent", "LocalForward", "RemoteForward", "DynamicForward",
+		"StrictHostKeyChecking", "UserKnownHostsFile", "ServerAliveInterval", "ServerAliveCountMax",
+		"ControlMaster", "ControlPath", "ControlPersist", "AddKeysToAgent", "UseKeychain",
+		"PubkeyAcceptedAlgorithms", "PubkeyAcceptedKeyTypes", "HostKeyAlgorithms", "Compression",
+		"ConnectTimeout", "LogLevel", "RequestTTY", "RemoteCommand", "SendEnv", "SetEnv",
+		"CertificateFile", "PreferredAuthentications", "PasswordAuthentication", "HashKnownHosts",
+	} {
+		knownKeys[strings.ToLower(key)] = key
+	}
+}
+
+// canonicalKey keeps option names readable since ssh_config keys are case-insensitive.
+func canonicalKey(key string) string {
+	if known, ok := knownKeys[strings.ToLower(key)]; ok {
+		return known
+	}
+	return key
+}
+
+// matchesHost applies ssh's Host pattern rules: any positive match and no
+// negated match.
+func matchesHost(patterns []string, host string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+		ok, err := filepath.Match(strings.ToLower(pattern), strings.ToLower(host))
+		if err != nil || !ok {
+			continue
+		}
+		if negated {
+			return false
+		}
+		matched = true
+	}
+	return matched
+}
+
+// Options that may be given several times and accumulate instead of the
+// first value winning.
+var multiValued = map[string]bool{
+	"IdentityFile": true, "CertificateFile": true, "LocalForward": true,
+	"RemoteForward": true, "DynamicForward": true, "SendEnv": true,
+}
+
+// effectiveOptions resolves the settings for host: the first value obtained
+// for each option wins, except for the options that accumulate.
+func effectiveOptions(blocks []hostBlock, host string) ([]configOption, []string) {
+	resolved := make([]configOption, 0)
+	seen := map[string]bool{}
+	skipped := make([]string, 0)
+	for _, block := range blocks {
+		if block.Match != "" {
+			skipped = append(skipped, fmt.Sprintf("%s:%d Match %s", block.File, block.Line, block.Match))
+			continue
+		}
+		if !matchesHost(block.Patterns, host) {
+			continue
+		}
+		for _, option := range block.Options {
+			key := strings.ToLower(option.Key)
+			if seen[key] && !multiValued[option.Key] {
+				continue
+			}
+			seen[key] = true
+			resolved = append(resolved, option)
+		}
+	}
+	return resolved, skipped
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
+
+func configPath(ctx modules.ActionContext) (string, string, error) {
+	dir, err := sshDir(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	return cliutil.FirstNonEmpty(ctx.Params["config"], filepath.Join(dir, "config")), dir, nil
+}
+
+func loadConfig(ctx modules.ActionContext) ([]hostBlock, string, error) {
+	path, dir, err := configPath(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	blocks, err := parseConfig(path, dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", modules.NotFoundError("%s does not exist", path)
+		}
+		return nil, "", fmt.Errorf("failed to read ssh config: %w", err)
+	}
+	return blocks, path, nil
+}
+
+func listHosts(ctx modules.ActionContext) (string, error) {
+	blocks, path, err := loadConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "HOST\tHOSTNAME\tUSER\tPORT\tIDENTITY\tPROXYJUMP")
+	count := 0
+	for _, block := range blocks[1:] {
+		if block.Match != "" {
+			continue
+		}
+		for _, pattern := range block.Patterns {
+			if strings.ContainsAny(pattern, "*?!") {
+				continue
+			}
+			options, _ := effectiveOptions(blocks, pattern)
+			values := optionMap(options)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", pattern, cliutil.Dash(values["HostName"]), cliutil.Dash(values["User"]),
+				cliutil.Dash(values["Port"]), cliutil.Dash(values["IdentityFile"]), cliutil.Dash(values["ProxyJump"]))
+			count++
+		}
+	}
+	w.Flush()
+	if count == 0 {
+		return fmt.Sprintf("No concrete Host entries in %s.", path), nil
+	}
+	fmt.Fprintf(&b, "\n%d host(s) in %s (wildcard patterns are applied, not listed).", count, path)
+	return b.String(), nil
+}
+
+func optionMap(options []configOption) map[string]string {
+	values := map[string]string{}
+	for _, option := range options {
+		if _, ok := values[option.Key]; !ok {
+			values[option.Key] = option.Value
+		}
+	}
+	return values
+}
+
+func showHost(ctx modules.ActionContext) (string, error) {
+	host := ctx.Params["host"]
+	if host == "" && len(ctx.Positionals) > 0 {
+		host = ctx.Positionals[0]
+	}
+	if host == "" {
+		return "", modules.UsageError("missing host name")
+	}
+	blocks, path, err := loadConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	options, skipped := effectiveOptions(blocks, host)
+	var b strings.Builder
+	fmt.Fprintf(&b, "Effective settings for %s from %s\n\n", host, path)
+	if len(options) == 0 {
+		b.WriteString("No options apply; ssh defaults are used.\n")
+	}
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, option := range options {
+		fmt.Fprintf(w, "  %s\t%s\t(%s:%d)\n", option.Key, option.Value, option.File, option.Line)
+	}
+	w.Flush()
+	if len(skipped) > 0 {
+		b.WriteString("\nMatch blocks are not evaluated:\n")
+		for _, match := range skipped {
+			fmt.Fprintf(&b, "  %s\n", match)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
@@ -0,0 +1,178 @@
+package ssh
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/modules"
+)
+
+var testSSHDir = filepath.Join("testdata")
+
+func testConfigContext(params map[string]string) modules.ActionContext {
+	merged := map[string]string{"ssh-dir": testSSHDir, "config": filepath.Join(testSSHDir, "config")}
+	for key, value := range params {
+		merged[key] = value
+	}
+	return modules.ActionContext{Params: merged}
+}
+
+func TestSplitConfigLine(t *testing.T) {
+	tests := []struct {
+		line string
+		key  string
+		args []string
+	}{
+		{line: "Host web-1 web-2", key: "Host", args: []string{"web-1", "web-2"}},
+		{line: "  HostName=10.0.0.1", key: "HostName", args: []string{"10.0.0.1"}},
+		{line: "ServerAliveInterval = 30", key: "ServerAliveInterval", args: []string{"30"}},
+		{line: "Port=\"2200\"", key: "Port", args: []string{"2200"}},
+		{line: `IdentityFile "~/.ssh/My Keys/id"`, key: "IdentityFile", args: []string{"~/.ssh/My Keys/id"}},
+		{line: "ProxyCommand\tssh -W %h:%p \"jump host\"", key: "ProxyCommand", args: []string{"ssh", "-W", "%h:%p", "jump host"}},
+		{line: "Compression", key: "Compression", args: nil},
+		{line: "# Host commented", key: "", args: nil},
+		{line: "   ", key: "", args: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			key, args := splitConfigLine(tt.line)
+			if len(args) == 0 {
+				args = nil
+			}
+			if key != tt.key || !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("splitConfigLine(%q) = %q, %q, want %q, %q", tt.line, key, args, tt.key, tt.args)
+			}
+		})
+	}
+}
+
+func TestMatchesHost(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		host     string
+		want     bool
+	}{
+		{patterns: []string{"web-*"}, host: "web-1", want: true},
+		{patterns: []string{"WEB-?"}, host: "web-1", want: true},
+		{patterns: []string{"web-*", "!web-legacy"}, host: "web-legacy", want: false},
+		{patterns: []string{"!web-legacy", "web-*"}, host: "web-legacy", want: false},
+		{patterns: []string{"!web-legacy"}, host: "db", want: false},
+		{patterns: []string{"db", "web-*"}, host: "db", want: true},
+		{patterns: []string{"*"}, host: "anything", want: true},
+	}
+	for _, tt := range tests {
+		if got := matchesHost(tt.patterns, tt.host); got != tt.want {
+			t.Errorf("matchesHost(%q, %q) = %v, want %v", tt.patterns, tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestParseConfigFollowsIncludes(t *testing.T) {
+	blocks, err := parseConfig(filepath.Join(testSSHDir, "config"), testSSHDir)
+	if err != nil {
+		t.Fatalf("parseConfig() error = %v", err)
+	}
+	var work *hostBlock
+	for i := range blocks {
+		if reflect.DeepEqual(blocks[i].Patterns, []string{"work"}) {
+			work = &blocks[i]
+		}
+	}
+	if work == nil || work.File != filepath.Join(testSSHDir, "conf.d", "work.conf") {
+		t.Fatalf("included Host work not parsed: %+v", work)
+	}
+	if got := optionMap(work.Options); got["User"] != "alice" || got["Port"] != "2200" {
+		t.Errorf("work options = %v, want canonical keys and unquoted values", got)
+	}
+}
+
+func TestParseConfigLimitsIncludeDepth(t *testing.T) {
+	_, err := parseConfig(filepath.Join(testSSHDir, "loop", "config"), testSSHDir)
+	if err == nil || !strings.Contains(err.Error(), "too many nested Include directives") {
+		t.Fatalf("parseConfig(loop) error = %v, want the depth limit", err)
+	}
+}
+
+func TestEffectiveOptions(t *testing.T) {
+	blocks, err := parseConfig(filepath.Join(testSSHDir, "config"), testSSHDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := func(options []configOption, key string) []string {
+		found := make([]string, 0)
+		for _, option := range options {
+			if option.Key == key {
+				found = append(found, option.Value)
+			}
+		}
+		return found
+	}
+
+	options, skipped := effectiveOptions(blocks, "web-1")
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		// First value wins, even over a more specific block later in the file.
+		{key: "User", want: []string{"deploy"}},
+		{key: "HostName", want: []string{"10.0.0.1"}},
+		{key: "ProxyJump", want: []string{"bastion"}},
+		// Options after an Include stay in the enclosing Host block.
+		{key: "ForwardAgent", want: []string{"yes"}},
+		{key: "ServerAliveInterval", want: []string{"30"}},
+		// multiValued options accumulate in file order.
+		{key: "IdentityFile", want: []string{"~/.ssh/id_global", "~/.ssh/id_web", "~/.ssh/My Keys/id_default"}},
+		{key: "LocalForward", want: []string{"8080 localhost:80", "9090 localhost:90"}},
+	}
+	for _, tt := range tests {
+		if got := values(options, tt.key); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("web-1 %s = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+	if len(skipped) != 1 || !strings.Contains(skipped[0], "Match host *.internal") {
+		t.Errorf("skipped = %q, want the Match block", skipped)
+	}
+
+	// The negated pattern excludes web-legacy from the web-* block.
+	legacy, _ := effectiveOptions(blocks, "web-legacy")
+	if got := optionMap(legacy); got["User"] != "fallback" || got["ProxyJump"] != "" {
+		t.Errorf("web-legacy options = %v", got)
+	}
+}
+
+func TestListAndShowHosts(t *testing.T) {
+	out, err := listHosts(testConfigContext(nil))
+	if err != nil {
+		t.Fatalf("listHosts() error = %v", err)
+	}
+	words := strings.Join(strings.Fields(out), " ")
+	for _, want := range []string{
+		"bastion bastion.example.test ops 2222 ~/.ssh/id_global -",
+		"web-1 10.0.0.1 deploy - ~/.ssh/id_global bastion",
+		"work work.example.test alice 2200",
+		"3 host(s)",
+	} {
+		if !strings.Contains(words, want) {
+			t.Errorf("listHosts() missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "web-legacy") || strings.Contains(out, "web-*") {
+		t.Errorf("listHosts() listed a pattern:\n%s", out)
+	}
+
+	out, err = showHost(testConfigContext(map[string]string{"host": "web-1"}))
+	if err != nil {
+		t.Fatalf("showHost() error = %v", err)
+	}
+	if !strings.Contains(out, "User") || !strings.Contains(out, "Match blocks are not evaluated") {
+		t.Errorf("showHost() output:\n%s", out)
+	}
+	if _, err := showHost(testConfigContext(nil)); modules.KindOf(err) != modules.KindUsage {
+		t.Errorf("showHost() without host error = %v, want usage error", err)
+	}
+	if _, err := listHosts(testConfigContext(map[string]string{"config": filepath.Join(t.TempDir(), "missing")})); modules.KindOf(err) != modules.KindNotFound {
+		t.Errorf("listHosts(missing) error = %v, want not found", err)
+	}
+}
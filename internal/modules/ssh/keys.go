@@ -0,0 +1,407 @@
+package ssh
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/modules"
+)
+
+const (
+	defaultRSABits = 4096
+	minRSABits     = 2048
+	certSuffix     = "-cert.pub"
+)
+
+type keyInfo struct {
+	Path        string
+	Type        string
+	Bits        int
+	Fingerprint string
+	Comment     string
+	HasPrivate  bool
+	Encrypted   bool
+	Weak        bool
+	Cert        *gossh.Certificate
+	Warnings    []string
+}
+
+func sshDir(ctx modules.ActionContext) (string, error) {
+	if dir := ctx.Params["ssh-dir"]; dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".ssh"), nil
+}
+
+func generateKey(ctx modules.ActionContext) (string, error) {
+	keyType := strings.ToLower(cliutil.FirstNonEmpty(ctx.Params["type"], "ed25519"))
+	var private crypto.PrivateKey
+	switch keyType {
+	case "ed25519":
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate key: %w", err)
+		}
+		private = key
+	case "rsa":
+		bits := defaultRSABits
+		if raw := ctx.Params["bits"]; raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < minRSABits {
+				return "", modules.UsageError("--bits must be a number of at least %d", minRSABits)
+			}
+			bits = parsed
+		}
+		key, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate key: %w", err)
+		}
+		private = key
+	default:
+		return "", modules.UsageError("unsupported key type %q (use ed25519 or rsa)", keyType)
+	}
+
+	path := ctx.Params["out"]
+	if path == "" && len(ctx.Positionals) > 0 {
+		path = ctx.Positionals[0]
+	}
+	if path == "" {
+		dir, err := sshDir(ctx)
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(dir, "id_"+keyType)
+	}
+	if ctx.Params["force"] != "true" {
+		for _, existing := range []string{path, path + ".pub"} {
+			if _, err := os.Stat(existing); err == nil {
+				return "", modules.UsageError("%s already exists (use --force true to overwrite)", existing)
+			}
+		}
+	}
+
+	comment := ctx.Params["comment"]
+	if comment == "" {
+		comment = defaultComment()
+	}
+	var block *pem.Block
+	var err error
+	if passphrase := ctx.Params["passphrase"]; passphrase != "" {
+		block, err = gossh.MarshalPrivateKeyWithPassphrase(private, comment, []byte(passphrase))
+	} else {
+		block, err = gossh.MarshalPrivateKey(private, comment)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to encode private key: %w", err)
+	}
+	signer, err := gossh.NewSignerFromKey(private)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive public key: %w", err)
+	}
+	public := authorizedLine(signer.PublicKey(), comment)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write private key: %w", err)
+	}
+	// WriteFile keeps the mode of an existing file, so tighten it explicitly.
+	if err := os.Chmod(path, 0o600); err != nil {
+		return "", fmt.Errorf("failed to set permissions on %s: %w", path, err)
+	}
+	if err := os.WriteFile(path+".pub", []byte(public+"\n"), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write public key: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Generated %s key %s\n", keyType, gossh.FingerprintSHA256(signer.PublicKey()))
+	fmt.Fprintf(&b, "  Private key: %s\n", path)
+	fmt.Fprintf(&b, "  Public key:  %s.pub\n", path)
+	if ctx.Params["passphrase"] == "" {
+		b.WriteString("  The private key is not protected by a passphrase.\n")
+	}
+	fmt.Fprintf(&b, "\n%s", public)
+	return b.String(), nil
+}
+
+func authorizedLine(key gossh.PublicKey, comment string) string {
+	line := strings.TrimSpace(string(gossh.MarshalAuthorizedKey(key)))
+	if comment != "" {
+		line += " " + comment
+	}
+	return line
+}
+
+func defaultComment() string {
+	user := cliutil.FirstNonEmpty(os.Getenv("USER"), os.Getenv("USERNAME"))
+	host, _ := os.Hostname()
+	switch {
+	case user != "" && host != "":
+		return user + "@" + host
+	default:
+		return user + host
+	}
+}
+
+// scanKeys pairs public keys with their private halves. Private keys without
+// a .pub file are still listed when the public key can be recovered.
+func scanKeys(dir string) ([]keyInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	keys := map[string]*keyInfo{}
+	for _, entry := range entries {
+		if entry.IsDir() || skipFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if strings.HasSuffix(entry.Name(), certSuffix) {
+			// ssh loads id_ed25519-cert.pub alongside id_ed25519, so the
+			// certificate belongs to that key rather than being a key itself.
+			public, _, _, _, err := gossh.ParseAuthorizedKey(data)
+			if err != nil {
+				continue
+			}
+			if cert, ok := public.(*gossh.Certificate); ok {
+				keyFor(keys, strings.TrimSuffix(path, certSuffix)).Cert = cert
+				continue
+			}
+		}
+		if strings.HasSuffix(entry.Name(), ".pub") {
+			public, comment, _, _, err := gossh.ParseAuthorizedKey(data)
+			if err != nil {
+				continue
+			}
+			base := strings.TrimSuffix(path, ".pub")
+			info := keyFor(keys, base)
+			info.Comment = comment
+			describePublic(info, public)
+			continue
+		}
+		if !bytes.Contains(data, []byte("PRIVATE KEY-----")) {
+			continue
+		}
+		info := keyFor(keys, path)
+		info.HasPrivate = true
+		if public, encrypted, ok := publicFromPrivate(data); ok {
+			info.Encrypted = encrypted
+			if info.Type == "" && public != nil {
+				describePublic(info, public)
+			}
+		}
+	}
+
+	list := make([]keyInfo, 0, len(keys))
+	for _, info := range keys {
+		if info.Type == "" && info.Cert != nil {
+			describePublic(info, info.Cert.Key)
+		}
+		if info.Type == "" {
+			info.Type = "unknown"
+			info.Warnings = append(info.Warnings, "encrypted key without .pub file; type unknown")
+		}
+		info.Weak = isWeak(*info)
+		info.Warnings = append(info.Warnings, keyWarnings(*info)...)
+		list = append(list, *info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Path < list[j].Path })
+	return list, nil
+}
+
+func skipFile(name string) bool {
+	switch name {
+	case "config", "known_hosts", "known_hosts.old", "authorized_keys", "authorized_keys2", "environment":
+		return true
+	}
+	return strings.HasPrefix(name, ".")
+}
+
+func keyFor(keys map[string]*keyInfo, path string) *keyInfo {
+	if info, ok := keys[path]; ok {
+		return info
+	}
+	info := &keyInfo{Path: path}
+	keys[path] = info
+	return info
+}
+
+func publicFromPrivate(data []byte) (gossh.PublicKey, bool, bool) {
+	raw, err := gossh.ParseRawPrivateKey(data)
+	if err != nil {
+		var missing *gossh.PassphraseMissingError
+		if errors.As(err, &missing) {
+			return missing.PublicKey, true, true
+		}
+		return nil, false, false
+	}
+	signer, err := gossh.NewSignerFromKey(raw)
+	if err != nil {
+		return nil, false, false
+	}
+	return signer.PublicKey(), false, true
+}
+
+func describePublic(info *keyInfo, public gossh.PublicKey) {
+	info.Type = public.Type()
+	info.Fingerprint = gossh.FingerprintSHA256(public)
+	info.Bits = publicKeyBits(public)
+}
+
+func publicKeyBits(public gossh.PublicKey) int {
+	crypted, ok := public.(gossh.CryptoPublicKey)
+	if !ok {
+		return 0
+	}
+	switch key := crypted.CryptoPublicKey().(type) {
+	case *rsa.PublicKey:
+		return key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return 256
+	}
+	return 0
+}
+
+// isWeak reports key types that modern servers reject or that can be broken.
+func isWeak(info keyInfo) bool {
+	return info.Type == gossh.KeyAlgoDSA || (info.Type == gossh.KeyAlgoRSA && info.Bits < minRSABits)
+}
+
+func keyWarnings(info keyInfo) []string {
+	warnings := make([]string, 0)
+	switch {
+	case info.Type == gossh.KeyAlgoDSA:
+		warnings = append(warnings, "DSA keys are disabled by default since OpenSSH 7.0; replace with ed25519")
+	case info.Type == gossh.KeyAlgoRSA && info.Bits < minRSABits:
+		warnings = append(warnings, fmt.Sprintf("RSA key of %d bits is too short; use ed25519 or RSA of at least %d bits", info.Bits, minRSABits))
+	case info.Type == gossh.KeyAlgoRSA && info.Bits < 3072:
+		warnings = append(warnings, fmt.Sprintf("RSA key of %d bits is below the current 3072-bit recommendation", info.Bits))
+	}
+	if info.Cert != nil {
+		warnings = append(warnings, certWarnings(info, time.Now())...)
+	}
+	if !info.HasPrivate {
+		warnings = append(warnings, "no matching private key")
+	} else if !info.Encrypted && info.Type != "unknown" {
+		warnings = append(warnings, "private key has no passphrase")
+	}
+	return warnings
+}
+
+func certWarnings(info keyInfo, now time.Time) []string {
+	warnings := make([]string, 0)
+	cert := info.Cert
+	if info.Fingerprint != "" && gossh.FingerprintSHA256(cert.Key) != info.Fingerprint {
+		warnings = append(warnings, "certificate "+filepath.Base(info.Path)+certSuffix+" was issued for a different key")
+	}
+	switch {
+	case cert.ValidBefore != gossh.CertTimeInfinity && now.After(time.Unix(int64(cert.ValidBefore), 0)):
+		warnings = append(warnings, "certificate expired "+time.Unix(int64(cert.ValidBefore), 0).Local().Format("2006-01-02 15:04"))
+	case now.Before(time.Unix(int64(cert.ValidAfter), 0)):
+		warnings = append(warnings, "certificate not valid before "+time.Unix(int64(cert.ValidAfter), 0).Local().Format("2006-01-02 15:04"))
+	}
+	return warnings
+}
+
+// describeCert summarises a certificate for the CERT column, e.g.
+// "user deploy,admin until 2026-01-02".
+func describeCert(cert *gossh.Certificate) string {
+	kind := "user"
+	if cert.CertType == gossh.HostCert {
+		kind = "host"
+	}
+	if len(cert.ValidPrincipals) > 0 {
+		kind += " " + strings.Join(cert.ValidPrincipals, ",")
+	}
+	if cert.ValidBefore == gossh.CertTimeInfinity {
+		return kind + " forever"
+	}
+	return kind + " until " + time.Unix(int64(cert.ValidBefore), 0).Local().Format("2006-01-02")
+}
+
+func listKeys(ctx modules.ActionContext) (string, error) {
+	dir, err := sshDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	keys, err := scanKeys(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", modules.NotFoundError("%s does not exist", dir)
+		}
+		return "", fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	if len(keys) == 0 {
+		return fmt.Sprintf("No keys found in %s.", dir), nil
+	}
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tTYPE\tBITS\tFINGERPRINT\tCOMMENT\tCERT")
+	for _, key := range keys {
+		bits := "-"
+		if key.Bits > 0 {
+			bits = strconv.Itoa(key.Bits)
+		}
+		cert := "-"
+		if key.Cert != nil {
+			cert = describeCert(key.Cert)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", filepath.Base(key.Path), key.Type, bits, cliutil.Dash(key.Fingerprint), cliutil.Dash(key.Comment), cert)
+	}
+	w.Flush()
+
+	weak := 0
+	for _, key := range keys {
+		if key.Weak {
+			weak++
+		}
+	}
+	writeWarnings(&b, keys)
+	fmt.Fprintf(&b, "\n%d key(s) in %s, %d weak.", len(keys), dir, weak)
+	if weak > 0 {
+		return b.String(), fmt.Errorf("%d weak key(s) found", weak)
+	}
+	return b.String(), nil
+}
+
+func writeWarnings(b *strings.Builder, keys []keyInfo) {
+	header := false
+	for _, key := range keys {
+		for _, warning := range key.Warnings {
+			if !header {
+				b.WriteString("\nWarnings:\n")
+				header = true
+			}
+			fmt.Fprintf(b, "  %s: %s\n", filepath.Base(key.Path), warning)
+		}
+	}
+}
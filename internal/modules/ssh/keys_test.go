@@ -0,0 +1,125 @@
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+
+	"go-devtools/internal/modules"
+)
+
+func keygen(t *testing.T, path string) gossh.PublicKey {
+	t.Helper()
+	if _, err := generateKey(modules.ActionContext{Params: map[string]string{"out": path, "comment": "dev@test"}}); err != nil {
+		t.Fatalf("keygen error = %v", err)
+	}
+	data, err := os.ReadFile(path + ".pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, _, _, _, err := gossh.ParseAuthorizedKey(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return public
+}
+
+func writeCert(t *testing.T, path string, key gossh.PublicKey, validBefore time.Time) {
+	t.Helper()
+	_, caKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := gossh.NewSignerFromKey(caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := &gossh.Certificate{
+		Key:             key,
+		CertType:        gossh.UserCert,
+		KeyId:           "dev",
+		ValidPrincipals: []string{"deploy"},
+		ValidAfter:      uint64(validBefore.Add(-48 * time.Hour).Unix()),
+		ValidBefore:     uint64(validBefore.Unix()),
+	}
+	if err := cert.SignCert(rand.Reader, signer); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, gossh.MarshalAuthorizedKey(cert), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanKeysPairsCertificates(t *testing.T) {
+	dir := t.TempDir()
+	work := keygen(t, filepath.Join(dir, "id_work"))
+	old := keygen(t, filepath.Join(dir, "id_old"))
+	other := keygen(t, filepath.Join(t.TempDir(), "id_other"))
+	writeCert(t, filepath.Join(dir, "id_work-cert.pub"), work, time.Now().Add(24*time.Hour))
+	writeCert(t, filepath.Join(dir, "id_old-cert.pub"), old, time.Now().Add(-time.Hour))
+	writeCert(t, filepath.Join(dir, "id_stray-cert.pub"), other, time.Now().Add(24*time.Hour))
+
+	keys, err := scanKeys(dir)
+	if err != nil {
+		t.Fatalf("scanKeys() error = %v", err)
+	}
+	byName := map[string]keyInfo{}
+	for _, key := range keys {
+		byName[filepath.Base(key.Path)] = key
+	}
+	if len(keys) != 3 {
+		t.Fatalf("scanKeys() = %d keys (%v), want id_old, id_stray and id_work", len(keys), byName)
+	}
+
+	workKey := byName["id_work"]
+	if workKey.Cert == nil || workKey.Type != gossh.KeyAlgoED25519 || !workKey.HasPrivate {
+		t.Errorf("id_work = %+v, want an ed25519 key with its certificate", workKey)
+	}
+	for _, warning := range workKey.Warnings {
+		if strings.Contains(warning, "certificate") || strings.Contains(warning, "no matching private key") {
+			t.Errorf("id_work warning %q", warning)
+		}
+	}
+	if warnings := strings.Join(byName["id_old"].Warnings, "; "); !strings.Contains(warnings, "certificate expired") {
+		t.Errorf("id_old warnings = %q, want an expired certificate", warnings)
+	}
+	if warnings := strings.Join(byName["id_stray"].Warnings, "; "); !strings.Contains(warnings, "no matching private key") {
+		t.Errorf("id_stray warnings = %q, want a missing private key", warnings)
+	}
+	if got := describeCert(workKey.Cert); !strings.HasPrefix(got, "user deploy until ") {
+		t.Errorf("describeCert() = %q", got)
+	}
+}
+
+func TestCertificateForDifferentKey(t *testing.T) {
+	dir := t.TempDir()
+	keygen(t, filepath.Join(dir, "id_work"))
+	other := keygen(t, filepath.Join(t.TempDir(), "id_other"))
+	writeCert(t, filepath.Join(dir, "id_work-cert.pub"), other, time.Now().Add(24*time.Hour))
+
+	keys, err := scanKeys(dir)
+	if err != nil || len(keys) != 1 {
+		t.Fatalf("scanKeys() = %+v, %v", keys, err)
+	}
+	if warnings := strings.Join(keys[0].Warnings, "; "); !strings.Contains(warnings, "issued for a different key") {
+		t.Errorf("warnings = %q, want a key mismatch", warnings)
+	}
+}
+
+func TestKeygenRefusesToOverwrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	keygen(t, path)
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("private key mode = %v (err %v), want 0600", info.Mode().Perm(), err)
+	}
+	if _, err := generateKey(modules.ActionContext{Params: map[string]string{"out": path}}); modules.KindOf(err) != modules.KindUsage {
+		t.Errorf("second keygen error = %v, want usage error", err)
+	}
+}
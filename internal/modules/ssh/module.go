@@ -0,0 +1,117 @@
+package ssh
+
+import (
+	"fmt"
+
+	"go-devtools/internal/cliutil"
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/requirements"
+)
+
+type Tool struct{}
+
+func New() modules.Tool {
+	return Tool{}
+}
+
+func (Tool) ID() string { return "ssh" }
+
+func (Tool) Label() string { return "SSH Keys" }
+
+func (Tool) Description() string { return "Generate and audit SSH keys and config" }
+
+func (Tool) Requirements() []requirements.Check { return nil }
+
+func (Tool) Actions() []modules.Action {
+	return []modules.Action{
+		{
+			ID:          "keygen",
+			Label:       "Generate key pair",
+			Description: "Generate an ed25519 or RSA key pair in OpenSSH format",
+			Usage:       "devtools run ssh keygen [path] [--type ed25519|rsa] [--bits 4096] [--comment <text>] [--passphrase <pw>] [--force true]",
+			Run:         generateKey,
+		},
+		{
+			ID:          "keys",
+			Label:       "List keys",
+			Description: "List keys in ~/.ssh with type, size, fingerprint and comment; flags weak keys",
+			Usage:       "devtools run ssh keys [--ssh-dir <dir>]",
+			Run:         listKeys,
+		},
+		{
+			ID:          "hosts",
+			Label:       "List config hosts",
+			Description: "List Host entries from ~/.ssh/config with their effective settings",
+			Usage:       "devtools run ssh hosts [--config <file>] [--ssh-dir <dir>]",
+			Run:         listHosts,
+		},
+		{
+			ID:          "host",
+			Label:       "Show host settings",
+			Description: "Show the effective ~/.ssh/config settings for a host and where each comes from",
+			Usage:       "devtools run ssh host <name> [--config <file>] [--ssh-dir <dir>]",
+			Run:         showHost,
+		},
+		{
+			ID:          "check-permissions",
+			Label:       "Check permissions",
+			Description: "Check that ~/.ssh and the files in it are not readable or writable by others",
+			Usage:       "devtools run ssh check-permissions [--fix true] [--ssh-dir <dir>]",
+			Run:         checkPermissions,
+		},
+	}
+}
+
+func (Tool) Menu() *menu.Menu {
+	return menu.NewBuilder("SSH Keys").
+		Action("List keys", "Keys in ~/.ssh with fingerprints and warnings", func() (string, error) {
+			return listKeys(modules.ActionContext{})
+		}).
+		Action("Generate key pair", "Prompt for type, file and passphrase", keygenPrompt).
+		Action("List config hosts", "Host entries from ~/.ssh/config", func() (string, error) {
+			return listHosts(modules.ActionContext{})
+		}).
+		Action("Show host settings", "Prompt for a host name", hostPrompt).
+		Action("Check permissions", "Report files in ~/.ssh that others can access", func() (string, error) {
+			return checkPermissions(modules.ActionContext{})
+		}).
+		WithBack().
+		Build()
+}
+
+func keygenPrompt() (string, error) {
+	keyType, err := cliutil.Prompt("Key type (ed25519/rsa) [ed25519]: ")
+	if err != nil {
+		return "", err
+	}
+	path, err := cliutil.Prompt("File [~/.ssh/id_<type>]: ")
+	if err != nil {
+		return "", err
+	}
+	comment, err := cliutil.Prompt("Comment [user@host]: ")
+	if err != nil {
+		return "", err
+	}
+	passphrase, err := cliutil.Prompt("Passphrase (empty for none, input is visible): ")
+	if err != nil {
+		return "", err
+	}
+	return generateKey(modules.ActionContext{Params: map[string]string{
+		"type":       keyType,
+		"out":        expandHome(path),
+		"comment":    comment,
+		"passphrase": passphrase,
+	}})
+}
+
+func hostPrompt() (string, error) {
+	host, err := cliutil.Prompt("Host: ")
+	if err != nil {
+		return "", err
+	}
+	if host == "" {
+		return "", fmt.Errorf("host cannot be empty")
+	}
+	return showHost(modules.ActionContext{Positionals: []string{host}})
+}
@@ -0,0 +1,109 @@
+package ssh
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"go-devtools/internal/modules"
+)
+
+type permIssue struct {
+	Path    string
+	Mode    fs.FileMode
+	Want    fs.FileMode
+	Problem string
+}
+
+// expectedMode returns the strictest mode ssh and sshd insist on, and whether
+// anything beyond the owner may read the file at all.
+func expectedMode(dir, path string, info fs.FileInfo) (fs.FileMode, bool) {
+	if path == dir {
+		return 0o700, false
+	}
+	name := filepath.Base(path)
+	switch {
+	case info.IsDir():
+		return 0o700, false
+	case strings.HasSuffix(name, ".pub"), name == "known_hosts", name == "known_hosts.old":
+		return 0o644, true
+	case name == "config", name == "authorized_keys", name == "authorized_keys2":
+		return 0o600, true
+	default:
+		return 0o600, false
+	}
+}
+
+func findPermissionIssues(dir string) ([]permIssue, error) {
+	issues := make([]permIssue, 0)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		mode := info.Mode().Perm()
+		want, othersMayRead := expectedMode(dir, path, info)
+		switch {
+		case mode&0o022 != 0:
+			issues = append(issues, permIssue{Path: path, Mode: mode, Want: want, Problem: "writable by group or others"})
+		case !othersMayRead && mode&0o077 != 0:
+			issues = append(issues, permIssue{Path: path, Mode: mode, Want: want, Problem: "readable by group or others"})
+		}
+		return nil
+	})
+	return issues, err
+}
+
+func checkPermissions(ctx modules.ActionContext) (string, error) {
+	if runtime.GOOS == "windows" {
+		return "Permission checks use Unix modes and are skipped on Windows.", nil
+	}
+	dir, err := sshDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return "", modules.NotFoundError("%s does not exist", dir)
+	}
+	issues, err := findPermissionIssues(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+	if len(issues) == 0 {
+		return fmt.Sprintf("Permissions in %s look good.", dir), nil
+	}
+
+	fix := ctx.Params["fix"] == "true"
+	var b strings.Builder
+	failed := 0
+	for _, issue := range issues {
+		fmt.Fprintf(&b, "%s  %04o  %s (expected %04o)", issue.Path, issue.Mode, issue.Problem, issue.Want)
+		if fix {
+			if err := os.Chmod(issue.Path, issue.Want); err != nil {
+				fmt.Fprintf(&b, "  fix failed: %v", err)
+				failed++
+			} else {
+				b.WriteString("  fixed")
+			}
+		}
+		b.WriteString("\n")
+	}
+	switch {
+	case !fix:
+		b.WriteString("\nssh refuses private keys that others can read. Run with --fix true to apply the expected modes.")
+		return b.String(), fmt.Errorf("%d permission problem(s) in %s", len(issues), dir)
+	case failed > 0:
+		return b.String(), fmt.Errorf("%d of %d permission problem(s) could not be fixed", failed, len(issues))
+	}
+	fmt.Fprintf(&b, "\nFixed %d permission problem(s).", len(issues))
+	return b.String(), nil
+}
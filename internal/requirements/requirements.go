@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+
+	"go-devtools/internal/envfile"
 )
 
 type InstallAction struct {
@@ -12,8 +14,11 @@ type InstallAction struct {
 }
 
 type Check struct {
-	Name      string
-	Validate  func() error
+	Name     string
+	Validate func() error
+	// Export runs once every check of a module or submenu has passed, so
+	// Validate can stay free of side effects.
+	Export    func() error
 	Installer *InstallAction
 }
 
@@ -24,6 +29,19 @@ func (c Check) Run() error {
 	return c.Validate()
 }
 
+// ExportAll runs the Export step of each check in order.
+func ExportAll(checks []Check) error {
+	for _, check := range checks {
+		if check.Export == nil {
+			continue
+		}
+		if err := check.Export(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func CommandExists(name string) Check {
 	return Check{
 		Name: name,
@@ -56,14 +74,80 @@ func CommandExistsWithBrew(name, formula string) Check {
 	return check
 }
 
-func EnvVarSet(name string) Check {
-	return Check{
+type EnvOption func(*envConfig)
+
+type envConfig struct {
+	dotenv     bool
+	dotenvPath string
+}
+
+// FromDotenv lets EnvVarSet fall back to a .env file when the variable is
+// not exported. An empty path searches the working directory and its
+// parents for .env. The value is exported into the process by the check's
+// Export step, so the action sees it too.
+func FromDotenv(path string) EnvOption {
+	return func(c *envConfig) {
+		c.dotenv = true
+		c.dotenvPath = path
+	}
+}
+
+func EnvVarSet(name string, opts ...EnvOption) Check {
+	var config envConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	check := Check{
 		Name: name,
 		Validate: func() error {
-			if os.Getenv(name) == "" {
+			if os.Getenv(name) != "" {
+				return nil
+			}
+			if !config.dotenv {
 				return fmt.Errorf("required environment variable %q is not set", name)
 			}
-			return nil
+			_, err := config.lookup(name)
+			return err
 		},
 	}
+	if config.dotenv {
+		check.Export = func() error {
+			if os.Getenv(name) != "" {
+				return nil
+			}
+			value, err := config.lookup(name)
+			if err != nil {
+				return err
+			}
+			if err := os.Setenv(name, value); err != nil {
+				return fmt.Errorf("failed to export %s: %w", name, err)
+			}
+			return nil
+		}
+	}
+	return check
+}
+
+func (c envConfig) lookup(name string) (string, error) {
+	path := c.dotenvPath
+	if path == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("required environment variable %q is not set", name)
+		}
+		found, ok := envfile.Find(cwd, envfile.DefaultName)
+		if !ok {
+			return "", fmt.Errorf("required environment variable %q is not set and no %s file was found", name, envfile.DefaultName)
+		}
+		path = found
+	}
+	file, err := envfile.Load(path)
+	if err != nil {
+		return "", fmt.Errorf("required environment variable %q is not set (failed to read %s: %v)", name, path, err)
+	}
+	value, ok := file.Lookup(name)
+	if !ok || value == "" {
+		return "", fmt.Errorf("required environment variable %q is not set or defined in %s", name, path)
+	}
+	return value, nil
 }
@@ -0,0 +1,52 @@
+package requirements
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnvVarSetFromDotenv(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	if err := os.WriteFile(path, []byte("DEVTOOLS_TEST_TOKEN=from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DEVTOOLS_TEST_TOKEN", "")
+
+	if err := EnvVarSet("DEVTOOLS_TEST_TOKEN").Run(); err == nil {
+		t.Error("EnvVarSet without FromDotenv passed with the variable unset")
+	}
+
+	check := EnvVarSet("DEVTOOLS_TEST_TOKEN", FromDotenv(path))
+	if err := check.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := os.Getenv("DEVTOOLS_TEST_TOKEN"); got != "" {
+		t.Fatalf("Run() exported %q; validation must not change the environment", got)
+	}
+	if err := ExportAll([]Check{CommandExists("sh"), check}); err != nil {
+		t.Fatalf("ExportAll() error = %v", err)
+	}
+	if got := os.Getenv("DEVTOOLS_TEST_TOKEN"); got != "from-file" {
+		t.Errorf("after ExportAll DEVTOOLS_TEST_TOKEN = %q, want from-file", got)
+	}
+
+	t.Setenv("DEVTOOLS_TEST_TOKEN", "exported")
+	if err := ExportAll([]Check{check}); err != nil || os.Getenv("DEVTOOLS_TEST_TOKEN") != "exported" {
+		t.Errorf("ExportAll() overrode an exported value (err %v)", err)
+	}
+}
+
+func TestEnvVarSetFromDotenvMissingKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("OTHER=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DEVTOOLS_TEST_TOKEN", "")
+	err := EnvVarSet("DEVTOOLS_TEST_TOKEN", FromDotenv(path)).Run()
+	if err == nil || !strings.Contains(err.Error(), "not set or defined in "+path) {
+		t.Errorf("Run() error = %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package safefile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteKeepsPermissions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+
+	if err := Write(path, []byte("one")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("new file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	if err := os.Chmod(path, 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if err := Write(path, []byte("two")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	info, _ := os.Stat(path)
+	if string(data) != "two" || info.Mode().Perm() != 0o640 {
+		t.Errorf("after rewrite: %q mode %v, want \"two\" mode 0640", data, info.Mode().Perm())
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want only the target file", len(entries))
+	}
+}
+
+func TestBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte("original"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := Backup(path)
+	if err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if backup != path+".bak" {
+		t.Errorf("Backup() = %q, want %q", backup, path+".bak")
+	}
+	data, _ := os.ReadFile(backup)
+	info, _ := os.Stat(backup)
+	if string(data) != "original" || info.Mode().Perm() != 0o600 {
+		t.Errorf("backup = %q mode %v", data, info.Mode().Perm())
+	}
+
+	if _, err := Backup(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Backup(missing) error = nil")
+	}
+}
@@ -0,0 +1,390 @@
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"go-devtools/internal/modules"
+)
+
+const (
+	modulePath   = "go-devtools"
+	mainFile     = "cmd/devtools/main.go"
+	modulesDir   = "internal/modules"
+	toolsLiteral = "toolModules := []modules.Tool{"
+)
+
+var idPattern = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+// writeFile is replaced in tests to simulate a failing write.
+var writeFile = os.WriteFile
+
+// buildMain type-checks the edited command; tests replace it when the
+// fixture repository cannot build.
+var buildMain = func(root string) error {
+	cmd := exec.Command("go", "build", "-o", os.DevNull, "./"+filepath.ToSlash(filepath.Dir(mainFile)))
+	cmd.Dir = root
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w\n%s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+type Module struct {
+	ID      string
+	Package string
+	Label   string
+}
+
+type Result struct {
+	Files    []string
+	Modified []string
+	// Alias is the import name used in main.go when the package name
+	// clashes with an identifier already used there, e.g. "climodule".
+	Alias string
+}
+
+func NewModule(id string) (Module, error) {
+	if !idPattern.MatchString(id) {
+		return Module{}, modules.UsageError("invalid module id %q (use lowercase words separated by dashes, e.g. my-tool)", id)
+	}
+
+	if pkg := strings.ReplaceAll(id, "-", ""); token.IsKeyword(pkg) || pkg == "main" {
+		return Module{}, modules.UsageError("invalid module id %q (%s is reserved in Go)", id, pkg)
+	}
+
+	words := strings.Split(id, "-")
+	labels := make([]string, len(words))
+	for i, word := range words {
+		labels[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return Module{
+		ID:      id,
+		Package: strings.Join(words, ""),
+		Label:   strings.Join(labels, " "),
+	}, nil
+}
+
+func FindRoot(start string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", err
+	}
+	for {
+		data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil && strings.HasPrefix(strings.TrimSpace(string(data)), "module "+modulePath) {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", modules.NotFoundError("could not find the %s repository root above %s (use --root)", modulePath, start)
+		}
+		dir = parent
+	}
+}
+
+// Generate writes the module package, registers it in main.go and builds
+// the command to type-check the result. On any error after the package
+// directory is created, the directory is removed and main.go restored so
+// the tree is left as it was.
+func Generate(root string, module Module) (result Result, err error) {
+	pkgDir := filepath.Join(root, modulesDir, module.Package)
+	if _, err := os.Stat(pkgDir); err == nil {
+		return Result{}, modules.UsageError("package directory %s already exists", pkgDir)
+	}
+
+	mainPath := filepath.Join(root, mainFile)
+	mainSource, err := os.ReadFile(mainPath)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read %s: %w", mainFile, err)
+	}
+	name, err := importName(filepath.Dir(mainPath), module)
+	if err != nil {
+		return Result{}, err
+	}
+	registered, err := register(mainSource, module, name)
+	if err != nil {
+		return Result{}, err
+	}
+
+	files := map[string]*template.Template{
+		filepath.Join(pkgDir, "module.go"):      moduleTemplate,
+		filepath.Join(pkgDir, "module_test.go"): testTemplate,
+	}
+	rendered := make(map[string][]byte, len(files))
+	for path, tmpl := range files {
+		source, err := render(tmpl, module)
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to render %s: %w", filepath.Base(path), err)
+		}
+		rendered[path] = source
+	}
+
+	if err := os.MkdirAll(pkgDir, 0o755); err != nil {
+		return Result{}, fmt.Errorf("failed to create %s: %w", pkgDir, err)
+	}
+	mainWritten := false
+	defer func() {
+		if err != nil {
+			if mainWritten {
+				if restoreErr := writeFile(mainPath, mainSource, 0o644); restoreErr != nil {
+					err = fmt.Errorf("%w (restoring %s also failed: %v)", err, mainFile, restoreErr)
+				}
+			}
+			if removeErr := os.RemoveAll(pkgDir); removeErr != nil {
+				err = fmt.Errorf("%w (cleanup of %s also failed: %v)", err, pkgDir, removeErr)
+			}
+			result = Result{}
+		}
+	}()
+
+	result = Result{Modified: []string{mainPath}}
+	for path, source := range rendered {
+		if err := writeFile(path, source, 0o644); err != nil {
+			return Result{}, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		result.Files = append(result.Files, path)
+	}
+	sort.Strings(result.Files)
+
+	if err := writeFile(mainPath, registered, 0o644); err != nil {
+		return Result{}, fmt.Errorf("failed to update %s: %w", mainFile, err)
+	}
+	mainWritten = true
+	if err := buildMain(root); err != nil {
+		return Result{}, fmt.Errorf("generated module does not build: %w", err)
+	}
+	if name != module.Package {
+		result.Alias = name
+	}
+	return result, nil
+}
+
+// importName returns the name main.go should import the module under: its
+// package name, or <package>module when that would clash with an import or
+// identifier already used by the command, such as cli or history.
+func importName(dir string, module Module) (string, error) {
+	used, err := usedNames(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, name := range []string{module.Package, module.Package + "module"} {
+		if !used[name] {
+			return name, nil
+		}
+	}
+	return "", modules.UsageError("module id %q clashes with names used in %s; choose another id", module.ID, mainFile)
+}
+
+// usedNames collects every identifier and import name in the Go files of
+// dir, which is conservative but catches clashes with local variables too.
+func usedNames(dir string) (map[string]bool, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	used := map[string]bool{}
+	fset := token.NewFileSet()
+	for _, file := range paths {
+		parsed, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		for _, spec := range parsed.Imports {
+			importPath := strings.Trim(spec.Path.Value, `"`)
+			used[path.Base(importPath)] = true
+		}
+		ast.Inspect(parsed, func(node ast.Node) bool {
+			if ident, ok := node.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+			return true
+		})
+	}
+	return used, nil
+}
+
+func register(source []byte, module Module, name string) ([]byte, error) {
+	text := string(source)
+	importPath := fmt.Sprintf("%q", modulePath+"/"+modulesDir+"/"+module.Package)
+	if strings.Contains(text, importPath+"\n") {
+		return nil, modules.UsageError("module package %q is already imported in %s", module.Package, mainFile)
+	}
+	importLine := "\t" + importPath + "\n"
+	if name != module.Package {
+		importLine = "\t" + name + " " + importPath + "\n"
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	prefix := "\t\"" + modulePath + "/" + modulesDir + "/"
+	insertAt := -1
+	for i, line := range lines {
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+		insertAt = i + 1
+		if line > importLine {
+			insertAt = i
+			break
+		}
+	}
+	if insertAt < 0 {
+		return nil, fmt.Errorf("could not find module imports in %s", mainFile)
+	}
+	lines = append(lines[:insertAt], append([]string{importLine}, lines[insertAt:]...)...)
+	text = strings.Join(lines, "")
+
+	start := strings.Index(text, toolsLiteral)
+	if start < 0 {
+		return nil, fmt.Errorf("could not find %q in %s", toolsLiteral, mainFile)
+	}
+	end := strings.Index(text[start:], "\n\t}")
+	if end < 0 {
+		return nil, fmt.Errorf("could not find the end of the toolModules slice in %s", mainFile)
+	}
+	end += start
+	text = text[:end] + fmt.Sprintf("\n\t\t%s.New(),", name) + text[end:]
+
+	formatted, err := format.Source([]byte(text))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s: %w", mainFile, err)
+	}
+	return formatted, nil
+}
+
+func render(tmpl *template.Template, module Module) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, module); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+var moduleTemplate = template.Must(template.New("module").Parse(`package {{.Package}}
+
+import (
+	"fmt"
+
+	"go-devtools/internal/menu"
+	"go-devtools/internal/modules"
+	"go-devtools/internal/requirements"
+)
+
+type Tool struct{}
+
+func New() modules.Tool {
+	return Tool{}
+}
+
+func (Tool) ID() string { return "{{.ID}}" }
+
+func (Tool) Label() string { return "{{.Label}}" }
+
+func (Tool) Description() string { return "TODO: describe {{.Label}}" }
+
+func (Tool) Requirements() []requirements.Check {
+	// Add checks such as requirements.CommandExists("git") or
+	// requirements.EnvVarSet("TOKEN", requirements.FromDotenv("")).
+	return nil
+}
+
+func (Tool) Actions() []modules.Action {
+	return []modules.Action{
+		{
+			ID:          "greet",
+			Label:       "Print greeting",
+			Description: "Example action",
+			Usage:       "devtools run {{.ID}} greet [--name <name>]",
+			Run:         runGreeting,
+		},
+	}
+}
+
+func (Tool) Menu() *menu.Menu {
+	return menu.NewBuilder("{{.Label}}").
+		Action("Print greeting", "Example action", func() (string, error) {
+			return runGreeting(modules.ActionContext{})
+		}).
+		WithBack().
+		Build()
+}
+
+func runGreeting(ctx modules.ActionContext) (string, error) {
+	name := ctx.Params["name"]
+	if name == "" {
+		name = "world"
+	}
+	return fmt.Sprintf("Hello, %s, from the {{.Label}} module.", name), nil
+}
+`))
+
+var testTemplate = template.Must(template.New("test").Parse(`package {{.Package}}
+
+import (
+	"testing"
+
+	"go-devtools/internal/devtoolstest"
+	"go-devtools/internal/modules"
+)
+
+func TestActions(t *testing.T) {
+	tool := New()
+
+	tests := []struct {
+		name    string
+		action  string
+		params  map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "greet default",
+			action: "greet",
+			want:   "Hello, world, from the {{.Label}} module.",
+		},
+		{
+			name:   "greet with name",
+			action: "greet",
+			params: map[string]string{"name": "alice"},
+			want:   "Hello, alice, from the {{.Label}} module.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, ok := modules.FindAction(tool, tt.action)
+			if !ok {
+				t.Fatalf("action %q not found", tt.action)
+			}
+			got, err := action.Run(modules.ActionContext{Params: tt.params})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Run() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMenu(t *testing.T) {
+	devtoolstest.Isolate(t)
+	term, err := devtoolstest.RunTool(t, New(), devtoolstest.KeyEnter, devtoolstest.KeyEnter, devtoolstest.KeyQuit)
+	if err != nil {
+		t.Fatalf("RunTool() error = %v", err)
+	}
+	devtoolstest.AssertFrameContains(t, term.LastFrame(), "Hello, world, from the {{.Label}} module.")
+}
+`))
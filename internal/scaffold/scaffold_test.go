@@ -0,0 +1,269 @@
+package scaffold
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-devtools/internal/modules"
+)
+
+var defaultBuildMain = buildMain
+
+const testMain = `package main
+
+import (
+	"go-devtools/internal/modules"
+	"go-devtools/internal/modules/alpha"
+	"go-devtools/internal/modules/zulu"
+)
+
+func main() {
+	toolModules := []modules.Tool{
+		alpha.New(),
+		zulu.New(),
+	}
+	_ = toolModules
+}
+`
+
+// newRepo creates a fixture repository whose main.go imports packages that
+// do not exist, so the build check is stubbed out.
+func newRepo(t *testing.T) string {
+	t.Helper()
+	buildMain = func(string) error { return nil }
+	t.Cleanup(func() { buildMain = defaultBuildMain })
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module go-devtools\n\ngo 1.22\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "cmd", "devtools"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, mainFile), []byte(testMain), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestNewModule(t *testing.T) {
+	tests := []struct {
+		id      string
+		want    Module
+		wantErr bool
+	}{
+		{id: "my-tool", want: Module{ID: "my-tool", Package: "mytool", Label: "My Tool"}},
+		{id: "git2", want: Module{ID: "git2", Package: "git2", Label: "Git2"}},
+		{id: "My-Tool", wantErr: true},
+		{id: "my--tool", wantErr: true},
+		{id: "2fa", wantErr: true},
+		{id: "go", wantErr: true},
+		{id: "func", wantErr: true},
+		{id: "main", wantErr: true},
+		{id: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			got, err := NewModule(tt.id)
+			if tt.wantErr {
+				if modules.KindOf(err) != modules.KindUsage {
+					t.Fatalf("NewModule(%q) error = %v, want usage error", tt.id, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewModule(%q) error = %v", tt.id, err)
+			}
+			if got != tt.want {
+				t.Errorf("NewModule(%q) = %+v, want %+v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindRoot(t *testing.T) {
+	root := newRepo(t)
+	nested := filepath.Join(root, "internal", "modules")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	got, err := FindRoot(nested)
+	if err != nil {
+		t.Fatalf("FindRoot() error = %v", err)
+	}
+	if got != root {
+		t.Errorf("FindRoot() = %q, want %q", got, root)
+	}
+
+	if _, err := FindRoot(t.TempDir()); modules.KindOf(err) != modules.KindNotFound {
+		t.Errorf("FindRoot(outside) error = %v, want not found", err)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	root := newRepo(t)
+	module, _ := NewModule("my-tool")
+
+	result, err := Generate(root, module)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(result.Files) != 2 {
+		t.Fatalf("Generate() files = %v, want module.go and module_test.go", result.Files)
+	}
+	for _, path := range result.Files {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("missing generated file: %v", err)
+		}
+	}
+
+	main, err := os.ReadFile(filepath.Join(root, mainFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(main)
+	importAt := strings.Index(text, `"go-devtools/internal/modules/mytool"`)
+	if importAt < 0 || importAt > strings.Index(text, `"go-devtools/internal/modules/zulu"`) {
+		t.Errorf("import not inserted in sorted position:\n%s", text)
+	}
+	if !strings.Contains(text, "\t\tzulu.New(),\n\t\tmytool.New(),\n") {
+		t.Errorf("mytool.New() not appended to toolModules:\n%s", text)
+	}
+
+	if _, err := Generate(root, module); modules.KindOf(err) != modules.KindUsage {
+		t.Errorf("second Generate() error = %v, want usage error", err)
+	}
+}
+
+func TestGenerateRollsBackOnFailure(t *testing.T) {
+	root := newRepo(t)
+	module, _ := NewModule("my-tool")
+
+	writeFile = func(path string, data []byte, perm os.FileMode) error {
+		if strings.HasSuffix(path, mainFile) {
+			return errors.New("disk full")
+		}
+		return os.WriteFile(path, data, perm)
+	}
+	t.Cleanup(func() { writeFile = os.WriteFile })
+
+	if _, err := Generate(root, module); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("Generate() error = %v, want the write failure", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, modulesDir, module.Package)); !os.IsNotExist(err) {
+		t.Errorf("package directory left behind after failure (stat error: %v)", err)
+	}
+	main, _ := os.ReadFile(filepath.Join(root, mainFile))
+	if string(main) != testMain {
+		t.Errorf("main.go changed after failure:\n%s", main)
+	}
+}
+
+func TestGenerateAliasesClashingPackageNames(t *testing.T) {
+	tests := []struct {
+		id    string
+		alias string
+	}{
+		{id: "modules", alias: "modulesmodule"},
+		{id: "tool-modules", alias: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			root := newRepo(t)
+			module, _ := NewModule(tt.id)
+			result, err := Generate(root, module)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if result.Alias != tt.alias {
+				t.Errorf("Alias = %q, want %q", result.Alias, tt.alias)
+			}
+			name := module.Package
+			if tt.alias != "" {
+				name = tt.alias
+			}
+			main, _ := os.ReadFile(filepath.Join(root, mainFile))
+			if !strings.Contains(string(main), "\t\t"+name+".New(),\n") {
+				t.Errorf("%s.New() not registered:\n%s", name, main)
+			}
+		})
+	}
+
+	root := newRepo(t)
+	module, _ := NewModule("alpha")
+	if _, err := Generate(root, module); modules.KindOf(err) != modules.KindUsage {
+		t.Errorf("Generate(alpha) error = %v, want usage error for an existing import", err)
+	}
+}
+
+func TestGenerateRestoresMainWhenBuildFails(t *testing.T) {
+	root := newRepo(t)
+	buildMain = func(string) error { return errors.New("undefined: cli.New") }
+	module, _ := NewModule("my-tool")
+
+	if _, err := Generate(root, module); err == nil || !strings.Contains(err.Error(), "undefined: cli.New") {
+		t.Fatalf("Generate() error = %v, want the build failure", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, modulesDir, module.Package)); !os.IsNotExist(err) {
+		t.Errorf("package directory left behind after failure (stat error: %v)", err)
+	}
+	if main, _ := os.ReadFile(filepath.Join(root, mainFile)); string(main) != testMain {
+		t.Errorf("main.go not restored after failure:\n%s", main)
+	}
+}
+
+// TestGenerateBuildsWithRealMain scaffolds modules whose package names
+// clash with main.go's imports and locals into a copy of this repository
+// and builds the result.
+func TestGenerateBuildsWithRealMain(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a copy of the repository")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not in PATH")
+	}
+	source, err := FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	copyTree(t, source, root, "go.mod", "cmd", "internal")
+
+	for _, id := range []string{"cli", "history", "root"} {
+		module, _ := NewModule(id)
+		result, err := Generate(root, module)
+		if err != nil {
+			t.Fatalf("Generate(%q) error = %v", id, err)
+		}
+		if result.Alias != id+"module" {
+			t.Errorf("Generate(%q) alias = %q, want %q", id, result.Alias, id+"module")
+		}
+	}
+}
+
+func copyTree(t *testing.T, from, to string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		err := filepath.WalkDir(filepath.Join(from, name), func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(from, path)
+			if entry.IsDir() {
+				return os.MkdirAll(filepath.Join(to, rel), 0o755)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(to, rel), data, 0o644)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
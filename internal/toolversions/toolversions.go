@@ -0,0 +1,132 @@
+package toolversions
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go-devtools/internal/requirements"
+)
+
+const timeout = 15 * time.Second
+
+// Spec describes how to find a CLI and read its version. Aliases are the
+// names the tool goes by in an asdf .tool-versions file.
+type Spec struct {
+	Name    string
+	Command string
+	Args    []string
+	Pattern *regexp.Regexp
+	Aliases []string
+	Formula string
+}
+
+// Registry lists the tools the versions report checks, in display order.
+var Registry = []Spec{
+	{Name: "aws", Command: "aws", Args: []string{"--version"}, Pattern: regexp.MustCompile(`aws-cli/(\S+)`), Aliases: []string{"awscli"}, Formula: "awscli"},
+	{Name: "az", Command: "az", Args: []string{"version"}, Pattern: regexp.MustCompile(`"azure-cli":\s*"([^"]+)"`), Aliases: []string{"azure-cli"}, Formula: "azure-cli"},
+	{Name: "gcloud", Command: "gcloud", Args: []string{"version"}, Pattern: regexp.MustCompile(`Google Cloud SDK (\S+)`), Aliases: []string{"google-cloud-sdk"}, Formula: "google-cloud-sdk"},
+	{Name: "kubectl", Command: "kubectl", Args: []string{"version", "--client"}, Pattern: regexp.MustCompile(`(?:Client Version: |GitVersion:")v?([0-9][^\s"]*)`), Formula: "kubernetes-cli"},
+	{Name: "terraform", Command: "terraform", Args: []string{"version"}, Pattern: regexp.MustCompile(`Terraform v(\S+)`), Formula: "terraform"},
+	{Name: "docker", Command: "docker", Args: []string{"--version"}, Pattern: regexp.MustCompile(`Docker version ([^,\s]+)`), Formula: "docker"},
+	{Name: "node", Command: "node", Args: []string{"--version"}, Pattern: regexp.MustCompile(`v?(\d+\.\d+\.\d+)`), Aliases: []string{"nodejs"}, Formula: "node"},
+	{Name: "python", Command: "python3", Args: []string{"--version"}, Pattern: regexp.MustCompile(`Python (\S+)`), Aliases: []string{"python3"}, Formula: "python"},
+	{Name: "go", Command: "go", Args: []string{"version"}, Pattern: regexp.MustCompile(`go(\d+\.\d+(?:\.\d+)?\S*)`), Aliases: []string{"golang"}, Formula: "go"},
+	{Name: "git", Command: "git", Args: []string{"--version"}, Pattern: regexp.MustCompile(`git version (\S+)`), Formula: "git"},
+}
+
+// Lookup finds a spec by name or alias.
+func Lookup(name string) (Spec, bool) {
+	for _, spec := range Registry {
+		if spec.Matches(name) {
+			return spec, true
+		}
+	}
+	return Spec{}, false
+}
+
+func Names() []string {
+	names := make([]string, 0, len(Registry))
+	for _, spec := range Registry {
+		names = append(names, spec.Name)
+	}
+	return names
+}
+
+func (s Spec) Matches(name string) bool {
+	if name == s.Name {
+		return true
+	}
+	for _, alias := range s.Aliases {
+		if name == alias {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Spec) Requirement() requirements.Check {
+	return requirements.CommandExistsWithBrew(s.Command, s.Formula)
+}
+
+type Result struct {
+	Spec      Spec
+	Installed bool
+	Version   string
+	Err       error
+}
+
+// Version runs the version command, bounded by a timeout. A missing command
+// is reported through Installed rather than Err.
+func (s Spec) Version(ctx context.Context) Result {
+	result := Result{Spec: s}
+	if _, err := exec.LookPath(s.Command); err != nil {
+		return result
+	}
+	result.Installed = true
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.Command, s.Args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+		result.Err = err
+		return result
+	}
+
+	match := s.Pattern.FindStringSubmatch(out.String())
+	if match == nil {
+		line, _, _ := strings.Cut(strings.TrimSpace(out.String()), "\n")
+		result.Err = fmt.Errorf("unrecognised version output %q", line)
+		return result
+	}
+	result.Version = match[1]
+	return result
+}
+
+// Check runs the version command of every spec in parallel and returns the
+// results in the same order.
+func Check(specs []Spec) []Result {
+	results := make([]Result, len(specs))
+	var wg sync.WaitGroup
+	for i, spec := range specs {
+		wg.Add(1)
+		go func(i int, spec Spec) {
+			defer wg.Done()
+			results[i] = spec.Version(context.Background())
+		}(i, spec)
+	}
+	wg.Wait()
+	return results
+}
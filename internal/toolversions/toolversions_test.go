@@ -0,0 +1,93 @@
+package toolversions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{name: "terraform", want: "terraform", ok: true},
+		{name: "awscli", want: "aws", ok: true},
+		{name: "nodejs", want: "node", ok: true},
+		{name: "golang", want: "go", ok: true},
+		{name: "rust", ok: false},
+	}
+	for _, tt := range tests {
+		spec, ok := Lookup(tt.name)
+		if ok != tt.ok || spec.Name != tt.want {
+			t.Errorf("Lookup(%q) = %q, %v, want %q, %v", tt.name, spec.Name, ok, tt.want, tt.ok)
+		}
+	}
+	if names := Names(); len(names) != len(Registry) || names[0] != "aws" {
+		t.Errorf("Names() = %v", names)
+	}
+}
+
+func helperSpec(mode string, pattern string) Spec {
+	return Spec{
+		Name:    "helper",
+		Command: os.Args[0],
+		Args:    []string{"-test.run=TestHelperProcess", "--", mode},
+		Pattern: regexp.MustCompile(pattern),
+	}
+}
+
+func TestVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          Spec
+		wantInstalled bool
+		want          string
+		wantErr       string
+	}{
+		{name: "parsed", spec: helperSpec("version", `helper v(\S+)`), wantInstalled: true, want: "1.4.2"},
+		{name: "unrecognised", spec: helperSpec("version", `other (\S+)`), wantInstalled: true, wantErr: `unrecognised version output "helper v1.4.2"`},
+		{name: "failure", spec: helperSpec("fail", `helper v(\S+)`), wantInstalled: true, wantErr: "exit status 2"},
+		{name: "missing", spec: Spec{Name: "missing", Command: "devtools-missing-command"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TOOLVERSIONS_HELPER", "1")
+			got := tt.spec.Version(context.Background())
+			if got.Installed != tt.wantInstalled || got.Version != tt.want {
+				t.Errorf("Version() = %+v, want installed %v version %q", got, tt.wantInstalled, tt.want)
+			}
+			if tt.wantErr == "" && got.Err != nil || tt.wantErr != "" && (got.Err == nil || !strings.Contains(got.Err.Error(), tt.wantErr)) {
+				t.Errorf("Version() error = %v, want %q", got.Err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckKeepsOrder(t *testing.T) {
+	t.Setenv("TOOLVERSIONS_HELPER", "1")
+	specs := []Spec{helperSpec("version", `helper v(\S+)`), {Name: "missing", Command: "devtools-missing-command"}}
+	results := Check(specs)
+	if len(results) != 2 || results[0].Spec.Name != "helper" || results[0].Version != "1.4.2" || results[1].Installed {
+		t.Errorf("Check() = %+v", results)
+	}
+}
+
+// TestHelperProcess is the command started by the helper specs.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("TOOLVERSIONS_HELPER") == "" {
+		return
+	}
+	switch os.Args[len(os.Args)-1] {
+	case "version":
+		fmt.Println("helper v1.4.2")
+	case "fail":
+		fmt.Fprintln(os.Stderr, "broken")
+		os.Exit(2)
+	}
+	os.Exit(0)
+}